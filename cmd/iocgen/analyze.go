@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tuhuynh27/go-ioc/internal/wire"
+)
+
+var (
+	analyzeCmd = &cobra.Command{
+		Use:   "analyze",
+		Short: "Perform comprehensive component analysis",
+		Run: func(cmd *cobra.Command, args []string) {
+			absDir, components := loadComponents()
+			analyzer := newAnalyzer(components)
+			result := analyzer.PerformComprehensiveAnalysis()
+
+			switch {
+			case analyzeHTML != "":
+				writeHTMLReport(analyzer, analyzeHTML)
+			case reportFormat == "" || reportOut != "":
+				analyzer.PrintAnalysisResult(result)
+			}
+			if reportFormat != "" {
+				writeFindingsReport(absDir, result)
+			}
+
+			if writeBaseline != "" {
+				if err := wire.NewBaseline(result.Findings).WriteBaseline(writeBaseline); err != nil {
+					log.Fatalf("Error writing baseline: %v", err)
+				}
+				log.Printf("Wrote baseline with %d findings to %s", len(result.Findings), writeBaseline)
+			}
+
+			failed := false
+			if baselineFile != "" {
+				baseline, err := wire.LoadBaseline(baselineFile)
+				if err != nil {
+					log.Fatalf("Error loading baseline: %v", err)
+				}
+				fresh := baseline.NewFindings(result.Findings)
+				printNewFindings(fresh, len(result.Findings)-len(fresh))
+				failed = len(fresh) > 0
+			}
+
+			violations := analyzer.CheckThresholds(result.ComponentMetrics, thresholds)
+			if thresholds != (wire.MetricThresholds{}) {
+				wire.WriteThresholdViolations(os.Stdout, violations)
+			}
+			if failed || len(violations) > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+
+	analyzeHTML                 string
+	writeBaseline, baselineFile string
+	thresholds                  wire.MetricThresholds
+)
+
+// printNewFindings reports findings that are not accepted by the baseline
+func printNewFindings(findings []wire.Finding, accepted int) {
+	if len(findings) == 0 {
+		fmt.Printf("\n✅ No new findings (%d accepted by baseline)\n", accepted)
+		return
+	}
+	fmt.Printf("\n🆕 New Findings (%d, %d accepted by baseline):\n", len(findings), accepted)
+	for _, finding := range findings {
+		fmt.Printf("  %s [%s] %s\n", strings.ToUpper(finding.Severity), finding.RuleID, finding.Message)
+		if finding.SourceFile != "" {
+			fmt.Printf("    at %s:%d\n", finding.SourceFile, finding.LineNumber)
+		}
+	}
+}
+
+// writeHTMLReport writes the interactive HTML report to the given file
+func writeHTMLReport(analyzer *wire.DependencyAnalyzer, path string) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("Error creating report file: %v", err)
+	}
+	defer file.Close()
+
+	if err := analyzer.WriteHTMLReport(file); err != nil {
+		log.Fatalf("Error writing HTML report: %v", err)
+	}
+	log.Printf("Wrote HTML analysis report to %s", path)
+}
+
+func init() {
+	analyzeCmd.Flags().StringVar(&analyzeHTML, "html", "", "Write a self-contained interactive HTML report to this file")
+	analyzeCmd.Flags().StringVar(&writeBaseline, "write-baseline", "", "Write all current findings to this baseline file")
+	analyzeCmd.Flags().StringVar(&baselineFile, "baseline", "", "Fail only on findings that are not in this baseline file")
+	analyzeCmd.Flags().IntVar(&thresholds.MaxFanIn, "max-fan-in", 0, "Fail when a component has more dependents than this (0 disables)")
+	analyzeCmd.Flags().IntVar(&thresholds.MaxFanOut, "max-fan-out", 0, "Fail when a component has more dependencies than this (0 disables)")
+	analyzeCmd.Flags().IntVar(&thresholds.MaxDepth, "max-depth", 0, "Fail when a component's dependency depth exceeds this (0 disables)")
+	analyzeCmd.Flags().IntVar(&thresholds.MaxTransitiveDependencies, "max-transitive", 0, "Fail when a component has more transitive dependencies than this (0 disables)")
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/tuhuynh27/go-ioc/internal/wire"
+)
+
+var (
+	checkCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Check that the committed wire_gen.go is up to date",
+		Run: func(cmd *cobra.Command, args []string) {
+			absDir, components := loadComponents()
+
+			if containers := loadContainers(absDir, components); containers != nil {
+				eachContainer(containers, func(c namedContainer) error {
+					return checkGeneratedFile(absDir, c.spec.OutputDir(absDir), c.generator(absDir))
+				})
+				return
+			}
+
+			checkProfileOutput()
+			err := checkGeneratedFile(absDir, outputDir(absDir), newGenerator(absDir, components))
+			if errors.Is(err, errOutOfDate) {
+				os.Exit(1)
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+
+	checkSummary bool
+
+	errOutOfDate = errors.New("generated file is out of date")
+)
+
+// checkGeneratedFile compares the wire_gen.go in outputDir with the code the
+// generator produces. When they differ it prints the drift of the components the
+// generator selected and returns errOutOfDate
+func checkGeneratedFile(absDir, outputDir string, gen *wire.Generator) error {
+	generated, err := gen.GenerateCode()
+	if err != nil {
+		return fmt.Errorf("error generating code: %w", err)
+	}
+
+	path := filepath.Join(outputDir, "wire_gen.go")
+	committed, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading generated file: %w (run iocgen to generate it)", err)
+	}
+
+	if bytes.Equal(committed, generated) {
+		fmt.Printf("✅ %s is up to date\n", path)
+		return nil
+	}
+
+	fmt.Printf("❌ %s is out of date, run iocgen to regenerate it\n", path)
+	printModelDrift(committed, gen.Components())
+	if !checkSummary {
+		relPath, err := filepath.Rel(absDir, path)
+		if err != nil {
+			relPath = path
+		}
+		fmt.Printf("\n%s", wire.UnifiedDiff("a/"+filepath.ToSlash(relPath), "b/"+filepath.ToSlash(relPath), committed, generated))
+	}
+	return errOutOfDate
+}
+
+// printModelDrift explains which component changes caused the generated file to drift,
+// using the model hashes embedded in the committed file header
+func printModelDrift(committed []byte, components []wire.Component) {
+	previousHash, previous, ok := wire.ParseModelHeader(committed)
+	if !ok {
+		fmt.Println("  The committed file has no component model header, it was generated by an older iocgen")
+		return
+	}
+
+	current := wire.ComputeModelHashes(components)
+	if previousHash == current.ModelHash() {
+		fmt.Println("  The component model is unchanged, the file was edited by hand or generated by another iocgen version")
+		return
+	}
+
+	drift := wire.CompareModels(previous, current)
+	for _, key := range drift.Added {
+		fmt.Printf("  + %s (new component)\n", key)
+	}
+	for _, key := range drift.Removed {
+		fmt.Printf("  - %s (removed component)\n", key)
+	}
+	for _, key := range drift.Changed {
+		fmt.Printf("  ~ %s (changed)\n", key)
+	}
+}
+
+func init() {
+	checkCmd.Flags().BoolVar(&checkSummary, "summary", false, "Only report the component changes, without the textual diff")
+}
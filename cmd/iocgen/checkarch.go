@@ -0,0 +1,40 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tuhuynh27/go-ioc/internal/wire"
+)
+
+var (
+	checkArchCmd = &cobra.Command{
+		Use:   "check-arch",
+		Short: "Check autowired dependencies against architecture rules",
+		Run: func(cmd *cobra.Command, args []string) {
+			rules, err := wire.LoadArchRules(archRulesFile)
+			if err != nil {
+				log.Fatalf("Error loading architecture rules: %v", err)
+			}
+
+			_, components := loadComponents()
+
+			violations := wire.NewAnalyzer(components).CheckArchitecture(rules)
+			if err := wire.WriteArchViolations(os.Stdout, violations, archFormat); err != nil {
+				log.Fatalf("Error writing report: %v", err)
+			}
+
+			if len(violations) > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+
+	archRulesFile, archFormat string
+)
+
+func init() {
+	checkArchCmd.Flags().StringVar(&archRulesFile, "rules", ".iocgen-arch.json", "Architecture rule file")
+	checkArchCmd.Flags().StringVar(&archFormat, "format", "text", "Output format: text or json")
+}
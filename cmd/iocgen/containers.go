@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tuhuynh27/go-ioc/internal/wire"
+)
+
+// namedContainer is a container selected for generation together with its components
+type namedContainer struct {
+	spec       wire.ContainerSpec
+	components []wire.Component
+}
+
+// loadContainers returns the named containers declared by the manifest and by
+// EntryPoint markers, restricted to --container. It returns nil when the project
+// has no named containers, in which case one container holds every component
+func loadContainers(absDir string, components []wire.Component) []namedContainer {
+	specs, err := wire.CollectContainers(loadContainerManifest(absDir), components)
+	if err != nil {
+		log.Fatalf("Error collecting containers: %v", err)
+	}
+	if len(specs) == 0 {
+		if len(containerNames) > 0 {
+			log.Fatalf("No named containers are declared, add %s or EntryPoint markers with a container name", wire.DefaultContainerManifest)
+		}
+		return nil
+	}
+
+	if len(roots) > 0 {
+		log.Fatalf("--roots cannot be combined with named containers, declare the roots in %s instead", wire.DefaultContainerManifest)
+	}
+	if len(profiles) > 0 {
+		log.Fatal("--profile cannot be combined with named containers, their generated files would be replaced")
+	}
+
+	specs, err = wire.SelectContainers(specs, containerNames)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var containers []namedContainer
+	for _, spec := range specs {
+		selected, err := wire.ContainerComponents(components, spec)
+		if err != nil {
+			log.Fatal(err)
+		}
+		containers = append(containers, namedContainer{spec: spec, components: selected})
+	}
+	return containers
+}
+
+// loadContainerManifest reads the containers of --manifest, or of the default
+// manifest in absDir if it exists
+func loadContainerManifest(absDir string) []wire.ContainerSpec {
+	manifest := containerManifest
+	if manifest == "" {
+		manifest = filepath.Join(absDir, wire.DefaultContainerManifest)
+	}
+
+	specs, err := wire.LoadContainerManifest(manifest)
+	if err != nil && !(errors.Is(err, fs.ErrNotExist) && containerManifest == "") {
+		log.Fatalf("Error loading container manifest: %v", err)
+	}
+	return specs
+}
+
+// generator returns the generator writing the container into its output package
+func (c namedContainer) generator(absDir string) *wire.Generator {
+	return recordScan(wire.NewGenerator(c.components).ForContainer(absDir, c.spec), c.spec.OutputDir(absDir))
+}
+
+// eachContainer runs fn for every container, reporting failures per container
+// instead of stopping at the first one. It exits with status 1 if any failed
+func eachContainer(containers []namedContainer, fn func(c namedContainer) error) {
+	failed := 0
+	for _, c := range containers {
+		fmt.Printf("\n📦 Container %s (%d components)\n", c.spec.Name, len(c.components))
+		if err := fn(c); err != nil {
+			fmt.Printf("❌ Container %s: %v\n", c.spec.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("\n❌ %d of %d containers failed\n", failed, len(containers))
+		os.Exit(1)
+	}
+}
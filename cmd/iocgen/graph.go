@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tuhuynh27/go-ioc/internal/wire"
+)
+
+var (
+	graphCmd = &cobra.Command{
+		Use:   "graph",
+		Short: "Export the resolved dependency graph (dot, mermaid, plantuml, graphml)",
+		Run: func(cmd *cobra.Command, args []string) {
+			format, err := wire.ParseGraphFormat(graphFormat)
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			if graphGroupBy != "package" && graphGroupBy != "stereotype" {
+				log.Fatalf("Error: unknown grouping %q (supported: package, stereotype)", graphGroupBy)
+			}
+
+			_, components := loadComponents()
+
+			analyzer := wire.NewAnalyzer(components)
+			graph, err := analyzer.BuildGraph(wire.GraphOptions{
+				RootComponent: graphRootComponent,
+				RootPackage:   graphRootPackage,
+				ByStereotype:  graphGroupBy == "stereotype",
+			})
+			if err != nil {
+				log.Fatalf("Error building graph: %v", err)
+			}
+
+			var out io.Writer = os.Stdout
+			if graphOutput != "" {
+				file, err := os.Create(graphOutput)
+				if err != nil {
+					log.Fatalf("Error creating graph file: %v", err)
+				}
+				defer file.Close()
+				out = file
+			}
+
+			if err := graph.Write(out, format); err != nil {
+				log.Fatalf("Error writing graph: %v", err)
+			}
+
+			if graphOutput != "" {
+				log.Printf("Wrote %s graph with %d nodes and %d edges to %s", format, len(graph.Nodes), len(graph.Edges), graphOutput)
+			}
+		},
+	}
+
+	graphFormat, graphOutput             string
+	graphRootComponent, graphRootPackage string
+	graphGroupBy                         string
+)
+
+func init() {
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "dot", "Graph format: dot, mermaid, plantuml or graphml")
+	graphCmd.Flags().StringVar(&graphOutput, "out", "", "Write the graph to this file instead of stdout")
+	graphCmd.Flags().StringVar(&graphRootComponent, "component", "", "Only export the subgraph rooted at this component (e.g. service.NotificationService)")
+	graphCmd.Flags().StringVar(&graphRootPackage, "package", "", "Only export the subgraph rooted at components in this package")
+	graphCmd.Flags().StringVar(&graphGroupBy, "group-by", "package", "Cluster the nodes by package or stereotype, stereotyped nodes are colored by role either way")
+}
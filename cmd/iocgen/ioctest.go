@@ -0,0 +1,67 @@
+package main
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/tuhuynh27/go-ioc/internal/wire"
+)
+
+var (
+	ioctestCmd = &cobra.Command{
+		Use:   "ioctest",
+		Short: "Generate the constructors ioctest.Build wires single components with in tests",
+		Long: `Generate a package registering a reflection-free constructor per component
+with the ioctest package. Import it for its side effects in tests, then build
+one component with its real or faked dependencies:
+
+    svc := ioctest.Build(t, &notification.NotificationService{}, ioctest.Fake[logger.Logger](fake))
+
+The package imports every component, so only external test packages can import
+it. With --in-package, every component package gets an ioctest_gen_test.go
+instead, for tests inside the package.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			absDir, components := loadComponents()
+
+			// Tests inside a component package get constructors generated into the package
+			if ioctestInPackage {
+				config := ""
+				if stereotypeConfig != "" {
+					abs, err := filepath.Abs(stereotypeConfig)
+					if err != nil {
+						log.Fatalf("Error getting absolute path: %v", err)
+					}
+					config = abs
+				}
+				gen := wire.NewGenerator(components).ForInPackageTests(absDir, config).ForProfiles(profiles).ForScan(scanOptions(), "")
+				paths, err := gen.GenerateInPackageTests()
+				if err != nil {
+					log.Fatalf("Error generating test constructors: %v", err)
+				}
+				log.Printf("Successfully generated test constructors into %d packages", len(paths))
+				return
+			}
+
+			outputDir := ioctestOutput
+			if !filepath.IsAbs(outputDir) {
+				outputDir = filepath.Join(absDir, outputDir)
+			}
+			gen := recordScan(wire.NewGenerator(components).ForTestPackage(absDir, outputDir).ForProfiles(profiles), outputDir)
+
+			path, err := gen.GenerateTestPackage()
+			if err != nil {
+				log.Fatalf("Error generating test constructors: %v", err)
+			}
+			log.Printf("Successfully generated test constructors: %s", path)
+		},
+	}
+
+	ioctestOutput    string
+	ioctestInPackage bool
+)
+
+func init() {
+	ioctestCmd.Flags().StringVar(&ioctestOutput, "out", filepath.Join("wire", "wiretest"), "Directory of the generated ioctest_gen.go, relative to --dir")
+	ioctestCmd.Flags().BoolVar(&ioctestInPackage, "in-package", false, "Generate an ioctest_gen_test.go into every component package for tests inside the package")
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
+	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
@@ -19,33 +22,10 @@ var (
 				return
 			}
 
-			// Convert to absolute path
-			absDir, err := filepath.Abs(dir)
-			if err != nil {
-				log.Fatalf("Error getting absolute path: %v", err)
-			}
-
-			log.Printf("Scanning directory: %s", absDir)
+			absDir, components := loadComponents()
 
-			// Parse components
-			components, err := wire.ParseComponents(absDir)
-
-			if err != nil {
-				log.Fatalf("Error parsing components: %v", err)
-			}
-
-			if verbose {
-				for _, comp := range components {
-					log.Printf("Component: %s (package: %s)", comp.Name, comp.Package)
-					log.Printf("- Source: %s:%d", comp.SourceFile, comp.LineNumber)
-					log.Printf("- Qualifier: %s", comp.Qualifier)
-					log.Printf("- Implements: %v", comp.Implements)
-					log.Printf("- Dependencies: %v", comp.Dependencies)
-				}
-			}
-
-			// Create generator
-			gen := wire.NewGenerator(components)
+			// Create generator, narrowed to the components --roots needs
+			gen := newGenerator(absDir, components)
 
 			// Handle special modes
 			if showGraph {
@@ -54,6 +34,22 @@ var (
 			}
 
 			if dryRun {
+				if reportFormat != "" {
+					result := newAnalyzer(components).PerformComprehensiveAnalysis()
+					writeFindingsReport(absDir, result)
+					if hasErrorFindings(result.Findings) {
+						os.Exit(1)
+					}
+					if reportOut == "" {
+						return
+					}
+				}
+				if containers := loadContainers(absDir, components); containers != nil {
+					eachContainer(containers, func(c namedContainer) error {
+						return c.generator(absDir).ValidateOnly()
+					})
+					return
+				}
 				if err := gen.ValidateOnly(); err != nil {
 					log.Fatalf("Validation failed: %v", err)
 				}
@@ -66,12 +62,19 @@ var (
 			}
 
 			if analyzeComponents {
-				analyzer := wire.NewAnalyzer(components)
+				analyzer := newAnalyzer(components)
 				analyzer.PrintAnalysisReport()
 				return
 			}
 
-			// Generate code
+			// Generate code, one wire_gen.go per named container if the project declares them
+			if containers := loadContainers(absDir, components); containers != nil {
+				eachContainer(containers, func(c namedContainer) error {
+					return c.generator(absDir).Generate(absDir)
+				})
+				return
+			}
+			checkProfileOutput()
 			if err := gen.Generate(absDir); err != nil {
 				log.Fatalf("Error generating code: %v", err)
 			}
@@ -80,28 +83,189 @@ var (
 		},
 	}
 
-	dir, output                           string
-	verbose, help                         bool
-	showGraph, dryRun                     bool
-	listComponents, analyzeComponents     bool
+	dir, output                         string
+	verbose, help                       bool
+	showGraph, dryRun                   bool
+	listComponents, analyzeComponents   bool
+	roots                               []string
+	noCache                             bool
+	scanRoots, includes, excludes, tags []string
+	scanModules                         []string
+	containerManifest                   string
+	containerNames                      []string
+	profiles                            []string
+	stereotypeConfig                    string
 )
 
+// defaultOutput is the generated file when --output is not given
+const defaultOutput = "wire/wire_gen.go"
+
+// loadComponents resolves the scan directory and parses all components under it
+func loadComponents() (string, []wire.Component) {
+	// Convert to absolute path
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		log.Fatalf("Error getting absolute path: %v", err)
+	}
+
+	log.Printf("Scanning directory: %s", absDir)
+
+	// Parse components, reusing the results of unchanged files from the scan cache
+	opts := scanOptions()
+	if !noCache {
+		opts.CacheDir = filepath.Join(absDir, ".iocgen", "cache")
+	}
+	components, stats, err := wire.ParseComponentsWithOptions(absDir, opts)
+
+	if err != nil {
+		log.Fatalf("Error parsing components: %v", err)
+	}
+
+	if verbose {
+		log.Printf("Scanned %d files: %d from cache, %d parsed", stats.Files, stats.CacheHits, stats.CacheMisses)
+		for _, comp := range components {
+			log.Printf("Component: %s (package: %s)", comp.Name, comp.Package)
+			log.Printf("- Source: %s:%d", comp.SourceFile, comp.LineNumber)
+			log.Printf("- Qualifier: %s", comp.Qualifier)
+			log.Printf("- Implements: %v", comp.Implements)
+			log.Printf("- Dependencies: %v", comp.Dependencies)
+		}
+	}
+
+	return absDir, components
+}
+
+// scanOptions returns the scan roots, patterns and build tags given on the command line.
+// GOOS and GOARCH are taken from the environment
+func scanOptions() wire.ParseOptions {
+	return wire.ParseOptions{
+		Roots:       scanRoots,
+		Modules:     scanModules,
+		Include:     includes,
+		Exclude:     excludes,
+		Tags:        tags,
+		Profiles:    profiles,
+		Stereotypes: loadStereotypes(),
+	}
+}
+
+// loadStereotypes reads the stereotype markers of --stereotypes, or of the
+// default config in --dir if it exists
+func loadStereotypes() map[string]string {
+	config := stereotypeConfig
+	if config == "" {
+		config = filepath.Join(dir, wire.DefaultStereotypeConfig)
+	}
+
+	stereotypes, err := wire.LoadStereotypeConfig(config)
+	if err != nil && !(errors.Is(err, fs.ErrNotExist) && stereotypeConfig == "") {
+		log.Fatalf("Error loading stereotype config: %v", err)
+	}
+	return stereotypes
+}
+
+// newGenerator creates a generator for the components writing to --output. With
+// --roots, Initialize only builds the roots and their dependencies and returns just the roots
+func newGenerator(absDir string, components []wire.Component) *wire.Generator {
+	gen := wire.NewGenerator(components)
+	if output != defaultOutput {
+		gen.ForOutput(absDir, outputDir(absDir))
+	}
+	gen, err := gen.ForProfiles(profiles).ForRoots(roots)
+	if err != nil {
+		log.Fatalf("Error selecting roots: %v", err)
+	}
+	return recordScan(gen, outputDir(absDir))
+}
+
+// recordScan makes the go:generate directive of the generator writing into
+// outputDir repeat the scan options, so that go generate scans the same files
+func recordScan(gen *wire.Generator, outputDir string) *wire.Generator {
+	config := ""
+	if stereotypeConfig != "" {
+		absConfig, err := filepath.Abs(stereotypeConfig)
+		if err != nil {
+			log.Fatalf("Error getting absolute path: %v", err)
+		}
+		config = filepath.ToSlash(absConfig)
+		if rel, err := filepath.Rel(outputDir, absConfig); err == nil {
+			config = filepath.ToSlash(rel)
+		}
+	}
+	return gen.ForScan(scanOptions(), config)
+}
+
+// outputDir returns the directory of the generated file given by --output
+func outputDir(absDir string) string {
+	if filepath.Base(output) != "wire_gen.go" {
+		log.Fatalf("--output must name a wire_gen.go file, got %s", output)
+	}
+	if filepath.IsAbs(output) {
+		return filepath.Dir(output)
+	}
+	return filepath.Join(absDir, filepath.Dir(output))
+}
+
+// checkProfileOutput stops when a profile is active but the generated file is
+// not moved away from the application's wire_gen.go, which it would replace
+func checkProfileOutput() {
+	if len(profiles) > 0 && output == defaultOutput {
+		log.Fatalf("--profile needs --output for the container of the profile, e.g. --output=internal/%swire/wire_gen.go", profiles[0])
+	}
+}
+
+// newAnalyzer creates an analyzer for the components with the roots given by --roots
+func newAnalyzer(components []wire.Component) *wire.DependencyAnalyzer {
+	analyzer := wire.NewAnalyzer(components)
+	if err := analyzer.SetRoots(roots); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	return analyzer
+}
+
 func main() {
 	rootCmd.PersistentFlags().StringVarP(&dir, "dir", "d", ".", "Directory to scan for components")
-	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "wire/wire_gen.go", "Output file for generated code")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", defaultOutput, "Output file for generated code, relative to --dir")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
 	rootCmd.PersistentFlags().BoolVarP(&help, "help", "h", false, "Show help message")
 	rootCmd.PersistentFlags().BoolVar(&showGraph, "graph", false, "Show dependency graph visualization")
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Validate components without generating files")
 	rootCmd.PersistentFlags().BoolVar(&listComponents, "list", false, "List all discovered components")
 	rootCmd.PersistentFlags().BoolVar(&analyzeComponents, "analyze", false, "Perform comprehensive component analysis")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Parse every file instead of reusing the scan cache in .iocgen/cache")
+	rootCmd.PersistentFlags().StringSliceVar(&scanRoots, "scan", nil, "Directories to scan, relative to --dir (default: the whole directory)")
+	rootCmd.PersistentFlags().StringSliceVar(&scanModules, "scan-module", nil, "Also scan packages of required modules from the module cache or vendor/, e.g. 'github.com/acme/platform/...'")
+	rootCmd.PersistentFlags().StringSliceVar(&includes, "include", nil, "Only scan files matching these glob patterns, e.g. 'internal/**'")
+	rootCmd.PersistentFlags().StringSliceVar(&excludes, "exclude", nil, "Skip files and directories matching these glob patterns, e.g. '*_mock.go'")
+	rootCmd.PersistentFlags().StringSliceVar(&tags, "tags", nil, "Build tags to satisfy when evaluating build constraints")
+	rootCmd.PersistentFlags().StringSliceVar(&roots, "roots", nil, "Components treated as application roots in addition to EntryPoint markers, generation then only builds these roots")
+	rootCmd.PersistentFlags().StringVar(&containerManifest, "manifest", "", "Manifest declaring named containers (default: "+wire.DefaultContainerManifest+" in --dir, if present)")
+	rootCmd.PersistentFlags().StringSliceVar(&containerNames, "container", nil, "Only generate or check these named containers")
+	rootCmd.PersistentFlags().StringSliceVar(&profiles, "profile", nil, "Activate components with these Profile markers, e.g. 'test' for the fakes of iocgen mocks")
+	rootCmd.PersistentFlags().StringVar(&stereotypeConfig, "stereotypes", "", "Config declaring stereotype markers that count as Component (default: "+wire.DefaultStereotypeConfig+" in --dir, if present)")
+	rootCmd.PersistentFlags().StringVar(&reportFormat, "report-format", "", "Write findings as a report for CI: sarif, junit or checkstyle")
+	rootCmd.PersistentFlags().StringVar(&reportOut, "report-out", "", "Write the report to this file instead of stdout")
+
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(whyCmd)
+	rootCmd.AddCommand(depsCmd)
+	rootCmd.AddCommand(impactCmd)
+	rootCmd.AddCommand(checkArchCmd)
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(mocksCmd)
+	rootCmd.AddCommand(ioctestCmd)
 
 	printBanner()
-	rootCmd.Execute()
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
 
+// printBanner writes the banner to stderr so that machine readable output on stdout stays clean
 func printBanner() {
-	fmt.Println(`
+	fmt.Fprintln(os.Stderr, `
    ______      _____ ____  ______
   / ____/___  /  _/ / __ \/ ____/
  / / __/ __ \ / // / / / / /     
@@ -109,5 +273,5 @@ func printBanner() {
 \____/\____/___/_/\____/\____/   
                                  
 Inversion of Control for Go
-Version 0.0.0`)
+Version `+wire.Version)
 }
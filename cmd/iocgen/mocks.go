@@ -0,0 +1,42 @@
+package main
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/tuhuynh27/go-ioc/internal/wire"
+)
+
+var (
+	mocksCmd = &cobra.Command{
+		Use:   "mocks",
+		Short: "Generate call-recording fakes of the interfaces components implement",
+		Long: `Generate call-recording fakes of every interface named in an implements tag.
+
+Each fake records its calls in <Method>Calls and returns the results of the
+<Method>Func fields, or zero values when they are not set. The fakes are
+components of the mock profile implementing their interface with the qualifiers
+of the real implementations, so a test container generated with
+--profile=test --output=<dir>/wire_gen.go injects them instead.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			absDir, components := loadComponents()
+
+			path, err := wire.GenerateMocks(absDir, components, wire.MockOptions{
+				Scope:     scanOptions(),
+				OutputDir: mocksOutput,
+				Profile:   mockProfile,
+			})
+			if err != nil {
+				log.Fatalf("Error generating mocks: %v", err)
+			}
+			log.Printf("Successfully generated mocks: %s", path)
+		},
+	}
+
+	mocksOutput, mockProfile string
+)
+
+func init() {
+	mocksCmd.Flags().StringVar(&mocksOutput, "out", "mocks", "Directory of the generated mocks_gen.go, relative to --dir")
+	mocksCmd.Flags().StringVar(&mockProfile, "mock-profile", wire.DefaultMockProfile, "Profile the fakes are active in")
+}
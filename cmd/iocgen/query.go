@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tuhuynh27/go-ioc/internal/wire"
+)
+
+var (
+	whyCmd = &cobra.Command{
+		Use:   "why <from> <to>",
+		Short: "Print every dependency path from one component to another",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			_, components := loadComponents()
+
+			paths, err := wire.NewAnalyzer(components).FindPaths(args[0], args[1])
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+
+			if len(paths) == 0 {
+				fmt.Printf("%s does not depend on %s\n", args[0], args[1])
+				return
+			}
+
+			fmt.Printf("%d dependency path(s) from %s to %s:\n\n", len(paths), args[0], args[1])
+			for i, path := range paths {
+				fmt.Printf("%d. %s\n", i+1, path.String())
+			}
+		},
+	}
+
+	depsCmd = &cobra.Command{
+		Use:   "deps <component>",
+		Short: "Print the dependencies of a component, or its dependents with --reverse",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			_, components := loadComponents()
+
+			related, err := wire.NewAnalyzer(components).FindRelated(args[0], depsReverse, depsTransitive)
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+
+			direction := "Dependencies of"
+			if depsReverse {
+				direction = "Components depending on"
+			}
+			scope := "direct"
+			if depsTransitive {
+				scope = "transitive"
+			}
+			fmt.Printf("%s %s (%s, %d found):\n", direction, args[0], scope, len(related))
+
+			for _, rel := range related {
+				fmt.Printf("  [%d] %s.%s", rel.Distance, rel.Component.Package, rel.Component.Type)
+				if len(rel.Via) > 0 {
+					fmt.Printf(" via %s", strings.Join(rel.Via, ", "))
+				}
+				fmt.Printf(" [%s:%d]\n", rel.Component.SourceFile, rel.Component.LineNumber)
+			}
+		},
+	}
+
+	impactCmd = &cobra.Command{
+		Use:   "impact",
+		Short: "Map changed files to the components that must be re-tested",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(impactFiles) == 0 {
+				log.Fatalf("Error: --files is required")
+			}
+
+			absDir, components := loadComponents()
+
+			var files []string
+			for _, file := range impactFiles {
+				files = append(files, resolveChangedFile(absDir, file))
+			}
+
+			impacted := wire.NewAnalyzer(components).ImpactedComponents(files)
+			fmt.Printf("%d component(s) affected by %d changed file(s):\n", len(impacted), len(files))
+			for _, imp := range impacted {
+				fmt.Printf("  - %s.%s (%s: %s)\n", imp.Component.Package, imp.Component.Type, imp.Reason, imp.Cause)
+			}
+		},
+	}
+
+	depsReverse, depsTransitive bool
+	impactFiles                 []string
+)
+
+// resolveChangedFile turns a changed file argument into an absolute path, trying the
+// working directory first and the scan directory second
+func resolveChangedFile(absDir, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		if _, err := os.Stat(abs); err == nil {
+			return abs
+		}
+	}
+	return filepath.Join(absDir, file)
+}
+
+func init() {
+	depsCmd.Flags().BoolVar(&depsReverse, "reverse", false, "Print components that depend on the given component")
+	depsCmd.Flags().BoolVar(&depsTransitive, "transitive", false, "Include indirect dependencies")
+	impactCmd.Flags().StringSliceVar(&impactFiles, "files", nil, "Comma separated list of changed files")
+}
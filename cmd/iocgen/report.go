@@ -0,0 +1,51 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/tuhuynh27/go-ioc/internal/wire"
+)
+
+var reportFormat, reportOut string
+
+// writeFindingsReport writes the analysis findings in the requested report format
+// to --report-out, or to stdout when no output file is given
+func writeFindingsReport(absDir string, result *wire.AnalysisResult) {
+	format, err := wire.ParseReportFormat(reportFormat)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	reporter, err := wire.NewReporter(format, absDir)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if reportOut != "" {
+		file, err := os.Create(reportOut)
+		if err != nil {
+			log.Fatalf("Error creating report file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := reporter.Report(out, result.Findings, result.SuppressedFindings); err != nil {
+		log.Fatalf("Error writing %s report: %v", format, err)
+	}
+	if reportOut != "" {
+		log.Printf("Wrote %s report with %d findings to %s", format, len(result.Findings), reportOut)
+	}
+}
+
+// hasErrorFindings reports whether any finding would break code generation
+func hasErrorFindings(findings []wire.Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == "error" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tuhuynh27/go-ioc/internal/wire"
+)
+
+var (
+	watchCmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Regenerate wire_gen.go whenever components change",
+		Run: func(cmd *cobra.Command, args []string) {
+			absDir, err := filepath.Abs(dir)
+			if err != nil {
+				log.Fatalf("Error getting absolute path: %v", err)
+			}
+
+			// Containers named only by EntryPoint markers are picked up on every rebuild
+			containers := loadContainerManifest(absDir)
+			checkProfileOutput()
+
+			config := ""
+			if stereotypeConfig != "" {
+				if config, err = filepath.Abs(stereotypeConfig); err != nil {
+					log.Fatalf("Error getting absolute path: %v", err)
+				}
+			}
+
+			watcher, err := wire.NewWatcher(absDir, wire.WatchOptions{
+				Interval:   watchInterval,
+				Debounce:   watchDebounce,
+				Output:     os.Stdout,
+				Scope:      scanOptions(),
+				Containers: containers,
+				Select:     containerNames,
+				Roots:      roots,
+				OutputDir:  outputDir(absDir),
+
+				StereotypeConfig: config,
+			})
+			if err != nil {
+				log.Fatalf("Error starting watcher: %v", err)
+			}
+
+			stop := make(chan struct{})
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			go func() {
+				<-signals
+				close(stop)
+			}()
+
+			if err := watcher.Run(stop); err != nil {
+				log.Fatalf("Error watching %s: %v", absDir, err)
+			}
+		},
+	}
+
+	watchInterval, watchDebounce time.Duration
+)
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 500*time.Millisecond, "How often to poll for changed .go files")
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", 300*time.Millisecond, "Wait this long after the last change before regenerating")
+}
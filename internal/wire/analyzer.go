@@ -9,24 +9,36 @@ import (
 // DependencyAnalyzer provides advanced analysis capabilities for IoC components
 type DependencyAnalyzer struct {
 	components []Component
+	index      *componentIndex // Resolution index shared by all analyses
+	roots      []string        // Keys of components configured as roots in addition to EntryPoint markers
 }
 
 // NewAnalyzer creates a new DependencyAnalyzer instance
 func NewAnalyzer(components []Component) *DependencyAnalyzer {
-	return &DependencyAnalyzer{components: components}
+	return &DependencyAnalyzer{components: components, index: newComponentIndex(components)}
 }
 
 // AnalysisResult contains comprehensive analysis results
 type AnalysisResult struct {
-	TotalComponents       int
-	TotalDependencies     int
-	CircularDependencies  []CircularDependency
-	UnusedComponents      []Component
-	OrphanedComponents    []Component
-	InterfaceAnalysis     InterfaceAnalysis
-	QualifierConflicts    []QualifierConflict
-	DependencyDepth       map[string]int
-	ComponentsByPackage   map[string][]Component
+	TotalComponents      int
+	TotalDependencies    int
+	CircularDependencies []CircularDependency
+	UnusedComponents     []Component
+	OrphanedComponents   []Component
+	InterfaceAnalysis    InterfaceAnalysis
+	QualifierConflicts   []QualifierConflict
+	DependencyDepth      map[string]int
+	ComponentsByPackage  map[string][]Component
+	Stereotypes          map[string][]Component // Components with a stereotype marker grouped by role
+	ComponentMetrics     map[string]ComponentMetrics
+	PackageMetrics       map[string]PackageMetrics
+	Findings             []Finding             // Active findings with stable rule IDs
+	SuppressedFindings   []Finding             // Findings suppressed by //ioc:ignore directives or ignore tags
+	EntryPoints          []Component           // Roots of the reachability analysis, empty when unused detection is dependency based
+	UnreachableSubgraphs []UnreachableSubgraph // Connected groups of unused components, only set with entry points
+	RemovablePackages    []string              // Packages without any reachable component, only set with entry points
+	ModuleGraph          []ModuleEdge          // Dependencies between container modules, only set with modules
+	PrivateAccesses      []PrivateAccess       // Dependencies on components another module does not export
 }
 
 // CircularDependency represents a detected circular dependency
@@ -57,14 +69,27 @@ func (a *DependencyAnalyzer) PerformComprehensiveAnalysis() *AnalysisResult {
 		TotalComponents:     len(a.components),
 		TotalDependencies:   a.countTotalDependencies(),
 		ComponentsByPackage: a.groupComponentsByPackage(),
+		Stereotypes:         a.groupComponentsByStereotype(),
 	}
 
 	result.CircularDependencies = a.FindCircularDependencies()
 	result.UnusedComponents = a.FindUnusedComponents()
+	if a.HasRoots() {
+		result.EntryPoints = a.EntryPoints()
+		result.UnreachableSubgraphs = a.FindUnreachableSubgraphs(result.UnusedComponents)
+		result.RemovablePackages = a.FindRemovablePackages(result.UnusedComponents)
+	}
+	if a.HasModules() {
+		result.ModuleGraph = a.BuildModuleGraph()
+		result.PrivateAccesses = a.FindPrivateAccesses()
+	}
 	result.OrphanedComponents = a.FindOrphanedComponents()
 	result.InterfaceAnalysis = a.AnalyzeInterfaces()
 	result.QualifierConflicts = a.FindQualifierConflicts()
 	result.DependencyDepth = a.CalculateDependencyDepth()
+	result.ComponentMetrics = a.CalculateComponentMetrics()
+	result.PackageMetrics = a.CalculatePackageMetrics()
+	result.Findings, result.SuppressedFindings = a.collectFindings(result)
 
 	return result
 }
@@ -99,7 +124,7 @@ func (a *DependencyAnalyzer) dfsCircular(comp Component, visited, recStack map[s
 
 	// Check dependencies
 	for _, dep := range comp.Dependencies {
-		depComponents := a.findDependencyComponents(dep)
+		depComponents := a.injectedComponents(dep)
 		for _, depComp := range depComponents {
 			depKey := depComp.Package + "." + depComp.Type
 			
@@ -128,15 +153,20 @@ func (a *DependencyAnalyzer) dfsCircular(comp Component, visited, recStack map[s
 	return nil
 }
 
-// FindUnusedComponents identifies components that are not dependencies of any other component
+// FindUnusedComponents identifies components that are not dependencies of any other component.
+// When entry points or roots are configured, it returns the components not reachable from them instead
 func (a *DependencyAnalyzer) FindUnusedComponents() []Component {
+	if a.HasRoots() {
+		return a.findUnreachableComponents()
+	}
+
 	used := make(map[string]bool)
 	
 	// Mark components that are dependencies of others
 	for _, comp := range a.components {
 		for _, dep := range comp.Dependencies {
-			// Find all components that satisfy this dependency
-			satisfyingComponents := a.findDependencyComponents(dep)
+			// Find the components injected for this dependency
+			satisfyingComponents := a.injectedComponents(dep)
 			for _, satisfying := range satisfyingComponents {
 				compKey := satisfying.Package + "." + satisfying.Type
 				used[compKey] = true
@@ -242,7 +272,7 @@ func (a *DependencyAnalyzer) FindQualifierConflicts() []QualifierConflict {
 	// Find conflicts
 	for iface, qualifiers := range qualifierMap {
 		for qualifier, components := range qualifiers {
-			if len(components) > 1 {
+			if len(components) > 1 && !a.index.singlePrimary(components) {
 				severity := "ERROR" // Multiple components with same qualifier
 				if qualifier == "" {
 					severity = "WARNING" // Empty qualifier with multiple implementations
@@ -283,7 +313,7 @@ func (a *DependencyAnalyzer) CalculateDependencyDepth() map[string]int {
 		
 		maxDepth := 0
 		for _, dep := range comp.Dependencies {
-			depComponents := a.findDependencyComponents(dep)
+			depComponents := a.injectedComponents(dep)
 			for _, depComp := range depComponents {
 				depDepth := calculateDepth(depComp)
 				if depDepth == -1 {
@@ -318,6 +348,17 @@ func (a *DependencyAnalyzer) groupComponentsByPackage() map[string][]Component {
 	return groups
 }
 
+// groupComponentsByStereotype groups the components with a stereotype by role
+func (a *DependencyAnalyzer) groupComponentsByStereotype() map[string][]Component {
+	groups := make(map[string][]Component)
+	for _, comp := range a.components {
+		if comp.Stereotype != "" {
+			groups[comp.Stereotype] = append(groups[comp.Stereotype], comp)
+		}
+	}
+	return groups
+}
+
 // countTotalDependencies counts all dependencies across components
 func (a *DependencyAnalyzer) countTotalDependencies() int {
 	total := 0
@@ -329,33 +370,32 @@ func (a *DependencyAnalyzer) countTotalDependencies() int {
 
 // findDependencyComponents finds all components that satisfy a given dependency
 func (a *DependencyAnalyzer) findDependencyComponents(dep Dependency) []Component {
-	var matches []Component
-	
-	for _, comp := range a.components {
-		// Direct type match - check both full package.Type and just Type
-		compKey := comp.Package + "." + comp.Type
-		if (comp.Type == dep.Type || compKey == dep.Type) && comp.Qualifier == dep.Qualifier {
-			matches = append(matches, comp)
-			continue
-		}
-		
-		// Interface implementation match
-		if strings.Contains(dep.Type, ".") {
-			depParts := strings.Split(dep.Type, ".")
-			depInterface := depParts[len(depParts)-1] // Get the interface name
-			
-			for _, iface := range comp.Implements {
-				ifaceParts := strings.Split(iface, "/")
-				ifaceName := ifaceParts[len(ifaceParts)-1]
-				if ifaceName == depInterface && comp.Qualifier == dep.Qualifier {
-					matches = append(matches, comp)
-					break
-				}
-			}
-		}
+	return a.index.resolve(dep)
+}
+
+// injectedComponents returns the components the generator wires for a dependency,
+// which is only the Primary one when a Primary settles the choice
+func (a *DependencyAnalyzer) injectedComponents(dep Dependency) []Component {
+	return a.index.injected(dep)
+}
+
+// splitTypeRef splits a type reference into its package base name and type name.
+// It accepts "pkg.Type", "example.com/x/pkg.Type" and "example.com/x/pkg/Type"
+func splitTypeRef(ref string) (string, string) {
+	slash := strings.LastIndex(ref, "/")
+	last := ref[slash+1:]
+	if dot := strings.LastIndex(last, "."); dot != -1 {
+		return last[:dot], last[dot+1:]
 	}
-	
-	return matches
+	if slash == -1 {
+		return "", last
+	}
+	return packageBase(ref[:slash]), last
+}
+
+// packageBase returns the last element of a package path
+func packageBase(pkg string) string {
+	return pkg[strings.LastIndex(pkg, "/")+1:]
 }
 
 // findImplementationsForDependency finds components that implement an interface dependency
@@ -381,7 +421,11 @@ func (a *DependencyAnalyzer) findImplementationsForDependency(dep Dependency) []
 
 // PrintAnalysisReport prints a comprehensive analysis report
 func (a *DependencyAnalyzer) PrintAnalysisReport() {
-	analysis := a.PerformComprehensiveAnalysis()
+	a.PrintAnalysisResult(a.PerformComprehensiveAnalysis())
+}
+
+// PrintAnalysisResult prints a previously computed analysis result
+func (a *DependencyAnalyzer) PrintAnalysisResult(analysis *AnalysisResult) {
 	
 	fmt.Println("📊 Component Analysis Report")
 	fmt.Println("============================")
@@ -404,6 +448,19 @@ func (a *DependencyAnalyzer) PrintAnalysisReport() {
 		components := analysis.ComponentsByPackage[pkg]
 		fmt.Printf("  %s: %d components\n", pkg, len(components))
 	}
+
+	// Stereotype breakdown
+	if len(analysis.Stereotypes) > 0 {
+		fmt.Printf("\n🏷️  Components by Stereotype:\n")
+		var roles []string
+		for role := range analysis.Stereotypes {
+			roles = append(roles, role)
+		}
+		sort.Strings(roles)
+		for _, role := range roles {
+			fmt.Printf("  %s: %d components\n", role, len(analysis.Stereotypes[role]))
+		}
+	}
 	
 	// Circular dependencies
 	if len(analysis.CircularDependencies) > 0 {
@@ -416,9 +473,21 @@ func (a *DependencyAnalyzer) PrintAnalysisReport() {
 	}
 	
 	// Unused components
-	if len(analysis.UnusedComponents) > 0 {
-		fmt.Printf("\n🗑️  Unused Components (%d found):\n", len(analysis.UnusedComponents))
-		for _, comp := range analysis.UnusedComponents {
+	suppressed := make(map[string]bool)
+	for _, finding := range analysis.SuppressedFindings {
+		suppressed[finding.Fingerprint] = true
+	}
+	var unused []Component
+	for _, comp := range analysis.UnusedComponents {
+		if !suppressed[RuleUnusedComponent+":"+componentKey(comp)] {
+			unused = append(unused, comp)
+		}
+	}
+	if len(analysis.EntryPoints) > 0 {
+		printReachability(analysis, unused)
+	} else if len(unused) > 0 {
+		fmt.Printf("\n🗑️  Unused Components (%d found):\n", len(unused))
+		for _, comp := range unused {
 			fmt.Printf("  - %s.%s [%s:%d]\n", comp.Package, comp.Type, comp.SourceFile, comp.LineNumber)
 		}
 	} else {
@@ -455,9 +524,15 @@ func (a *DependencyAnalyzer) PrintAnalysisReport() {
 	}
 	
 	// Qualifier conflicts
-	if len(analysis.QualifierConflicts) > 0 {
-		fmt.Printf("\n⚠️  Qualifier Conflicts (%d found):\n", len(analysis.QualifierConflicts))
-		for _, conflict := range analysis.QualifierConflicts {
+	var conflicts []QualifierConflict
+	for _, conflict := range analysis.QualifierConflicts {
+		if !suppressed[RuleQualifierConflict+":"+conflict.Interface+":"+conflict.Qualifier] {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	if len(conflicts) > 0 {
+		fmt.Printf("\n⚠️  Qualifier Conflicts (%d found):\n", len(conflicts))
+		for _, conflict := range conflicts {
 			fmt.Printf("  %s - Interface: %s, Qualifier: '%s'\n", 
 				conflict.Severity, conflict.Interface, conflict.Qualifier)
 			fmt.Printf("    Conflicting: %s\n", strings.Join(conflict.Conflicting, ", "))
@@ -489,4 +564,99 @@ func (a *DependencyAnalyzer) PrintAnalysisReport() {
 			fmt.Printf("  Depth %d: %d components\n", depth, len(components))
 		}
 	}
-}
\ No newline at end of file
+
+	// Container modules
+	if a.HasModules() {
+		printModules(a, analysis)
+	}
+
+	// Suppressed findings
+	if len(analysis.SuppressedFindings) > 0 {
+		fmt.Printf("\n🔕 Suppressed Findings (%d):\n", len(analysis.SuppressedFindings))
+		for _, finding := range analysis.SuppressedFindings {
+			fmt.Printf("  - [%s] %s: %s\n", finding.RuleID, finding.Component, finding.SuppressionReason)
+		}
+	}
+
+	// Quality metrics
+	printMetrics(analysis)
+}
+
+// printReachability prints the unreachable components grouped into subgraphs and
+// the packages that could be removed
+func printReachability(analysis *AnalysisResult, unreachable []Component) {
+	fmt.Printf("\n🚪 Entry Points (%d):\n", len(analysis.EntryPoints))
+	for _, comp := range analysis.EntryPoints {
+		fmt.Printf("  - %s.%s [%s:%d]\n", comp.Package, comp.Type, comp.SourceFile, comp.LineNumber)
+	}
+
+	if len(unreachable) == 0 {
+		fmt.Printf("\n✅ All components are reachable from the entry points\n")
+		return
+	}
+
+	reported := make(map[string]bool)
+	for _, comp := range unreachable {
+		reported[componentKey(comp)] = true
+	}
+	fmt.Printf("\n🗑️  Unreachable Components (%d found):\n", len(unreachable))
+	for i, subgraph := range analysis.UnreachableSubgraphs {
+		var members []Component
+		for _, comp := range subgraph.Components {
+			if reported[componentKey(comp)] {
+				members = append(members, comp)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		fmt.Printf("  Subgraph %d (%d components in %s):\n", i+1, len(members), strings.Join(subgraph.Packages, ", "))
+		for _, comp := range members {
+			fmt.Printf("    - %s.%s [%s:%d]\n", comp.Package, comp.Type, comp.SourceFile, comp.LineNumber)
+		}
+	}
+
+	if len(analysis.RemovablePackages) > 0 {
+		fmt.Printf("\n📦 Packages without reachable components (removal candidates):\n")
+		for _, pkg := range analysis.RemovablePackages {
+			fmt.Printf("  - %s\n", pkg)
+		}
+	}
+}
+
+// printMetrics prints the components with the highest coupling and the package metrics
+func printMetrics(analysis *AnalysisResult) {
+	fmt.Printf("\n📐 Component Metrics (top fan-out):\n")
+	keys := make([]string, 0, len(analysis.ComponentMetrics))
+	for key := range analysis.ComponentMetrics {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		mi, mj := analysis.ComponentMetrics[keys[i]], analysis.ComponentMetrics[keys[j]]
+		if mi.FanOut != mj.FanOut {
+			return mi.FanOut > mj.FanOut
+		}
+		return keys[i] < keys[j]
+	})
+	for i, key := range keys {
+		if i == 10 {
+			fmt.Printf("  ... %d more\n", len(keys)-i)
+			break
+		}
+		m := analysis.ComponentMetrics[key]
+		fmt.Printf("  %s: fan-in %d, fan-out %d, transitive %d, depth %d\n",
+			key, m.FanIn, m.FanOut, m.TransitiveDependencies, m.Depth)
+	}
+
+	fmt.Printf("\n📦 Package Metrics:\n")
+	var packages []string
+	for pkg := range analysis.PackageMetrics {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+	for _, pkg := range packages {
+		m := analysis.PackageMetrics[pkg]
+		fmt.Printf("  %s: Ca=%d Ce=%d I=%.2f A=%.2f D=%.2f\n",
+			pkg, m.AfferentCoupling, m.EfferentCoupling, m.Instability, m.Abstractness, m.DistanceFromMainSeq)
+	}
+}
@@ -0,0 +1,257 @@
+package wire
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ArchRuleSet is the content of an architecture rule file
+type ArchRuleSet struct {
+	Rules []ArchRule `json:"rules"`
+}
+
+// ArchRule restricts the direction of dependencies between packages or
+// between stereotypes.
+//
+// Package patterns use path.Match syntax and are matched against the full import
+// path as well as every trailing part of it, so "*/handler" matches
+// "example.com/app/handler". A trailing "/..." matches a package and all of its
+// sub-packages. Stereotypes are matched by role, like "controller".
+type ArchRule struct {
+	Name             string   `json:"name"`                       // Rule identifier used in reports
+	Description      string   `json:"description,omitempty"`      // Human readable explanation
+	From             string   `json:"from,omitempty"`             // Packages the rule applies to
+	FromStereotype   string   `json:"fromStereotype,omitempty"`   // Stereotype of the components the rule applies to
+	Allow            []string `json:"allow,omitempty"`            // If set, only these packages (and the own package) may be depended on
+	Deny             []string `json:"deny,omitempty"`             // Packages that must never be depended on
+	AllowStereotypes []string `json:"allowStereotypes,omitempty"` // If set, only components with these stereotypes may be depended on
+	DenyStereotypes  []string `json:"denyStereotypes,omitempty"`  // Stereotypes that must never be depended on
+}
+
+// ArchViolation is a single autowired dependency that breaks an architecture rule
+type ArchViolation struct {
+	Rule          string `json:"rule"`
+	Message       string `json:"message"`
+	Component     string `json:"component"`
+	FromPackage   string `json:"fromPackage"`
+	ToPackage     string `json:"toPackage"`
+	Target        string `json:"target"`
+	ToStereotype  string `json:"toStereotype,omitempty"`
+	FieldName     string `json:"field"`
+	DependsOnType string `json:"type"`
+	SourceFile    string `json:"file"`
+	LineNumber    int    `json:"line"`
+	Column        int    `json:"column,omitempty"`
+}
+
+// LoadArchRules reads and validates an architecture rule file
+func LoadArchRules(file string) (*ArchRuleSet, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule file: %w", err)
+	}
+
+	var rules ArchRuleSet
+	if err := json.Unmarshal(content, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rule file %s: %w", file, err)
+	}
+
+	for i, rule := range rules.Rules {
+		if rule.From == "" && rule.FromStereotype == "" {
+			return nil, fmt.Errorf("rule %d (%s) has no 'from' pattern or 'fromStereotype'", i+1, rule.Name)
+		}
+		if len(rule.Allow) == 0 && len(rule.Deny) == 0 && len(rule.AllowStereotypes) == 0 && len(rule.DenyStereotypes) == 0 {
+			return nil, fmt.Errorf("rule %d (%s) needs at least one 'allow' or 'deny' pattern or stereotype", i+1, rule.Name)
+		}
+		for _, pattern := range append(append([]string{rule.From}, rule.Allow...), rule.Deny...) {
+			if _, err := path.Match(strings.TrimSuffix(pattern, "/..."), ""); err != nil {
+				return nil, fmt.Errorf("rule %d (%s) has invalid pattern %q: %w", i+1, rule.Name, pattern, err)
+			}
+		}
+		if rule.Name == "" {
+			rules.Rules[i].Name = fmt.Sprintf("rule-%d", i+1)
+		}
+	}
+
+	return &rules, nil
+}
+
+// CheckArchitecture evaluates the rules against every resolved autowired dependency,
+// following the implementation that is injected for it
+func (a *DependencyAnalyzer) CheckArchitecture(rules *ArchRuleSet) []ArchViolation {
+	var violations []ArchViolation
+
+	for _, comp := range a.components {
+		for _, dep := range comp.Dependencies {
+			targets := a.findDependencyComponents(dep)
+			if len(targets) == 0 {
+				continue
+			}
+			target := targets[0]
+			toPkg := dependencyPackage(dep, target)
+
+			for _, rule := range rules.Rules {
+				if rule.From != "" && !matchPackagePattern(rule.From, comp.Package) {
+					continue
+				}
+				if rule.FromStereotype != "" && rule.FromStereotype != comp.Stereotype {
+					continue
+				}
+
+				from, to, reason := comp.Package, toPkg, ""
+				if toPkg != comp.Package {
+					if pattern := firstMatchingPattern(rule.Deny, toPkg); pattern != "" {
+						reason = fmt.Sprintf("dependency on %s is denied", pattern)
+					} else if len(rule.Allow) > 0 && firstMatchingPattern(rule.Allow, toPkg) == "" {
+						reason = fmt.Sprintf("only %s may be depended on", strings.Join(rule.Allow, ", "))
+					}
+				}
+				if reason == "" {
+					from, to = stereotypeLabel(comp), stereotypeLabel(target)
+					if slices.Contains(rule.DenyStereotypes, target.Stereotype) {
+						reason = fmt.Sprintf("dependency on %s is denied", target.Stereotype)
+					} else if len(rule.AllowStereotypes) > 0 && !slices.Contains(rule.AllowStereotypes, target.Stereotype) {
+						reason = fmt.Sprintf("only %s may be depended on", strings.Join(rule.AllowStereotypes, ", "))
+					}
+				}
+				if reason == "" {
+					continue
+				}
+
+				violations = append(violations, ArchViolation{
+					Rule:          rule.Name,
+					Message:       fmt.Sprintf("%s must not depend on %s: %s", from, to, reason),
+					Component:     componentKey(comp),
+					FromPackage:   comp.Package,
+					ToPackage:     toPkg,
+					Target:        componentKey(target),
+					ToStereotype:  target.Stereotype,
+					FieldName:     dep.FieldName,
+					DependsOnType: dep.Type,
+					SourceFile:    comp.SourceFile,
+					LineNumber:    dep.LineNumber,
+					Column:        dep.Column,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].SourceFile != violations[j].SourceFile {
+			return violations[i].SourceFile < violations[j].SourceFile
+		}
+		return violations[i].LineNumber < violations[j].LineNumber
+	})
+	return violations
+}
+
+// WriteArchViolations writes violations as human readable text or as JSON
+func WriteArchViolations(w io.Writer, violations []ArchViolation, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if violations == nil {
+			violations = []ArchViolation{}
+		}
+		return encoder.Encode(struct {
+			Violations []ArchViolation `json:"violations"`
+		}{violations})
+	case "text", "":
+		if len(violations) == 0 {
+			_, err := fmt.Fprintln(w, "✅ No architecture violations found")
+			return err
+		}
+		fmt.Fprintf(w, "❌ Architecture violations (%d found):\n", len(violations))
+		for _, v := range violations {
+			fmt.Fprintf(w, "\n  %s:%d [%s]\n", v.SourceFile, v.LineNumber, v.Rule)
+			fmt.Fprintf(w, "    %s.%s (%s) -> %s\n", v.Component, v.FieldName, v.DependsOnType, v.Target)
+			fmt.Fprintf(w, "    %s\n", v.Message)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported output format %q (supported: text, json)", format)
+}
+
+// stereotypeLabel describes a component by its stereotype in violation messages
+func stereotypeLabel(comp Component) string {
+	if comp.Stereotype == "" {
+		return componentKey(comp) + " without stereotype"
+	}
+	return comp.Stereotype + " " + componentKey(comp)
+}
+
+// dependencyPackage returns the package a dependency is declared against: the
+// interface package for interface dependencies, otherwise the component package
+func dependencyPackage(dep Dependency, target Component) string {
+	depBase, depName := splitTypeRef(dep.Type)
+	if target.Type == depName || componentKey(target) == dep.Type {
+		return target.Package
+	}
+	for _, iface := range target.Implements {
+		ifaceBase, ifaceName := splitTypeRef(iface)
+		if ifaceName != depName || (ifaceBase != "" && ifaceBase != depBase) {
+			continue
+		}
+		if pkg := interfacePackage(iface); strings.Contains(pkg, "/") {
+			return pkg
+		}
+	}
+	return target.Package
+}
+
+// interfacePackage strips the interface name from a fully qualified interface reference
+func interfacePackage(iface string) string {
+	slash := strings.LastIndex(iface, "/")
+	if dot := strings.LastIndex(iface, "."); dot > slash {
+		return iface[:dot]
+	}
+	if slash == -1 {
+		return ""
+	}
+	return iface[:slash]
+}
+
+// firstMatchingPattern returns the first pattern matching pkg, or an empty string
+func firstMatchingPattern(patterns []string, pkg string) string {
+	for _, pattern := range patterns {
+		if matchPackagePattern(pattern, pkg) {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// matchPackagePattern matches a package import path against a rule pattern
+func matchPackagePattern(pattern, pkg string) bool {
+	if base, ok := strings.CutSuffix(pattern, "/..."); ok {
+		for candidate := pkg; ; {
+			if matchPackagePattern(base, candidate) {
+				return true
+			}
+			slash := strings.LastIndex(candidate, "/")
+			if slash == -1 {
+				return false
+			}
+			candidate = candidate[:slash]
+		}
+	}
+
+	candidate := pkg
+	for {
+		if ok, _ := path.Match(pattern, candidate); ok {
+			return true
+		}
+		slash := strings.Index(candidate, "/")
+		if slash == -1 {
+			return false
+		}
+		candidate = candidate[slash+1:]
+	}
+}
@@ -0,0 +1,144 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatchPackagePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		pkg     string
+		want    bool
+	}{
+		{"*/handler", "example.com/app/handler", true},
+		{"*/handler", "example.com/app/handler/admin", false},
+		{"handler", "example.com/app/handler", true},
+		{"example.com/app/service", "example.com/app/service", true},
+		{"example.com/app/service", "example.com/other/service", false},
+		{"example.com/app/...", "example.com/app/service/users", true},
+		{"*/platform/...", "example.com/platform/auth", true},
+		{"*/repository", "example.com/app/repositoryx", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchPackagePattern(tt.pattern, tt.pkg); got != tt.want {
+			t.Errorf("matchPackagePattern(%q, %q) = %v, want %v", tt.pattern, tt.pkg, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzer_CheckArchitecture(t *testing.T) {
+	components := queryTestComponents()
+	// Let the handler reach into the repository directly
+	components[3].Dependencies = append(components[3].Dependencies, Dependency{
+		FieldName:  "Repo",
+		Type:       "repository.UserRepository",
+		LineNumber: 14,
+	})
+
+	rules := &ArchRuleSet{Rules: []ArchRule{
+		{Name: "handler-layering", From: "*/handler", Allow: []string{"*/service"}, Deny: []string{"*/repository"}},
+		{Name: "logger-leaf", From: "*/logger", Deny: []string{"example.com/app/..."}},
+	}}
+
+	violations := NewAnalyzer(components).CheckArchitecture(rules)
+	if len(violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %d: %+v", len(violations), violations)
+	}
+
+	v := violations[0]
+	if v.Rule != "handler-layering" || v.FieldName != "Repo" || v.LineNumber != 14 {
+		t.Errorf("Unexpected violation: %+v", v)
+	}
+	if v.SourceFile != "/app/handler/user_handler.go" {
+		t.Errorf("Expected violation in handler source file, got %s", v.SourceFile)
+	}
+
+	// Allow lists reject anything that is not listed
+	rules = &ArchRuleSet{Rules: []ArchRule{{Name: "service-only", From: "*/service", Allow: []string{"*/repository"}}}}
+	violations = NewAnalyzer(components).CheckArchitecture(rules)
+	if len(violations) != 1 || violations[0].ToPackage != "example.com/app/logger" {
+		t.Errorf("Expected the logger dependency of the service to be rejected, got %+v", violations)
+	}
+}
+
+func TestAnalyzer_CheckArchitectureInjectedTarget(t *testing.T) {
+	components := []Component{
+		{Type: "UserHandler", Package: "example.com/app/handler", Dependencies: []Dependency{
+			{FieldName: "Users", Type: "service.Users"},
+		}},
+		{Type: "UserService", Package: "example.com/app/service", Stereotype: "Service", Implements: []string{"example.com/app/service/Users"}, Primary: true},
+		{Type: "UserRepository", Package: "example.com/app/repository", Stereotype: "Repository", Implements: []string{"example.com/app/service/Users"}},
+	}
+	rules := &ArchRuleSet{Rules: []ArchRule{
+		{Name: "handler-layering", From: "*/handler", DenyStereotypes: []string{"Repository"}},
+	}}
+
+	// The repository also implements the interface but the primary service is injected
+	if violations := NewAnalyzer(components).CheckArchitecture(rules); len(violations) != 0 {
+		t.Errorf("Expected no violation through an implementation that is not injected, got %+v", violations)
+	}
+
+	components[1].Primary, components[2].Primary = false, true
+	violations := NewAnalyzer(components).CheckArchitecture(rules)
+	if len(violations) != 1 || violations[0].Target != "example.com/app/repository.UserRepository" {
+		t.Errorf("Expected a violation through the injected repository, got %+v", violations)
+	}
+}
+
+func TestLoadArchRules(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	valid := filepath.Join(tmpDir, "rules.json")
+	content := `{"rules": [{"from": "*/handler", "deny": ["*/repository"]}]}`
+	if err := os.WriteFile(valid, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write rule file: %v", err)
+	}
+	rules, err := LoadArchRules(valid)
+	if err != nil {
+		t.Fatalf("LoadArchRules failed: %v", err)
+	}
+	if rules.Rules[0].Name != "rule-1" {
+		t.Errorf("Expected default rule name, got %q", rules.Rules[0].Name)
+	}
+
+	invalid := filepath.Join(tmpDir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"rules": [{"from": "*/handler"}]}`), 0644); err != nil {
+		t.Fatalf("Failed to write rule file: %v", err)
+	}
+	if _, err := LoadArchRules(invalid); err == nil {
+		t.Error("Expected error for rule without allow or deny patterns")
+	}
+}
+
+func TestWriteArchViolations(t *testing.T) {
+	violations := []ArchViolation{{Rule: "r", SourceFile: "a.go", LineNumber: 3, FieldName: "Repo"}}
+
+	var text bytes.Buffer
+	if err := WriteArchViolations(&text, violations, "text"); err != nil {
+		t.Fatalf("WriteArchViolations failed: %v", err)
+	}
+	if !strings.Contains(text.String(), "a.go:3 [r]") {
+		t.Errorf("Expected file and line in text output, got %s", text.String())
+	}
+
+	var out bytes.Buffer
+	if err := WriteArchViolations(&out, violations, "json"); err != nil {
+		t.Fatalf("WriteArchViolations failed: %v", err)
+	}
+	var decoded struct {
+		Violations []ArchViolation `json:"violations"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || len(decoded.Violations) != 1 {
+		t.Errorf("Expected valid JSON with 1 violation, got %s (%v)", out.String(), err)
+	}
+
+	if err := WriteArchViolations(&out, violations, "xml"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
@@ -0,0 +1,82 @@
+package wire
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// baselineVersion is the format version written to baseline files
+const baselineVersion = 1
+
+// Baseline is a set of accepted findings. Only findings missing from the
+// baseline are reported as new
+type Baseline struct {
+	Version  int             `json:"version"`
+	Findings []BaselineEntry `json:"findings"`
+}
+
+// BaselineEntry records one accepted finding
+type BaselineEntry struct {
+	RuleID      string `json:"rule"`
+	Fingerprint string `json:"fingerprint"`
+	Message     string `json:"message"`
+}
+
+// NewBaseline creates a baseline accepting all given findings
+func NewBaseline(findings []Finding) *Baseline {
+	baseline := &Baseline{Version: baselineVersion, Findings: []BaselineEntry{}}
+	for _, finding := range findings {
+		baseline.Findings = append(baseline.Findings, BaselineEntry{
+			RuleID:      finding.RuleID,
+			Fingerprint: finding.Fingerprint,
+			Message:     finding.Message,
+		})
+	}
+	return baseline
+}
+
+// WriteBaseline writes the baseline as indented JSON
+func (b *Baseline) WriteBaseline(path string) error {
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// LoadBaseline reads a baseline file
+func LoadBaseline(path string) (*Baseline, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var baseline Baseline
+	if err := json.Unmarshal(content, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if baseline.Version != baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s", baseline.Version, path)
+	}
+	return &baseline, nil
+}
+
+// NewFindings returns the findings that are not accepted by the baseline
+func (b *Baseline) NewFindings(findings []Finding) []Finding {
+	accepted := make(map[string]bool, len(b.Findings))
+	for _, entry := range b.Findings {
+		accepted[entry.Fingerprint] = true
+	}
+
+	var fresh []Finding
+	for _, finding := range findings {
+		if !accepted[finding.Fingerprint] {
+			fresh = append(fresh, finding)
+		}
+	}
+	return fresh
+}
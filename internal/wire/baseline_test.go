@@ -0,0 +1,49 @@
+package wire
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBaseline(t *testing.T) {
+	accepted := []Finding{
+		{RuleID: RuleUnusedComponent, Fingerprint: "unused-component:example.com/app.Legacy", Message: "unused"},
+	}
+
+	path := filepath.Join(t.TempDir(), ".iocgen-baseline.json")
+	if err := NewBaseline(accepted).WriteBaseline(path); err != nil {
+		t.Fatalf("WriteBaseline failed: %v", err)
+	}
+
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline failed: %v", err)
+	}
+
+	current := append(accepted, Finding{
+		RuleID:      RuleQualifierConflict,
+		Fingerprint: "qualifier-conflict:example.com/app.Logger:",
+		LineNumber:  42, // Locations do not affect the identity
+	})
+	fresh := baseline.NewFindings(current)
+	if len(fresh) != 1 || fresh[0].RuleID != RuleQualifierConflict {
+		t.Errorf("Expected only the qualifier conflict to be new, got %+v", fresh)
+	}
+}
+
+func TestLoadBaselineErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := LoadBaseline(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected error for missing baseline")
+	}
+
+	path := filepath.Join(dir, "future.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99, "findings": []}`), 0644); err != nil {
+		t.Fatalf("Failed to write baseline: %v", err)
+	}
+	if _, err := LoadBaseline(path); err == nil {
+		t.Error("Expected error for unsupported baseline version")
+	}
+}
@@ -0,0 +1,173 @@
+package wire
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// scanCacheFile is the name of the cache index inside the cache directory
+const scanCacheFile = "components.json"
+
+// scanCacheSchema is the version of what the parser extracts from a file. Bump it
+// whenever extraction changes so that results of older extractors are not reused
+const scanCacheSchema = 5
+
+// scanCacheVersion identifies the tool and extractor that wrote a cache entry
+var scanCacheVersion = fmt.Sprintf("%s+%d", Version, scanCacheSchema)
+
+// fileScan is what the parser extracts from a single file
+type fileScan struct {
+	Components  []Component           `json:"components"`
+	Modules     []moduleDecl          `json:"modules,omitempty"`
+	Stereotypes []stereotypeDecl      `json:"stereotypes,omitempty"`
+	Candidates  []stereotypeCandidate `json:"candidates,omitempty"`
+}
+
+// cachedFile is a cache entry: the extraction result with the file it was read from
+type cachedFile struct {
+	fileScan
+	Path string `json:"path"` // File the result was last extracted from
+}
+
+// scanCache stores the components extracted from each file, keyed by a hash of
+// the file content, its package path and the extractor version. A nil cache is valid
+// and caches nothing
+type scanCache struct {
+	mu      sync.Mutex // Guards the maps, the cache is shared by parser workers
+	dir     string
+	entries map[string]cachedFile // Entries loaded from disk
+	used    map[string]cachedFile // Entries looked up or stored during this scan
+	dirty   bool
+}
+
+// scanCacheIndex is the on-disk format of the cache
+type scanCacheIndex struct {
+	Version string                `json:"version"`
+	Entries map[string]cachedFile `json:"entries"`
+}
+
+// openScanCache loads the cache from dir. A missing, unreadable or outdated
+// cache is treated as empty
+func openScanCache(dir string) *scanCache {
+	cache := &scanCache{
+		dir:     dir,
+		entries: make(map[string]cachedFile),
+		used:    make(map[string]cachedFile),
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, scanCacheFile))
+	if err != nil {
+		return cache
+	}
+	var index scanCacheIndex
+	if err := json.Unmarshal(content, &index); err != nil || index.Version != scanCacheVersion {
+		cache.dirty = true
+		return cache
+	}
+	if index.Entries != nil {
+		cache.entries = index.Entries
+	}
+	return cache
+}
+
+// fileCacheKey identifies the extraction result of a file
+func fileCacheKey(pkgPath string, content []byte) string {
+	h := sha256.New()
+	h.Write([]byte(scanCacheVersion + "\x00" + pkgPath + "\x00"))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// lookup returns the cached extraction result for a key, read from path
+func (c *scanCache) lookup(key, path string) (fileScan, bool) {
+	if c == nil {
+		return fileScan{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if ok {
+		if entry.Path != path {
+			entry.Path = path
+			c.dirty = true
+		}
+		c.used[key] = entry
+	}
+	return entry.fileScan, ok
+}
+
+// store records the extraction result for a key, read from path
+func (c *scanCache) store(key, path string, scan fileScan) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.used[key] = cachedFile{fileScan: scan, Path: path}
+	c.dirty = true
+}
+
+// save writes the cache back to disk. Entries this scan did not use are kept,
+// so that scoped scans do not throw away the rest of the tree, unless their file
+// was deleted or read with other content by this scan. That keeps at most one
+// entry per existing file
+func (c *scanCache) save() error {
+	if c == nil {
+		return nil
+	}
+
+	usedPaths := make(map[string]bool, len(c.used))
+	for _, entry := range c.used {
+		usedPaths[entry.Path] = true
+	}
+	entries := make(map[string]cachedFile, len(c.entries))
+	for key, entry := range c.used {
+		entries[key] = entry
+	}
+	for key, entry := range c.entries {
+		if _, ok := entries[key]; ok || usedPaths[entry.Path] {
+			continue
+		}
+		if _, err := os.Stat(entry.Path); err != nil {
+			continue
+		}
+		entries[key] = entry
+	}
+	if !c.dirty && len(entries) == len(c.entries) {
+		return nil
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(scanCacheIndex{Version: scanCacheVersion, Entries: entries})
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so that concurrent runs never read a partial file
+	tmp, err := os.CreateTemp(c.dir, scanCacheFile+".*")
+	if err != nil {
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, scanCacheFile))
+}
@@ -0,0 +1,137 @@
+package wire
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseComponentsWithCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	writeFile("go.mod", "module example.com/cache\n\ngo 1.23\n")
+	writeFile("config/config.go", "package config\n\ntype Config struct {\n\tComponent struct{}\n}\n")
+	writeFile("service/service.go", "package service\n\ntype Service struct {\n\tComponent struct{}\n\tConfig *config.Config `autowired:\"true\"`\n}\n\nfunc (s *Service) PostConstruct() {}\n")
+
+	opts := ParseOptions{CacheDir: filepath.Join(tmpDir, ".iocgen", "cache")}
+	first, stats, err := ParseComponentsWithOptions(tmpDir, opts)
+	if err != nil {
+		t.Fatalf("First scan failed: %v", err)
+	}
+	if stats.Files != 2 || stats.CacheHits != 0 || stats.CacheMisses != 2 {
+		t.Errorf("Expected a cold cache, got %+v", stats)
+	}
+
+	second, stats, err := ParseComponentsWithOptions(tmpDir, opts)
+	if err != nil {
+		t.Fatalf("Second scan failed: %v", err)
+	}
+	if stats.CacheHits != 2 || stats.CacheMisses != 0 {
+		t.Errorf("Expected every file to come from the cache, got %+v", stats)
+	}
+	if ComputeModelHashes(first).ModelHash() != ComputeModelHashes(second).ModelHash() {
+		t.Error("Expected cached components to match parsed components")
+	}
+	for i := range second {
+		if second[i].SourceFile != first[i].SourceFile || second[i].LineNumber != first[i].LineNumber {
+			t.Errorf("Expected cached source positions to match, got %s:%d", second[i].SourceFile, second[i].LineNumber)
+		}
+	}
+
+	// Only the changed file is parsed again
+	writeFile("service/service.go", "package service\n\ntype Service struct {\n\tComponent struct{}\n}\n")
+	_, stats, err = ParseComponentsWithOptions(tmpDir, opts)
+	if err != nil {
+		t.Fatalf("Third scan failed: %v", err)
+	}
+	if stats.CacheHits != 1 || stats.CacheMisses != 1 {
+		t.Errorf("Expected one hit and one miss, got %+v", stats)
+	}
+
+	// Entries of old file contents are dropped
+	content, err := os.ReadFile(filepath.Join(opts.CacheDir, scanCacheFile))
+	if err != nil {
+		t.Fatalf("Failed to read cache: %v", err)
+	}
+	var index scanCacheIndex
+	if err := json.Unmarshal(content, &index); err != nil {
+		t.Fatalf("Cache is not valid JSON: %v", err)
+	}
+	if len(index.Entries) != 2 {
+		t.Errorf("Expected 2 cache entries after pruning, got %d", len(index.Entries))
+	}
+
+	// A cache written by another version, or an older extractor of the same
+	// version, is ignored
+	for _, version := range []string{"other", Version} {
+		index.Version = version
+		content, _ = json.Marshal(index)
+		if err := os.WriteFile(filepath.Join(opts.CacheDir, scanCacheFile), content, 0644); err != nil {
+			t.Fatalf("Failed to write cache: %v", err)
+		}
+		_, stats, _ = ParseComponentsWithOptions(tmpDir, opts)
+		if stats.CacheHits != 0 {
+			t.Errorf("Expected a cache of version %q to be ignored, got %+v", version, stats)
+		}
+	}
+}
+
+func TestParseComponentsWithCache_ScopedScan(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod":             "module example.com/cache\n\ngo 1.23\n",
+		"config/config.go":   "package config\n\ntype Config struct {\n\tComponent struct{}\n}\n",
+		"service/service.go": "package service\n\ntype Service struct {\n\tComponent struct{}\n}\n",
+	})
+	opts := ParseOptions{CacheDir: filepath.Join(tmpDir, ".iocgen", "cache")}
+	if _, _, err := ParseComponentsWithOptions(tmpDir, opts); err != nil {
+		t.Fatalf("Full scan failed: %v", err)
+	}
+
+	// A scoped scan keeps the entries of the files it does not look at
+	scoped := opts
+	scoped.Roots = []string{"config"}
+	_, stats, err := ParseComponentsWithOptions(tmpDir, scoped)
+	if err != nil {
+		t.Fatalf("Scoped scan failed: %v", err)
+	}
+	if stats.Files != 1 || stats.CacheHits != 1 {
+		t.Errorf("Expected the scoped file to come from the cache, got %+v", stats)
+	}
+	_, stats, err = ParseComponentsWithOptions(tmpDir, opts)
+	if err != nil {
+		t.Fatalf("Full scan failed: %v", err)
+	}
+	if stats.CacheHits != 2 || stats.CacheMisses != 0 {
+		t.Errorf("Expected every file to come from the cache after a scoped scan, got %+v", stats)
+	}
+
+	// Entries of deleted files are dropped, even when a scoped scan did not see them
+	if err := os.Remove(filepath.Join(tmpDir, "service", "service.go")); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+	if _, _, err := ParseComponentsWithOptions(tmpDir, scoped); err != nil {
+		t.Fatalf("Scoped scan failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(opts.CacheDir, scanCacheFile))
+	if err != nil {
+		t.Fatalf("Failed to read cache: %v", err)
+	}
+	var index scanCacheIndex
+	if err := json.Unmarshal(content, &index); err != nil {
+		t.Fatalf("Cache is not valid JSON: %v", err)
+	}
+	if len(index.Entries) != 1 {
+		t.Errorf("Expected 1 cache entry after deleting a file, got %d", len(index.Entries))
+	}
+}
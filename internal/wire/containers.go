@@ -0,0 +1,193 @@
+package wire
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultContainerManifest is the manifest file looked up in the project directory
+const DefaultContainerManifest = ".iocgen-containers.json"
+
+// ContainerSpec describes a named container generated for one binary. The
+// container holds only the components reachable from its roots
+type ContainerSpec struct {
+	Name   string   `json:"name"`
+	Roots  []string `json:"roots,omitempty"`  // Component or interface references, e.g. "server.Server"
+	Output string   `json:"output,omitempty"` // Output directory relative to the project, defaults to cmd/<name>/wire
+}
+
+// containerManifest is the JSON document listing the containers of a project:
+//
+//	{
+//	  "containers": [
+//	    {"name": "api", "roots": ["server.Server"], "output": "cmd/api/wire"},
+//	    {"name": "worker", "roots": ["jobs.Runner"]}
+//	  ]
+//	}
+type containerManifest struct {
+	Containers []ContainerSpec `json:"containers"`
+}
+
+// LoadContainerManifest reads the containers declared in a manifest file
+func LoadContainerManifest(path string) ([]ContainerSpec, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest containerManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid container manifest %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for _, spec := range manifest.Containers {
+		if !validContainerName(spec.Name) {
+			return nil, fmt.Errorf("invalid container name %q in %s", spec.Name, path)
+		}
+		if seen[spec.Name] {
+			return nil, fmt.Errorf("container %s is declared twice in %s", spec.Name, path)
+		}
+		seen[spec.Name] = true
+		if filepath.IsAbs(spec.Output) || strings.HasPrefix(filepath.Clean(filepath.FromSlash(spec.Output)), "..") {
+			return nil, fmt.Errorf("output of container %s must be a directory inside the project, got %q", spec.Name, spec.Output)
+		}
+	}
+	return manifest.Containers, nil
+}
+
+// CollectContainers merges the manifest containers with the roots assigned by
+// EntryPoint markers such as EntryPoint struct{} `value:"api,worker"`. Containers
+// only named by markers are created with the default output directory. The result
+// is ordered by name
+func CollectContainers(specs []ContainerSpec, components []Component) ([]ContainerSpec, error) {
+	byName := make(map[string]*ContainerSpec)
+	for _, spec := range specs {
+		spec.Roots = append([]string(nil), spec.Roots...)
+		byName[spec.Name] = &spec
+	}
+	for _, comp := range components {
+		for _, name := range comp.Containers {
+			if !validContainerName(name) {
+				return nil, fmt.Errorf("invalid container name %q in the EntryPoint marker of %s (%s:%d)",
+					name, componentKey(comp), comp.SourceFile, comp.LineNumber)
+			}
+			if byName[name] == nil {
+				byName[name] = &ContainerSpec{Name: name}
+			}
+			byName[name].Roots = appendUnique(byName[name].Roots, componentKey(comp))
+		}
+	}
+
+	var containers []ContainerSpec
+	for _, name := range sortedKeys(byName) {
+		containers = append(containers, *byName[name])
+	}
+	return containers, nil
+}
+
+// OutputDir returns the directory the container's wire_gen.go is written to
+func (c ContainerSpec) OutputDir(baseDir string) string {
+	output := c.Output
+	if output == "" {
+		output = filepath.Join("cmd", c.Name, "wire")
+	}
+	return filepath.Join(baseDir, filepath.FromSlash(output))
+}
+
+// ContainerComponents returns the components making up a container: its roots
+// and everything they depend on, in declaration order
+func ContainerComponents(components []Component, spec ContainerSpec) ([]Component, error) {
+	if len(spec.Roots) == 0 {
+		return nil, fmt.Errorf("container %s has no roots", spec.Name)
+	}
+
+	selected, _, err := reachableComponents(components, spec.Roots)
+	if err != nil {
+		return nil, fmt.Errorf("container %s: %w", spec.Name, err)
+	}
+	return selected, nil
+}
+
+// ForContainer makes the generator write the named container into its own output
+// package below baseDir instead of the wire package of the whole project
+func (g *Generator) ForContainer(baseDir string, spec ContainerSpec) *Generator {
+	g.outputDir = spec.OutputDir(baseDir)
+	g.packageName = packageNameFor(g.outputDir)
+	g.generateArgs = fmt.Sprintf("--dir=%s --container=%s", relativeDir(g.outputDir, baseDir), spec.Name)
+	return g
+}
+
+// ForOutput makes the generator write wire_gen.go into outputDir instead of the
+// wire directory, in a package named after outputDir
+func (g *Generator) ForOutput(baseDir, outputDir string) *Generator {
+	g.outputDir = outputDir
+	g.packageName = packageNameFor(outputDir)
+	g.generateArgs = fmt.Sprintf("--dir=%s --output=%s/wire_gen.go", relativeDir(outputDir, baseDir), relativeDir(baseDir, outputDir))
+	return g
+}
+
+// relativeDir returns target relative to dir with forward slashes, or target
+// itself when it cannot be made relative
+func relativeDir(dir, target string) string {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		return filepath.ToSlash(target)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// packageNameFor derives a package name from the last element of a directory
+func packageNameFor(dir string) string {
+	name := unexportedIdent(strings.ToLower(exportedIdent(filepath.Base(dir))))
+	if name == "" || name == "main" {
+		return "wire"
+	}
+	return name
+}
+
+// validContainerName reports whether a container name can be used in file paths and flags
+func validContainerName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// SelectContainers returns the containers with the given names, or all of them
+// when no name is given
+func SelectContainers(specs []ContainerSpec, names []string) ([]ContainerSpec, error) {
+	if len(names) == 0 {
+		return specs, nil
+	}
+
+	var selected []ContainerSpec
+	for _, name := range names {
+		found := false
+		for _, spec := range specs {
+			if spec.Name == name {
+				selected = append(selected, spec)
+				found = true
+				break
+			}
+		}
+		if !found {
+			var known []string
+			for _, spec := range specs {
+				known = append(known, spec.Name)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown container %q, declared containers: %s", name, strings.Join(known, ", "))
+		}
+	}
+	return selected, nil
+}
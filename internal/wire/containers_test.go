@@ -0,0 +1,191 @@
+package wire
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// containerFiles is a project with an API server and a worker sharing the config
+var containerFiles = map[string]string{
+	"go.mod":           "module example.com/shop\n\ngo 1.23\n",
+	"config/config.go": "package config\n\ntype Config struct {\n\tComponent struct{}\n}\n",
+	"store/store.go": `package store
+
+import "example.com/shop/config"
+
+type Store struct {
+	Component struct{}
+	Config    *config.Config ` + "`autowired:\"true\"`" + `
+}
+`,
+	"server/server.go": `package server
+
+import "example.com/shop/store"
+
+type Server struct {
+	Component  struct{}
+	EntryPoint struct{} ` + "`value:\"api\"`" + `
+	Store      *store.Store ` + "`autowired:\"true\"`" + `
+}
+`,
+	"jobs/runner.go": `package jobs
+
+import "example.com/shop/config"
+
+type Runner struct {
+	Component struct{}
+	Config    *config.Config ` + "`autowired:\"true\"`" + `
+}
+`,
+	DefaultContainerManifest: `{"containers": [{"name": "worker", "roots": ["jobs.Runner"], "output": "cmd/worker/internal/di"}]}`,
+}
+
+func TestLoadContainerManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, containerFiles)
+
+	specs, err := LoadContainerManifest(filepath.Join(tmpDir, DefaultContainerManifest))
+	if err != nil {
+		t.Fatalf("LoadContainerManifest failed: %v", err)
+	}
+	expected := []ContainerSpec{{Name: "worker", Roots: []string{"jobs.Runner"}, Output: "cmd/worker/internal/di"}}
+	if !reflect.DeepEqual(specs, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, specs)
+	}
+
+	for name, manifest := range map[string]string{
+		"invalid name": `{"containers": [{"name": "../api"}]}`,
+		"duplicate":    `{"containers": [{"name": "api"}, {"name": "api"}]}`,
+		"outside":      `{"containers": [{"name": "api", "output": "../api/wire"}]}`,
+		"not json":     `containers: []`,
+	} {
+		path := filepath.Join(tmpDir, "manifest.json")
+		writeFiles(t, tmpDir, map[string]string{"manifest.json": manifest})
+		if _, err := LoadContainerManifest(path); err == nil {
+			t.Errorf("Expected an error for the %s manifest", name)
+		}
+	}
+}
+
+func TestContainerComponents(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, containerFiles)
+	components, err := ParseComponents(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+	manifest, err := LoadContainerManifest(filepath.Join(tmpDir, DefaultContainerManifest))
+	if err != nil {
+		t.Fatalf("LoadContainerManifest failed: %v", err)
+	}
+
+	// The api container is declared by the EntryPoint marker of the server only
+	specs, err := CollectContainers(manifest, components)
+	if err != nil {
+		t.Fatalf("CollectContainers failed: %v", err)
+	}
+	if len(specs) != 2 || specs[0].Name != "api" || specs[1].Name != "worker" {
+		t.Fatalf("Expected the api and worker containers, got %+v", specs)
+	}
+	if !reflect.DeepEqual(specs[0].Roots, []string{"example.com/shop/server.Server"}) {
+		t.Errorf("Expected the server as root of the api container, got %v", specs[0].Roots)
+	}
+
+	expected := map[string][]string{
+		"api":    {"Config", "Server", "Store"},
+		"worker": {"Config", "Runner"},
+	}
+	for _, spec := range specs {
+		selected, err := ContainerComponents(components, spec)
+		if err != nil {
+			t.Fatalf("ContainerComponents failed: %v", err)
+		}
+		var types []string
+		for _, comp := range selected {
+			types = append(types, comp.Type)
+		}
+		sort.Strings(types)
+		if !reflect.DeepEqual(types, expected[spec.Name]) {
+			t.Errorf("Expected container %s to hold %v, got %v", spec.Name, expected[spec.Name], types)
+		}
+	}
+
+	if _, err := ContainerComponents(components, ContainerSpec{Name: "migrate", Roots: []string{"migrations.Migrator"}}); err == nil {
+		t.Error("Expected an error for a root that matches no component")
+	}
+	if selected, err := SelectContainers(specs, []string{"worker"}); err != nil || len(selected) != 1 || selected[0].Name != "worker" {
+		t.Errorf("Expected only the worker container, got %+v (%v)", selected, err)
+	}
+	if _, err := SelectContainers(specs, []string{"migrate"}); err == nil || !strings.Contains(err.Error(), "api, worker") {
+		t.Errorf("Expected an error listing the declared containers, got %v", err)
+	}
+}
+
+func TestGenerator_ForContainer(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, containerFiles)
+	components, err := ParseComponents(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+	spec := ContainerSpec{Name: "worker", Roots: []string{"jobs.Runner"}, Output: "cmd/worker/internal/di"}
+	selected, err := ContainerComponents(components, spec)
+	if err != nil {
+		t.Fatalf("ContainerComponents failed: %v", err)
+	}
+
+	if err := NewGenerator(selected).ForContainer(tmpDir, spec).Generate(tmpDir); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	code, err := os.ReadFile(filepath.Join(tmpDir, "cmd", "worker", "internal", "di", "wire_gen.go"))
+	if err != nil {
+		t.Fatalf("Expected the container to be written to its output package: %v", err)
+	}
+	for _, want := range []string{
+		"iocgen --dir=../../../.. --container=worker\n",
+		"\npackage di\n",
+		"container.Runner = &jobs.Runner{",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Expected generated code to contain %q:\n%s", want, code)
+		}
+	}
+	if strings.Contains(string(code), "server.Server") {
+		t.Errorf("Expected the worker container not to hold the API server:\n%s", code)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "wire")); err == nil {
+		t.Error("Expected no project-wide wire package to be generated")
+	}
+}
+
+func TestWatcher_Containers(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, containerFiles)
+	manifest, err := LoadContainerManifest(filepath.Join(tmpDir, DefaultContainerManifest))
+	if err != nil {
+		t.Fatalf("LoadContainerManifest failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	watcher, err := NewWatcher(tmpDir, WatchOptions{Output: &out, Containers: manifest})
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+	if err := watcher.start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	for _, output := range []string{"cmd/api/wire", "cmd/worker/internal/di"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, filepath.FromSlash(output), "wire_gen.go")); err != nil {
+			t.Errorf("Expected %s/wire_gen.go to be generated: %v", output, err)
+		}
+	}
+	if !strings.Contains(out.String(), "Regenerated wire_gen.go of container worker (2 components)") {
+		t.Errorf("Expected a status line per container, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,138 @@
+package wire
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// dependencyRoot is a directory of a dependency module selected for scanning
+type dependencyRoot struct {
+	Dir       string
+	Recursive bool // Whether packages below Dir are scanned as well
+}
+
+// dependencyRoots locates the packages matched by a package pattern such as
+// github.com/acme/platform/... or github.com/acme/platform/auth in the modules
+// required by the main module. Packages are taken from the vendor directory when
+// the main module is vendored and from the module cache otherwise, never from the
+// network. Located modules are registered so their packages get correct import paths
+func (ws *workspace) dependencyRoots(pattern string) ([]dependencyRoot, error) {
+	base, recursive := strings.CutSuffix(pattern, "/...")
+	if base == "..." || base == "" {
+		return nil, fmt.Errorf("invalid module pattern %q", pattern)
+	}
+
+	var roots []dependencyRoot
+	for _, modPath := range sortedKeys(ws.main.Requires) {
+		var rel string
+		switch {
+		case base == modPath:
+		case strings.HasPrefix(base, modPath+"/"):
+			rel = strings.TrimPrefix(base, modPath+"/")
+		case recursive && strings.HasPrefix(modPath, base+"/"):
+			// The pattern spans the whole module
+		default:
+			continue
+		}
+
+		modDir, err := ws.locateModule(modPath)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, dependencyRoot{Dir: filepath.Join(modDir, filepath.FromSlash(rel)), Recursive: recursive})
+	}
+
+	// A more specific module also matches a package of a module containing it, so
+	// only keep the deepest module for a package pattern without wildcard
+	if !recursive && len(roots) > 1 {
+		roots = roots[len(roots)-1:]
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no module required by %s provides %s", ws.main.Path, pattern)
+	}
+	for _, root := range roots {
+		if info, err := os.Stat(root.Dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("package directory %s for %s not found", root.Dir, pattern)
+		}
+	}
+	return roots, nil
+}
+
+// locateModule returns the directory holding the sources of a required module
+// and registers the module as owner of that directory
+func (ws *workspace) locateModule(modPath string) (string, error) {
+	// Workspace modules are scanned from their own directory anyway
+	for _, mod := range ws.modules {
+		if mod.Path == modPath {
+			return mod.Dir, nil
+		}
+	}
+
+	source, version := modPath, ws.main.Requires[modPath]
+	var dir string
+	switch replacement := ws.main.Replaces[modPath]; {
+	case strings.HasPrefix(replacement, "./") || strings.HasPrefix(replacement, "../") || filepath.IsAbs(replacement):
+		dir = replacement
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(ws.main.Dir, dir)
+		}
+	case ws.vendored():
+		dir = filepath.Join(ws.main.Dir, "vendor", filepath.FromSlash(modPath))
+	default:
+		if replacement != "" {
+			// Replaced by another module version: path@version
+			if at := strings.LastIndex(replacement, "@"); at > 0 {
+				source, version = replacement[:at], replacement[at+1:]
+			}
+		}
+		dir = filepath.Join(moduleCacheDir(), escapeModulePath(source)+"@"+escapeModulePath(version))
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		if ws.vendored() {
+			return "", fmt.Errorf("module %s is not vendored in %s, run go mod vendor", modPath, filepath.Join(ws.main.Dir, "vendor"))
+		}
+		return "", fmt.Errorf("module %s@%s is not in the module cache, run go mod download %s", source, version, modPath)
+	}
+
+	// Packages keep the import path of the required module, also when it is replaced
+	ws.mu.Lock()
+	ws.owner[dir] = &goModule{Path: modPath, Dir: dir}
+	ws.mu.Unlock()
+	return dir, nil
+}
+
+// vendored reports whether the main module keeps its dependencies in vendor/
+func (ws *workspace) vendored() bool {
+	if ws.workFile != "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(ws.main.Dir, "vendor", "modules.txt"))
+	return err == nil
+}
+
+// moduleCacheDir returns the module cache directory: $GOMODCACHE or $GOPATH/pkg/mod
+func moduleCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	return filepath.Join(filepath.SplitList(build.Default.GOPATH)[0], "pkg", "mod")
+}
+
+// escapeModulePath escapes a module path or version for the module cache, which
+// replaces every upper case letter by an exclamation mark and the lower case letter
+func escapeModulePath(path string) string {
+	var escaped strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			escaped.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		escaped.WriteRune(r)
+	}
+	return filepath.FromSlash(escaped.String())
+}
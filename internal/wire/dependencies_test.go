@@ -0,0 +1,180 @@
+package wire
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// platformFiles is a shared module with an auth client and an audit logger
+var platformFiles = map[string]string{
+	"go.mod":              "module github.com/Acme/platform\n\ngo 1.23\n",
+	"auth/client.go":      "package auth\n\ntype Client struct {\n\tComponent struct{}\n}\n",
+	"auth/client_test.go": "package auth\n\ntype ClientFake struct {\n\tComponent struct{}\n}\n",
+	"audit/logger.go":     "package audit\n\ntype Logger struct {\n\tComponent struct{}\n}\n",
+	"audit/testdata/x.go": "package x\n\ntype Fixture struct {\n\tComponent struct{}\n}\n",
+}
+
+// appFiles is a module using the platform's auth client
+var appFiles = map[string]string{
+	"go.mod": "module example.com/app\n\ngo 1.23\n\nrequire github.com/Acme/platform v1.2.0\n",
+	"service/service.go": `package service
+
+import "github.com/Acme/platform/auth"
+
+type Service struct {
+	Component struct{}
+	Auth      *auth.Client ` + "`autowired:\"true\"`" + `
+}
+`,
+}
+
+// componentPackages maps the type names of components to their packages
+func componentPackages(components []Component) map[string]string {
+	packages := make(map[string]string)
+	for _, comp := range components {
+		packages[comp.Type] = comp.Package
+	}
+	return packages
+}
+
+func TestParseComponents_ModuleCache(t *testing.T) {
+	modCache := t.TempDir()
+	t.Setenv("GOMODCACHE", modCache)
+	t.Setenv("GOWORK", "off")
+	writeFiles(t, filepath.Join(modCache, "github.com", "!acme", "platform@v1.2.0"), platformFiles)
+	appDir := t.TempDir()
+	writeFiles(t, appDir, appFiles)
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected map[string]string
+	}{
+		{"whole module", []string{"github.com/Acme/platform/..."}, map[string]string{
+			"Service": "example.com/app/service",
+			"Client":  "github.com/Acme/platform/auth",
+			"Logger":  "github.com/Acme/platform/audit",
+		}},
+		{"single package", []string{"github.com/Acme/platform/auth"}, map[string]string{
+			"Service": "example.com/app/service",
+			"Client":  "github.com/Acme/platform/auth",
+		}},
+		{"prefix spanning modules", []string{"github.com/Acme/..."}, map[string]string{
+			"Service": "example.com/app/service",
+			"Client":  "github.com/Acme/platform/auth",
+			"Logger":  "github.com/Acme/platform/audit",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components, _, err := ParseComponentsWithOptions(appDir, ParseOptions{Modules: tt.patterns})
+			if err != nil {
+				t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+			}
+			if got := componentPackages(components); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	components, _, err := ParseComponentsWithOptions(appDir, ParseOptions{Modules: []string{"github.com/Acme/platform/..."}})
+	if err != nil {
+		t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+	}
+	if _, err := NewGenerator(components).GenerateCode(); err != nil {
+		t.Errorf("Expected the dependency component to be wired, got %v", err)
+	}
+
+	for _, pattern := range []string{"github.com/other/...", "github.com/Acme/platform/missing", "..."} {
+		if _, _, err := ParseComponentsWithOptions(appDir, ParseOptions{Modules: []string{pattern}}); err == nil {
+			t.Errorf("Expected an error for %s", pattern)
+		}
+	}
+}
+
+func TestParseComponents_ModuleNotDownloaded(t *testing.T) {
+	t.Setenv("GOMODCACHE", t.TempDir())
+	t.Setenv("GOWORK", "off")
+	appDir := t.TempDir()
+	writeFiles(t, appDir, appFiles)
+
+	_, _, err := ParseComponentsWithOptions(appDir, ParseOptions{Modules: []string{"github.com/Acme/platform/..."}})
+	if err == nil || !strings.Contains(err.Error(), "go mod download github.com/Acme/platform") {
+		t.Errorf("Expected a module cache error, got %v", err)
+	}
+}
+
+func TestParseComponents_VendoredModule(t *testing.T) {
+	t.Setenv("GOMODCACHE", t.TempDir())
+	t.Setenv("GOWORK", "off")
+	appDir := t.TempDir()
+	writeFiles(t, appDir, appFiles)
+	writeFiles(t, appDir, map[string]string{
+		"vendor/modules.txt":                             "# github.com/Acme/platform v1.2.0\n## explicit\ngithub.com/Acme/platform/auth\n",
+		"vendor/github.com/Acme/platform/auth/client.go": platformFiles["auth/client.go"],
+	})
+
+	// Without --scan-module the vendor directory is not scanned
+	components, err := ParseComponents(appDir)
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+	if len(components) != 1 {
+		t.Errorf("Expected only the local component, got %v", componentPackages(components))
+	}
+
+	components, _, err = ParseComponentsWithOptions(appDir, ParseOptions{Modules: []string{"github.com/Acme/platform/..."}})
+	if err != nil {
+		t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+	}
+	expected := map[string]string{
+		"Service": "example.com/app/service",
+		"Client":  "github.com/Acme/platform/auth",
+	}
+	if got := componentPackages(components); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	// Include patterns narrow down the local files only, not the vendored module
+	components, _, err = ParseComponentsWithOptions(appDir, ParseOptions{
+		Modules: []string{"github.com/Acme/platform/..."},
+		Include: []string{"service/**"},
+	})
+	if err != nil {
+		t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+	}
+	if got := componentPackages(components); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v with an include pattern, got %v", expected, got)
+	}
+}
+
+func TestParseComponents_ReplacedModule(t *testing.T) {
+	t.Setenv("GOWORK", "off")
+	tmpDir := t.TempDir()
+	writeFiles(t, filepath.Join(tmpDir, "platform"), platformFiles)
+	writeFiles(t, filepath.Join(tmpDir, "app"), appFiles)
+	writeFiles(t, filepath.Join(tmpDir, "app"), map[string]string{
+		"go.mod": appFiles["go.mod"] + "\nreplace github.com/Acme/platform => ../platform\n",
+	})
+
+	components, _, err := ParseComponentsWithOptions(filepath.Join(tmpDir, "app"), ParseOptions{Modules: []string{"github.com/Acme/platform/audit"}})
+	if err != nil {
+		t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+	}
+	expected := map[string]string{
+		"Service": "example.com/app/service",
+		"Logger":  "github.com/Acme/platform/audit",
+	}
+	if got := componentPackages(components); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	if got := escapeModulePath("github.com/Acme/BigCorp@v1.0.0-RC"); got != filepath.FromSlash("github.com/!acme/!big!corp@v1.0.0-!r!c") {
+		t.Errorf("Unexpected escaped path %s", got)
+	}
+}
@@ -0,0 +1,106 @@
+package wire
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\ntwo\nthree\nfour\nFIVE\nsix\nseven\neight\nnine\nten\neleven\n"
+
+	diff := UnifiedDiff("a/wire_gen.go", "b/wire_gen.go", []byte(a), []byte(b))
+	expected := `--- a/wire_gen.go
++++ b/wire_gen.go
+@@ -2,9 +2,10 @@
+ two
+ three
+ four
+-five
++FIVE
+ six
+ seven
+ eight
+ nine
+ ten
++eleven
+`
+	if diff != expected {
+		t.Errorf("Unexpected diff:\n%s\nexpected:\n%s", diff, expected)
+	}
+}
+
+func TestUnifiedDiffSeparateHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 30; i++ {
+		line := fmt.Sprintf("line %d", i+1)
+		a = append(a, line)
+		b = append(b, line)
+	}
+	b[2] = "changed"
+	b[25] = "changed"
+
+	diff := UnifiedDiff("a", "b", []byte(strings.Join(a, "\n")), []byte(strings.Join(b, "\n")))
+	if strings.Count(diff, "@@ -") != 2 {
+		t.Errorf("Expected two hunks for distant changes, got:\n%s", diff)
+	}
+	if !strings.Contains(diff, "@@ -1,6 +1,6 @@") || !strings.Contains(diff, "@@ -23,7 +23,7 @@") {
+		t.Errorf("Unexpected hunk headers:\n%s", diff)
+	}
+}
+
+func TestUnifiedDiffEqual(t *testing.T) {
+	if diff := UnifiedDiff("a", "b", []byte("same\n"), []byte("same\n")); diff != "" {
+		t.Errorf("Expected no diff for equal content, got:\n%s", diff)
+	}
+	if diff := UnifiedDiff("a", "b", nil, []byte("new\n")); !strings.Contains(diff, "@@ -0,0 +1,1 @@\n+new") {
+		t.Errorf("Unexpected diff for new file:\n%s", diff)
+	}
+}
+
+func TestDiffLinesLarge(t *testing.T) {
+	// Every third line changes, the script must still turn a into b
+	var a, b []string
+	for i := 0; i < 3000; i++ {
+		a = append(a, fmt.Sprintf("line %d", i))
+		if i%3 == 0 {
+			b = append(b, fmt.Sprintf("changed %d", i))
+		} else {
+			b = append(b, a[i])
+		}
+	}
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	before := stats.TotalAlloc
+	ops := diffLines(a, b)
+	runtime.ReadMemStats(&stats)
+	if allocated := stats.TotalAlloc - before; allocated > 64<<20 {
+		t.Errorf("Expected the diff to allocate less than 64 MB, got %d MB", allocated>>20)
+	}
+
+	var fromA, toB []string
+	for _, op := range ops {
+		if op.kind != '+' {
+			fromA = append(fromA, op.line)
+		}
+		if op.kind != '-' {
+			toB = append(toB, op.line)
+		}
+	}
+	if !slices.Equal(fromA, a) || !slices.Equal(toB, b) || len(ops) != 4000 {
+		t.Errorf("Expected a shortest script turning a into b, got %d ops", len(ops))
+	}
+
+	// A complete rewrite replaces every line instead of searching further
+	rewritten := make([]string, len(a))
+	for i := range a {
+		rewritten[i] = "new " + a[i]
+	}
+	ops = diffLines(a, rewritten)
+	if len(ops) != 6000 || ops[0].kind != '-' || ops[3000].kind != '+' {
+		t.Errorf("Expected a rewrite to replace every line, got %d ops", len(ops))
+	}
+}
@@ -0,0 +1,197 @@
+package wire
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Stable rule IDs for analyzer findings, used by baselines, suppressions and reporters
+const (
+	RuleCircularDependency   = "circular-dependency"
+	RuleUnusedComponent      = "unused-component"
+	RuleUnresolvedDependency = "unresolved-dependency"
+	RuleQualifierConflict    = "qualifier-conflict"
+	RulePrivateComponent     = "private-component"
+	RuleAll                  = "all"
+)
+
+// ruleAliases maps the short names accepted in suppressions to rule IDs
+var ruleAliases = map[string]string{
+	"cycle":      RuleCircularDependency,
+	"circular":   RuleCircularDependency,
+	"unused":     RuleUnusedComponent,
+	"orphaned":   RuleUnresolvedDependency,
+	"unresolved": RuleUnresolvedDependency,
+	"qualifier":  RuleQualifierConflict,
+	"private":    RulePrivateComponent,
+	"*":          RuleAll,
+}
+
+// normalizeRuleID resolves a rule alias to its rule ID
+func normalizeRuleID(rule string) string {
+	rule = strings.ToLower(rule)
+	if id, ok := ruleAliases[rule]; ok {
+		return id
+	}
+	return rule
+}
+
+// Finding is a single analyzer result with a stable identity
+type Finding struct {
+	RuleID            string `json:"rule"`
+	Severity          string `json:"severity"` // "error" or "warning"
+	Message           string `json:"message"`
+	Component         string `json:"component,omitempty"`
+	FieldName         string `json:"field,omitempty"`
+	SourceFile        string `json:"file,omitempty"`
+	LineNumber        int    `json:"line,omitempty"`
+	Column            int    `json:"column,omitempty"`
+	Fingerprint       string `json:"fingerprint"`                 // Location independent identity used by baselines
+	SuppressionReason string `json:"suppressionReason,omitempty"` // Set when the finding is suppressed
+}
+
+// collectFindings turns an analysis result into findings and splits off the
+// ones suppressed by //ioc:ignore directives or ignore tags
+func (a *DependencyAnalyzer) collectFindings(result *AnalysisResult) ([]Finding, []Finding) {
+	index := a.componentsByKey()
+	var findings []Finding
+
+	for _, cycle := range result.CircularDependencies {
+		path := canonicalCycle(cycle.Path)
+		comp := index[path[0]]
+		findings = append(findings, Finding{
+			RuleID:      RuleCircularDependency,
+			Severity:    "error",
+			Message:     cycle.Description,
+			Component:   path[0],
+			SourceFile:  comp.SourceFile,
+			LineNumber:  comp.LineNumber,
+			Fingerprint: RuleCircularDependency + ":" + strings.Join(path, ">"),
+		})
+	}
+
+	unusedMessage := "Component %s is not used as a dependency by any other component"
+	if len(result.EntryPoints) > 0 {
+		unusedMessage = "Component %s is not reachable from any entry point"
+	}
+	for _, comp := range result.UnusedComponents {
+		key := componentKey(comp)
+		findings = append(findings, Finding{
+			RuleID:      RuleUnusedComponent,
+			Severity:    "warning",
+			Message:     fmt.Sprintf(unusedMessage, key),
+			Component:   key,
+			SourceFile:  comp.SourceFile,
+			LineNumber:  comp.LineNumber,
+			Fingerprint: RuleUnusedComponent + ":" + key,
+		})
+	}
+
+	for _, comp := range result.OrphanedComponents {
+		key := componentKey(comp)
+		for _, dep := range comp.Dependencies {
+			if len(a.findDependencyComponents(dep)) > 0 {
+				continue
+			}
+			findings = append(findings, Finding{
+				RuleID:      RuleUnresolvedDependency,
+				Severity:    "error",
+				Message:     fmt.Sprintf("Could not resolve dependency '%s' with qualifier '%s' for field %s of %s", dep.Type, dep.Qualifier, dep.FieldName, key),
+				Component:   key,
+				FieldName:   dep.FieldName,
+				SourceFile:  comp.SourceFile,
+				LineNumber:  dep.LineNumber,
+				Column:      dep.Column,
+				Fingerprint: RuleUnresolvedDependency + ":" + key + "." + dep.FieldName,
+			})
+		}
+	}
+
+	for _, conflict := range result.QualifierConflicts {
+		conflicting := append([]string(nil), conflict.Conflicting...)
+		sort.Strings(conflicting)
+		comp := index[conflicting[0]]
+		findings = append(findings, Finding{
+			RuleID:      RuleQualifierConflict,
+			Severity:    strings.ToLower(conflict.Severity),
+			Message:     fmt.Sprintf("Interface %s has multiple implementations with qualifier '%s': %s", conflict.Interface, conflict.Qualifier, strings.Join(conflicting, ", ")),
+			Component:   conflicting[0],
+			SourceFile:  comp.SourceFile,
+			LineNumber:  comp.LineNumber,
+			Fingerprint: RuleQualifierConflict + ":" + conflict.Interface + ":" + conflict.Qualifier,
+		})
+	}
+
+	for _, access := range result.PrivateAccesses {
+		key := componentKey(access.Component)
+		findings = append(findings, Finding{
+			RuleID:      RulePrivateComponent,
+			Severity:    "error",
+			Message:     access.Message(),
+			Component:   key,
+			FieldName:   access.Dependency.FieldName,
+			SourceFile:  access.Component.SourceFile,
+			LineNumber:  access.Dependency.LineNumber,
+			Column:      access.Dependency.Column,
+			Fingerprint: RulePrivateComponent + ":" + key + "." + access.Dependency.FieldName,
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Fingerprint < findings[j].Fingerprint
+	})
+
+	var active, suppressed []Finding
+	for _, finding := range findings {
+		if reason, ok := a.suppressionFor(finding, result); ok {
+			finding.SuppressionReason = reason
+			suppressed = append(suppressed, finding)
+		} else {
+			active = append(active, finding)
+		}
+	}
+	return active, suppressed
+}
+
+// suppressionFor returns the suppression reason for a finding, checking every
+// component involved in it
+func (a *DependencyAnalyzer) suppressionFor(finding Finding, result *AnalysisResult) (string, bool) {
+	involved := []string{finding.Component}
+	if finding.RuleID == RuleQualifierConflict {
+		for _, conflict := range result.QualifierConflicts {
+			if finding.Fingerprint == RuleQualifierConflict+":"+conflict.Interface+":"+conflict.Qualifier {
+				involved = conflict.Conflicting
+			}
+		}
+	}
+
+	index := a.componentsByKey()
+	for _, key := range involved {
+		suppressions := index[key].Suppressions
+		if reason, ok := suppressions[finding.RuleID]; ok {
+			return reason, true
+		}
+		if reason, ok := suppressions[RuleAll]; ok {
+			return reason, true
+		}
+	}
+	return "", false
+}
+
+// canonicalCycle rotates a cycle path (with the start repeated at the end) so
+// that it starts at its smallest element, giving every cycle a stable identity
+func canonicalCycle(path []string) []string {
+	nodes := path
+	if len(nodes) > 1 && nodes[0] == nodes[len(nodes)-1] {
+		nodes = nodes[:len(nodes)-1]
+	}
+	start := 0
+	for i, node := range nodes {
+		if node < nodes[start] {
+			start = i
+		}
+	}
+	rotated := append(append([]string(nil), nodes[start:]...), nodes[:start]...)
+	return append(rotated, rotated[0])
+}
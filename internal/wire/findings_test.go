@@ -0,0 +1,83 @@
+package wire
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzer_Findings(t *testing.T) {
+	components := []Component{
+		{
+			Name:       "ConsoleLogger",
+			Type:       "ConsoleLogger",
+			Package:    "example.com/app/logger",
+			Implements: []string{"example.com/app/logger.Logger"},
+		},
+		{
+			Name:       "FileLogger",
+			Type:       "FileLogger",
+			Package:    "example.com/app/logger",
+			Implements: []string{"example.com/app/logger.Logger"},
+		},
+		{
+			Name:    "Server",
+			Type:    "Server",
+			Package: "example.com/app/server",
+			Dependencies: []Dependency{
+				{FieldName: "Cache", Type: "cache.Cache", LineNumber: 9},
+			},
+			Suppressions: map[string]string{RuleUnusedComponent: "entry point"},
+		},
+	}
+
+	result := NewAnalyzer(components).PerformComprehensiveAnalysis()
+
+	rules := make(map[string]int)
+	for _, finding := range result.Findings {
+		rules[finding.RuleID]++
+		if finding.Fingerprint == "" || !strings.HasPrefix(finding.Fingerprint, finding.RuleID+":") {
+			t.Errorf("Finding has no stable fingerprint: %+v", finding)
+		}
+	}
+
+	// Both loggers and the server are unused, the server one is suppressed
+	if rules[RuleUnusedComponent] != 2 {
+		t.Errorf("Expected 2 active unused findings, got %d", rules[RuleUnusedComponent])
+	}
+	if rules[RuleQualifierConflict] != 1 {
+		t.Errorf("Expected 1 qualifier conflict finding, got %d", rules[RuleQualifierConflict])
+	}
+	if rules[RuleUnresolvedDependency] != 1 {
+		t.Errorf("Expected 1 unresolved dependency finding, got %d", rules[RuleUnresolvedDependency])
+	}
+
+	if len(result.SuppressedFindings) != 1 || result.SuppressedFindings[0].SuppressionReason != "entry point" {
+		t.Errorf("Expected the server finding to be suppressed with its reason, got %+v", result.SuppressedFindings)
+	}
+
+	for _, finding := range result.Findings {
+		if finding.RuleID == RuleUnresolvedDependency && (finding.FieldName != "Cache" || finding.LineNumber != 9) {
+			t.Errorf("Expected unresolved finding to point at the Cache field, got %+v", finding)
+		}
+	}
+}
+
+func TestCanonicalCycle(t *testing.T) {
+	a := canonicalCycle([]string{"b", "c", "a", "b"})
+	b := canonicalCycle([]string{"a", "b", "c", "a"})
+	if strings.Join(a, ">") != strings.Join(b, ">") {
+		t.Errorf("Expected rotations of the same cycle to be equal, got %v and %v", a, b)
+	}
+	if strings.Join(a, ">") != "a>b>c>a" {
+		t.Errorf("Unexpected canonical cycle %v", a)
+	}
+}
+
+func TestNormalizeRuleID(t *testing.T) {
+	if normalizeRuleID("Unused") != RuleUnusedComponent {
+		t.Error("Expected unused alias to map to the unused-component rule")
+	}
+	if normalizeRuleID(RuleQualifierConflict) != RuleQualifierConflict {
+		t.Error("Expected rule IDs to map to themselves")
+	}
+}
@@ -3,8 +3,8 @@ package wire
 import (
 	"bytes"
 	"fmt"
+	"go/format"
 	"log"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -15,19 +15,46 @@ import (
 // Generator handles the code generation process for dependency injection
 type Generator struct {
 	components []Component     // List of all components to be wired
+	index      *componentIndex // Resolution index shared with the analyzer
 	visited    map[string]bool // Tracks visited components during dependency resolution
 	cyclicMap  map[string]bool // Tracks cyclic dependencies
+
+	outputDir        string // Directory of a named container's wire_gen.go, empty for <baseDir>/wire
+	packageName      string // Package of the generated file
+	generateArgs     string // Arguments of the go:generate directive
+	stereotypeConfig string // Absolute stereotype config recorded by in-package test constructors
+
+	roots []string // Keys of the components a narrowed Initialize returns, empty for the whole Container
 }
 
 // templateData holds the data needed for code template generation
 type templateData struct {
-	Imports    []string        // List of packages to import
-	Components []componentInit // List of component initializations
+	Package      string          // Package of the generated file
+	GenerateArgs string          // Arguments iocgen is run with by go:generate
+	Imports      []string        // List of packages to import
+	Components   []componentInit // List of component initializations
+	Modules      []moduleInit    // Sub-containers of the container modules
+	Roots        []rootInit      // Components returned by a narrowed Initialize
+	Options      []optionInit    // Typed options of InitializeWithOverrides
+	Overridable  bool            // Whether any dependency is injected through an interface option
+	Replaceable  bool            // Whether any component is skipped when its interface options replace it
+	Container    string          // Type name of the container, unexported when Initialize is narrowed
+}
+
+// moduleInit represents the sub-container holding the components of a module
+type moduleInit struct {
+	Name       string          // Module name from the Module marker
+	Field      string          // Field of the sub-container in Container
+	Type       string          // Type name of the sub-container
+	Components []componentInit // Components of the module, in initialization order
 }
 
 // componentInit represents a single component's initialization data
 type componentInit struct {
 	VarName       string         // Variable name for the component instance
+	FieldName     string         // Field holding the instance in its container
+	Ref           string         // Path of the instance relative to Container
+	Module        string         // Container module of the component, empty for the root container
 	Type          string         // Component type name
 	Package       string         // Package path where component is defined
 	Dependencies  []componentDep // List of dependencies for this component
@@ -35,12 +62,19 @@ type componentInit struct {
 	PostConstruct bool           // Whether component has PostConstruct method
 	PreDestroy    bool           // Whether component has PreDestroy method
 	Constructor   string         // Name of the constructor function
+	Overrides     []overrideSite // Interface options replacing the component wherever it is injected
 }
 
 // componentDep represents a single dependency of a component
 type componentDep struct {
 	FieldName string // Name of the field in the struct
 	VarName   string // Variable name of the dependency instance
+	Ref       string // Path of the dependency instance relative to Container
+	Qualifier string // Qualifier of the dependency
+
+	InterfacePackage string // Import path of the interface the field injects, empty for the component type
+	InterfaceName    string // Name of that interface
+	Override         string // Override field replacing implementations of the interface
 }
 
 // interfaceReg represents an interface implementation registration
@@ -50,34 +84,83 @@ type interfaceReg struct {
 	VarName   string // Variable name of the implementing component
 }
 
-// NewGenerator creates a new Generator instance with the provided components
+// NewGenerator creates a new Generator instance with the provided components.
+// Components are ordered by package and type so that the generated code does not
+// depend on the order in which the file system walk discovered them
 func NewGenerator(components []Component) *Generator {
+	sorted := append([]Component(nil), components...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return componentKey(sorted[i]) < componentKey(sorted[j])
+	})
+
 	return &Generator{
-		components: components,
+		components: sorted,
+		index:      newComponentIndex(sorted),
 		visited:    make(map[string]bool),
 		cyclicMap:  make(map[string]bool),
+
+		packageName:  "wire",
+		generateArgs: "--dir=../",
 	}
 }
 
+// Components returns the components the generator wires, after narrowing to
+// profiles and roots, in the order they are hashed into the generated header
+func (g *Generator) Components() []Component {
+	return g.components
+}
+
 // Generate performs the code generation process for dependency injection
 func (g *Generator) Generate(baseDir string) error {
 	startTime := time.Now()
 
+	code, err := g.GenerateCode()
+	if err != nil {
+		return err
+	}
+
+	// Write the generated code into the wire directory, or the output package of a named container
+	wireDir := filepath.Join(baseDir, "wire")
+	if g.outputDir != "" {
+		wireDir = g.outputDir
+	}
+	if err := writeGeneratedFile(wireDir, code); err != nil {
+		return err
+	}
+
+	log.Printf("Generated wire_gen.go in %s (completed in %v)", wireDir, time.Since(startTime))
+
+	return nil
+}
+
+// GenerateCode renders the content of wire_gen.go in memory without touching the file system
+func (g *Generator) GenerateCode() ([]byte, error) {
 	// Validate that we have components to process
 	if len(g.components) == 0 {
-		return fmt.Errorf("no components found")
+		return nil, fmt.Errorf("no components found")
 	}
 
-	// Ensure the wire directory exists for output
-	wireDir := filepath.Join(baseDir, "wire")
-	if err := os.MkdirAll(wireDir, 0755); err != nil {
-		return fmt.Errorf("failed to create wire directory: %w", err)
+	// Reset traversal state so the generator can be run more than once
+	g.visited = make(map[string]bool)
+	g.cyclicMap = make(map[string]bool)
+
+	// Components may only inject what other modules export
+	if err := g.checkModuleAccess(); err != nil {
+		return nil, err
 	}
 
 	// Sort components based on their dependencies
-	orderedComponents := g.topologicalSort()
+	orderedComponents, err := g.topologicalSort()
+	if err != nil {
+		return nil, err
+	}
 	// Generate initialization code for each component
-	inits := g.generateComponentInits(orderedComponents)
+	inits, err := g.generateComponentInits(orderedComponents)
+	if err != nil {
+		return nil, err
+	}
+	options := overrideOptions(inits)
+	overrideSites(inits, g.roots)
 
 	// Define template helper functions
 	funcMap := template.FuncMap{
@@ -85,6 +168,9 @@ func (g *Generator) Generate(baseDir string) error {
 			parts := strings.Split(pkg, "/")
 			return parts[len(parts)-1]
 		},
+		"hashes": func() []string {
+			return modelHeader(g.components)
+		},
 		"iterate": func(count int) []int {
 			var result []int
 			for i := count - 1; i >= 0; i-- {
@@ -96,65 +182,175 @@ func (g *Generator) Generate(baseDir string) error {
 
 	// Create and parse the code generation template
 	tmpl := template.New("wire").Funcs(funcMap)
-	tmpl, err := tmpl.Parse(`// File: wire_gen.go
+	tmpl, err = tmpl.Parse(`// File: wire_gen.go
 // Code generated by Go IoC. DO NOT EDIT.
-//go:generate go run github.com/tuhuynh27/go-ioc/cmd/iocgen --dir=../
-package wire
+//go:generate go run github.com/tuhuynh27/go-ioc/cmd/iocgen {{.GenerateArgs}}
+{{- range hashes}}
+{{.}}{{end}}
+
+package {{.Package}}
 
 import ({{range .Imports}}
     "{{.}}"{{end}}
 )
 
-type Container struct {
-    {{- range $comp := .Components}}
-    {{$comp.Type}} *{{$comp.Package | base}}.{{$comp.Type}}
+{{- if .Roots}}
+// Roots holds the components Initialize was generated for
+type Roots struct {
+    {{- range $root := .Roots}}
+    {{$root.Field}} *{{$root.Package | base}}.{{$root.Type}}
     {{- end}}
 }
 
-func Initialize() (*Container, func()) {
-    container := &Container{}{{range $comp := .Components}}
-    {{if $comp.Constructor}}
-    container.{{$comp.Type}} = {{$comp.Package | base}}.{{$comp.Constructor}}({{- range $i, $dep := $comp.Dependencies}}{{if $i}}, {{end}}container.{{$dep.VarName}}{{- end}}){{else}}
-    container.{{$comp.Type}} = &{{$comp.Package | base}}.{{$comp.Type}}{{if $comp.Dependencies}}{
-        {{- range $dep := $comp.Dependencies}}
-        {{$dep.FieldName}}: container.{{$dep.VarName}},{{- end}}
-    }{{else}}{}{{end}}{{end}}{{if $comp.PostConstruct}}
-    container.{{$comp.Type}}.PostConstruct(){{- end}}{{end}}
+// container holds the roots and everything they depend on
+{{- end}}
+type {{.Container}} struct {
+    {{- range $comp := .Components}}{{if not $comp.Module}}
+    {{$comp.FieldName}} *{{$comp.Package | base}}.{{$comp.Type}}
+    {{- end}}{{end}}
+    {{- range $mod := .Modules}}
+    {{$mod.Field}} *{{$mod.Type}}
+    {{- end}}
+}
+{{range $mod := .Modules}}
+// {{$mod.Type}} holds the components of the {{$mod.Name}} module. Unexported
+// fields are private to the module
+type {{$mod.Type}} struct {
+    {{- range $comp := $mod.Components}}
+    {{$comp.FieldName}} *{{$comp.Package | base}}.{{$comp.Type}}
+    {{- end}}
+}
+{{end}}
+func Initialize() (*{{template "result" .}}, func()) {
+    return InitializeWithOverrides()
+}
+
+// Option replaces a component before the components depending on it are built,
+// see InitializeWithOverrides
+type Option func(*overrides)
+
+// overrides holds the replacements passed to InitializeWithOverrides
+type overrides struct {
+    {{- range .Options}}
+    {{.Field}} {{if .Interface}}map[string]{{end}}{{.Type}}
+    {{- end}}
+}
+{{range .Options}}
+// {{.Name}} replaces {{.Subject}}{{if .Interface}}
+// wherever it is injected{{end}}
+func {{.Name}}({{if .Interface}}qualifier string, {{end}}impl {{.Type}}) Option {
+    return func(o *overrides) {
+        o.{{.Field}}{{if .Interface}}[qualifier]{{end}} = impl
+    }
+}
+{{end}}
+// InitializeWithOverrides builds the same components as Initialize, except for
+// the replaced ones: their replacements are injected instead, and PostConstruct
+// and PreDestroy are not called on them
+{{- if .Replaceable}}. An implementation replaced through its
+// interface wherever it is injected is not built, its field stays nil{{end}}
+func InitializeWithOverrides(opts ...Option) (*{{template "result" .}}, func()) {
+    o := &overrides{ {{- range .Options}}{{if .Interface}}
+        {{.Field}}: map[string]{{.Type}}{},{{end}}{{end}}
+    }
+    for _, opt := range opts {
+        opt(o)
+    }
+
+    container := &{{.Container}}{ {{- range $mod := .Modules}}
+        {{$mod.Field}}: &{{$mod.Type}}{},
+    {{- end}}{{if .Modules}}
+    {{end}}}{{range $comp := .Components}}
+
+    container.{{$comp.Ref}} = o.{{$comp.VarName}}
+    if container.{{$comp.Ref}} == nil{{if $comp.Overrides}} && {{template "needed" $comp.Overrides}}{{end}} {
+    {{- if $comp.Constructor}}
+        container.{{$comp.Ref}} = {{$comp.Package | base}}.{{$comp.Constructor}}({{- range $i, $dep := $comp.Dependencies}}{{if $i}}, {{end}}{{template "dep" $dep}}{{- end}}){{else}}
+        container.{{$comp.Ref}} = &{{$comp.Package | base}}.{{$comp.Type}}{{if $comp.Dependencies}}{
+            {{- range $dep := $comp.Dependencies}}
+            {{$dep.FieldName}}: {{template "dep" $dep}},{{- end}}
+        }{{else}}{}{{end}}{{end}}{{if $comp.PostConstruct}}
+        container.{{$comp.Ref}}.PostConstruct(){{- end}}
+    }{{end}}
 
     cleanup := func() {
         {{- range $i := len .Components | iterate}}
         {{- with index $.Components $i}}
         {{- if .PreDestroy}}
-        container.{{.Type}}.PreDestroy()
+        if o.{{.VarName}} == nil{{if .Overrides}} && container.{{.Ref}} != nil{{end}} {
+            container.{{.Ref}}.PreDestroy()
+        }
         {{- end}}
         {{- end}}
         {{- end}}
     }
+    {{- if .Roots}}
+
+    return &Roots{
+        {{- range $root := .Roots}}
+        {{$root.Field}}: container.{{$root.Ref}},
+        {{- end}}
+    }, cleanup
+    {{- else}}
 
     return container, cleanup
-}`)
+    {{- end}}
+}
+{{- if .Overridable}}
+
+// override returns the replacement registered for the qualifier, or the component
+func override[T any](replacements map[string]T, qualifier string, component T) T {
+    if impl, ok := replacements[qualifier]; ok {
+        return impl
+    }
+    return component
+}
+{{- end}}
+{{- if .Replaceable}}
+
+// overridden reports whether a replacement is registered for the qualifier
+func overridden[T any](replacements map[string]T, qualifier string) bool {
+    _, ok := replacements[qualifier]
+    return ok
+}
+{{- end}}
+{{define "result"}}{{if .Roots}}Roots{{else}}Container{{end}}{{end}}
+{{- define "dep"}}
+    {{- if .Override}}override[{{.InterfacePackage | base}}.{{.InterfaceName}}](o.{{.Override}}, {{printf "%q" .Qualifier}}, container.{{.Ref}})
+    {{- else}}container.{{.Ref}}{{end}}
+{{- end}}
+{{- define "needed"}}
+    {{- if gt (len .) 1}}({{end}}
+    {{- range $i, $site := .}}{{if $i}} || {{end}}!overridden(o.{{$site.Field}}, {{printf "%q" $site.Qualifier}}){{end}}
+    {{- if gt (len .) 1}}){{end}}
+{{- end}}`)
 	if err != nil {
-		return fmt.Errorf("template parsing failed: %w", err)
+		return nil, fmt.Errorf("template parsing failed: %w", err)
 	}
 
 	// Collect and deduplicate required imports
 	imports := make(map[string]bool)
 	for _, comp := range g.components {
 		// Only include imports with valid package paths
-		if strings.Contains(comp.Package, "/") && comp.Package != "github.com/tuhuynh27/go-ioc/ioc" {
+		if strings.Contains(comp.Package, "/") && comp.Package != markersPackage {
 			imports[comp.Package] = true
 		}
 		// Add imports for implemented interfaces
 		for _, iface := range comp.Implements {
 			if idx := strings.LastIndex(iface, "."); idx != -1 {
 				pkgPath := iface[:idx]
-				if strings.Contains(pkgPath, "/") && pkgPath != "github.com/tuhuynh27/go-ioc/ioc" {
+				if strings.Contains(pkgPath, "/") && pkgPath != markersPackage {
 					imports[pkgPath] = true
 				}
 			}
 		}
 	}
 
+	// Interfaces replaced by options may live in packages without components
+	for _, option := range options {
+		imports[option.Package] = true
+	}
+
 	// Convert imports map to sorted slice for consistent output
 	var importSlice []string
 	for imp := range imports {
@@ -164,25 +360,73 @@ func Initialize() (*Container, func()) {
 
 	// Prepare data for template execution
 	data := templateData{
-		Imports:    importSlice,
-		Components: inits,
+		Package:      g.packageName,
+		GenerateArgs: g.generateArgs,
+		Imports:      importSlice,
+		Components:   inits,
+		Modules:      moduleInits(inits),
+		Roots:        g.rootInits(inits),
+		Options:      options,
+		Container:    "Container",
+	}
+	if len(data.Roots) > 0 {
+		data.Container = "container"
+	}
+	for _, option := range options {
+		data.Overridable = data.Overridable || option.Interface
+	}
+	for _, init := range inits {
+		data.Replaceable = data.Replaceable || len(init.Overrides) > 0
 	}
 
 	// Generate the code using the template
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		return fmt.Errorf("template execution failed: %w", err)
+		return nil, fmt.Errorf("template execution failed: %w", err)
 	}
 
-	// Write the generated code to file
-	outputPath := filepath.Join(wireDir, "wire_gen.go")
-	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write generated code: %w", err)
+	// Format the output so it is gofmt-clean regardless of the template layout
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code failed: %w", err)
 	}
 
-	log.Printf("Generated wire_gen.go in %s (completed in %v)", wireDir, time.Since(startTime))
+	return code, nil
+}
 
-	return nil
+// checkModuleAccess rejects dependencies on components that are private to another module
+func (g *Generator) checkModuleAccess() error {
+	accesses := findPrivateAccesses(g.components, g.index)
+	if len(accesses) == 0 {
+		return nil
+	}
+	var messages []string
+	for _, access := range accesses {
+		messages = append(messages, fmt.Sprintf("  %s:%d: %s", access.Component.SourceFile, access.Dependency.LineNumber, access.Message()))
+	}
+	return fmt.Errorf("module boundary violations:\n%s", strings.Join(messages, "\n"))
+}
+
+// moduleInits groups the component initializations into one sub-container per
+// module, ordered by module name
+func moduleInits(inits []componentInit) []moduleInit {
+	byModule := make(map[string]*moduleInit)
+	for _, init := range inits {
+		if init.Module == "" {
+			continue
+		}
+		if byModule[init.Module] == nil {
+			field := exportedIdent(init.Module)
+			byModule[init.Module] = &moduleInit{Name: init.Module, Field: field, Type: field + "Module"}
+		}
+		byModule[init.Module].Components = append(byModule[init.Module].Components, init)
+	}
+
+	var modules []moduleInit
+	for _, name := range sortedKeys(byModule) {
+		modules = append(modules, *byModule[name])
+	}
+	return modules
 }
 
 // findPackageForType finds the full package path for a given package name
@@ -195,8 +439,9 @@ func (g *Generator) findPackageForType(pkgName string) string {
 	return ""
 }
 
-// generateComponentInits creates initialization data for all components
-func (g *Generator) generateComponentInits(components []Component) []componentInit {
+// generateComponentInits creates initialization data for all components. It
+// fails when a dependency cannot be resolved
+func (g *Generator) generateComponentInits(components []Component) ([]componentInit, error) {
 	var inits []componentInit
 	varNames := make(map[string]string)
 	nameCount := make(map[string]int)
@@ -216,10 +461,29 @@ func (g *Generator) generateComponentInits(components []Component) []componentIn
 		varNames[comp.Package+"."+comp.Type] = varName
 	}
 
+	// Module components live in the sub-container of their module, where the
+	// components the module does not export are unexported fields
+	refs := make(map[string]string)
+	fieldNames := make(map[string]string)
+	for _, comp := range components {
+		key := comp.Package + "." + comp.Type
+		fieldNames[key] = varNames[key]
+		refs[key] = varNames[key]
+		if comp.Module != "" {
+			if !comp.Exported {
+				fieldNames[key] = unexportedIdent(varNames[key])
+			}
+			refs[key] = exportedIdent(comp.Module) + "." + fieldNames[key]
+		}
+	}
+
 	// Second pass: create component initializations with dependencies and interface registrations
 	for _, comp := range components {
 		init := componentInit{
 			VarName:       varNames[comp.Package+"."+comp.Type],
+			FieldName:     fieldNames[comp.Package+"."+comp.Type],
+			Ref:           refs[comp.Package+"."+comp.Type],
+			Module:        comp.Module,
 			Type:          comp.Type,
 			Package:       comp.Package,
 			Dependencies:  []componentDep{},
@@ -231,49 +495,29 @@ func (g *Generator) generateComponentInits(components []Component) []componentIn
 
 		// Process each dependency for the component
 		for _, dep := range comp.Dependencies {
-			depVarName := ""
-
-			if strings.Contains(dep.Type, ".") {
-				// Handle interface dependencies
-				parts := strings.Split(dep.Type, ".")
-				pkgName := parts[0]
-				interfaceName := parts[1]
-
-				// Find matching component that implements the interface
-				for _, c := range components {
-					if strings.HasSuffix(c.Package, pkgName) {
-						// Check interface implementations
-						for _, impl := range c.Implements {
-							if strings.HasSuffix(impl, interfaceName) && c.Qualifier == dep.Qualifier {
-								depVarName = varNames[c.Package+"."+c.Type]
-								break
-							}
-						}
-						// Check direct type match
-						if c.Type == interfaceName && c.Qualifier == dep.Qualifier {
-							depVarName = varNames[c.Package+"."+c.Type]
-							break
-						}
-					}
-				}
-			} else {
-				// Handle direct type dependencies
-				for _, c := range components {
-					if c.Type == dep.Type {
-						depVarName = varNames[c.Package+"."+c.Type]
-						break
-					}
-				}
+			depVarName, depRef := "", ""
+			var target Component
+
+			// Use the injected component, the Primary one or else the first match in key order
+			if matches := g.index.injected(dep); len(matches) > 0 {
+				target = matches[0]
+				depVarName = varNames[componentKey(target)]
+				depRef = refs[componentKey(target)]
 			}
 
 			// Add dependency if found
 			if depVarName != "" {
+				ifacePkg, ifaceName := g.injectedInterface(dep, target)
 				init.Dependencies = append(init.Dependencies, componentDep{
-					FieldName: dep.FieldName,
-					VarName:   depVarName,
+					FieldName:        dep.FieldName,
+					VarName:          depVarName,
+					Ref:              depRef,
+					Qualifier:        dep.Qualifier,
+					InterfacePackage: ifacePkg,
+					InterfaceName:    ifaceName,
 				})
 			} else {
-				panic(fmt.Sprintf("Error: Could not resolve dependency '%s' with qualifier '%s'\n"+
+				return nil, fmt.Errorf("could not resolve dependency '%s' with qualifier '%s'\n"+
 					"  Component: %s.%s in %s:%d\n"+
 					"  Field: '%s'\n\n"+
 					"Suggestions:\n"+
@@ -282,8 +526,8 @@ func (g *Generator) generateComponentInits(components []Component) []componentIn
 					"3. Verify package is included in scanning scope\n"+
 					"4. Run with --verbose for component discovery details\n"+
 					"5. Use --graph to visualize dependencies",
-					dep.Type, dep.Qualifier, init.Package, init.Type, 
-					comp.SourceFile, comp.LineNumber, dep.FieldName))
+					dep.Type, dep.Qualifier, init.Package, init.Type,
+					comp.SourceFile, comp.LineNumber, dep.FieldName)
 			}
 		}
 
@@ -299,7 +543,7 @@ func (g *Generator) generateComponentInits(components []Component) []componentIn
 		inits = append(inits, init)
 	}
 
-	return inits
+	return inits, nil
 }
 
 // PrintDependencyGraph displays a visual representation of component dependencies
@@ -373,11 +617,22 @@ func (g *Generator) ValidateOnly() error {
 
 	fmt.Printf("🔍 Validating %d components...\n", len(g.components))
 
+	// Check module boundaries before resolving the initialization order
+	if err := g.checkModuleAccess(); err != nil {
+		return err
+	}
+
 	// Sort components based on their dependencies (this will catch circular dependencies)
-	orderedComponents := g.topologicalSort()
+	orderedComponents, err := g.topologicalSort()
+	if err != nil {
+		return err
+	}
 	
 	// Generate initialization code for validation (this will catch missing dependencies)
-	inits := g.generateComponentInits(orderedComponents)
+	inits, err := g.generateComponentInits(orderedComponents)
+	if err != nil {
+		return err
+	}
 
 	// Perform additional validation checks
 	g.validateComponentStructure()
@@ -405,7 +660,7 @@ func (g *Generator) validateComponentStructure() {
 		if len(comp.Implements) > 0 {
 			for _, iface := range comp.Implements {
 				duplicates := g.findDuplicateQualifiers(iface, comp.Qualifier)
-				if len(duplicates) > 1 {
+				if len(duplicates) > 1 && !g.index.singlePrimary(duplicates) {
 					log.Printf("⚠️  Warning: Multiple components implement '%s' with qualifier '%s': %v",
 						iface, comp.Qualifier, duplicates)
 				}
@@ -445,7 +700,7 @@ func (g *Generator) validateQualifierUniqueness() {
 	}
 	
 	for key, components := range qualifierMap {
-		if len(components) > 1 {
+		if len(components) > 1 && !g.index.singlePrimary(components) {
 			parts := strings.Split(key, ":")
 			iface := parts[0]
 			qualifier := parts[1]
@@ -506,6 +761,9 @@ func (g *Generator) ListComponents() {
 		}
 		fmt.Printf(" [%s:%d]\n", comp.SourceFile, comp.LineNumber)
 		
+		if comp.Stereotype != "" {
+			fmt.Printf("   🏷️  Stereotype: %s\n", comp.Stereotype)
+		}
 		if len(comp.Implements) > 0 {
 			fmt.Printf("   📋 Implements: %s\n", strings.Join(comp.Implements, ", "))
 		}
@@ -544,39 +802,34 @@ func (g *Generator) ListComponents() {
 	fmt.Printf("  Interfaces: %d\n", g.countInterfaceImplementations())
 }
 
-// contains checks if a slice contains a string (with suffix matching)
-func contains(slice []string, str string) bool {
-	for _, s := range slice {
-		if strings.HasSuffix(s, str) {
-			return true
-		}
-	}
-	return false
-}
-
-// topologicalSort sorts components based on their dependencies
-func (g *Generator) topologicalSort() []Component {
+// topologicalSort sorts components based on their dependencies. It fails on a
+// dependency cycle
+func (g *Generator) topologicalSort() ([]Component, error) {
 	var ordered []Component
 
 	// First process components with no dependencies
 	for _, comp := range g.components {
 		if len(comp.Dependencies) == 0 && !g.visited[comp.Package+"."+comp.Type] {
-			g.dfs(comp, &ordered)
+			if err := g.dfs(comp, &ordered); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	// Then process remaining components
 	for _, comp := range g.components {
 		if !g.visited[comp.Package+"."+comp.Type] {
-			g.dfs(comp, &ordered)
+			if err := g.dfs(comp, &ordered); err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	return ordered
+	return ordered, nil
 }
 
 // dfs performs a depth-first search to order components by dependencies
-func (g *Generator) dfs(comp Component, ordered *[]Component) {
+func (g *Generator) dfs(comp Component, ordered *[]Component) error {
 	componentKey := comp.Package + "." + comp.Type
 
 	if g.cyclicMap[componentKey] {
@@ -587,48 +840,32 @@ func (g *Generator) dfs(comp Component, ordered *[]Component) {
 		}
 		cyclePath = append(cyclePath, componentKey)
 
-		panic(fmt.Sprintf("Cyclic dependency detected!\n"+
+		return fmt.Errorf("cyclic dependency detected!\n"+
 			"Component %s.%s in %s:%d depends on a component that eventually depends back on it.\n"+
 			"Dependency path: %s\n"+
 			"Please check these components and their dependencies to resolve the cycle.",
 			comp.Package, comp.Type, comp.SourceFile, comp.LineNumber,
-			strings.Join(cyclePath, " -> ")))
+			strings.Join(cyclePath, " -> "))
 	}
 
 	g.cyclicMap[componentKey] = true
 	defer delete(g.cyclicMap, componentKey)
 
 	if g.visited[componentKey] {
-		return
+		return nil
 	}
 
 	g.visited[componentKey] = true
 
 	// Process dependencies before the component itself
 	for _, dep := range comp.Dependencies {
-		if strings.Contains(dep.Type, ".") {
-			parts := strings.Split(dep.Type, ".")
-			pkgName := parts[0]
-			typeName := parts[1]
-
-			// Find and visit each dependency
-			for _, other := range g.components {
-				if strings.HasSuffix(other.Package, pkgName) {
-					// Check both direct type matches and interface implementations
-					if other.Type == typeName || contains(other.Implements, typeName) {
-						g.dfs(other, ordered)
-					}
-				}
-			}
-		} else {
-			// Handle direct type dependencies
-			for _, other := range g.components {
-				if other.Type == dep.Type {
-					g.dfs(other, ordered)
-				}
+		for _, other := range g.index.injected(dep) {
+			if err := g.dfs(other, ordered); err != nil {
+				return err
 			}
 		}
 	}
 
 	*ordered = append(*ordered, comp)
+	return nil
 }
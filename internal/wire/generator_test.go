@@ -1,12 +1,16 @@
 package wire
 
 import (
+	"flag"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 )
 
+var updateGolden = flag.Bool("update", false, "Update the golden files of generator tests")
+
 func TestGenerator_Generate(t *testing.T) {
 	// Create temporary directory for test
 	tmpDir, err := os.MkdirTemp("", "ioc-test-*")
@@ -98,8 +102,10 @@ func TestGenerator_Generate(t *testing.T) {
 		"EmailService *message.EmailService",
 	}
 
+	// Fields are aligned by gofmt, so compare with collapsed whitespace
+	normalized := strings.Join(strings.Fields(contentStr), " ")
 	for _, field := range expectedFields {
-		if !strings.Contains(contentStr, field) {
+		if !strings.Contains(normalized, field) {
 			t.Errorf("Expected field not found: %s", field)
 		}
 	}
@@ -129,7 +135,10 @@ func TestGenerator_GenerateComponentInits(t *testing.T) {
 	}
 
 	gen := NewGenerator(components)
-	inits := gen.generateComponentInits(components)
+	inits, err := gen.generateComponentInits(components)
+	if err != nil {
+		t.Fatalf("generateComponentInits failed: %v", err)
+	}
 
 	if len(inits) != 2 {
 		t.Errorf("Expected 2 component inits, got %d", len(inits))
@@ -255,8 +264,10 @@ func TestGenerator_GenerateWithLifecycleMethods(t *testing.T) {
 		"StdoutLogger *logger.StdoutLogger",
 	}
 
+	// Fields are aligned by gofmt, so compare with collapsed whitespace
+	normalized := strings.Join(strings.Fields(contentStr), " ")
 	for _, field := range expectedFields {
-		if !strings.Contains(contentStr, field) {
+		if !strings.Contains(normalized, field) {
 			t.Errorf("Expected field not found: %s", field)
 		}
 	}
@@ -309,15 +320,11 @@ func TestGenerator_GenerateCyclicDependencies(t *testing.T) {
 	// Create generator
 	gen := NewGenerator(components)
 
-	// Expect panic due to cyclic dependencies
-	defer func() {
-		if r := recover(); r == nil {
-			t.Errorf("Expected panic due to cyclic dependencies, but did not panic")
-		}
-	}()
-
-	// Attempt to generate code
-	gen.Generate(tmpDir) // This should trigger the panic
+	// Expect an error due to cyclic dependencies
+	err = gen.Generate(tmpDir)
+	if err == nil || !strings.Contains(err.Error(), "cyclic dependency detected") {
+		t.Errorf("Expected an error due to cyclic dependencies, got %v", err)
+	}
 }
 
 func TestGenerator_GenerateWithConstructor(t *testing.T) {
@@ -373,8 +380,99 @@ func TestGenerator_GenerateWithConstructor(t *testing.T) {
 	contentStr := string(content)
 
 	// Check for constructor-based initialization
-	expectedInit := "container.EmailService = message.NewEmailService(container.StdoutLogger)"
+	expectedInit := `container.EmailService = message.NewEmailService(override[logger.Logger](o.Logger, "stdout", container.StdoutLogger))`
 	if !strings.Contains(contentStr, expectedInit) {
 		t.Errorf("Expected constructor initialization not found in generated code")
 	}
 }
+
+func TestGenerator_GenerateCode(t *testing.T) {
+	components := []Component{
+		{
+			Name:       "StdoutLogger",
+			Type:       "StdoutLogger",
+			Package:    "example.com/test/logger",
+			Implements: []string{"example.com/test/logger.Logger"},
+		},
+		{
+			Name:    "EmailService",
+			Type:    "EmailService",
+			Package: "example.com/test/message",
+			Dependencies: []Dependency{
+				{FieldName: "Logger", Type: "logger.Logger"},
+			},
+		},
+	}
+
+	gen := NewGenerator(components)
+	first, err := gen.GenerateCode()
+	if err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	second, err := gen.GenerateCode()
+	if err != nil {
+		t.Fatalf("Second GenerateCode failed: %v", err)
+	}
+	if string(first) != string(second) {
+		t.Error("Expected repeated generation to produce identical code")
+	}
+
+	modelHash, hashes, ok := ParseModelHeader(first)
+	if !ok {
+		t.Fatal("Expected generated code to contain a model header")
+	}
+	current := ComputeModelHashes(components)
+	if modelHash != current.ModelHash() || len(hashes) != 2 || hashes["example.com/test/message.EmailService"] != current["example.com/test/message.EmailService"] {
+		t.Errorf("Model header does not match the components: %s %v", modelHash, hashes)
+	}
+}
+
+func TestGenerator_GoldenFiles(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "golden", "*"))
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("No golden fixtures found: %v", err)
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			components, err := ParseComponents(fixture)
+			if err != nil {
+				t.Fatalf("ParseComponents failed: %v", err)
+			}
+
+			code, err := NewGenerator(components).GenerateCode()
+			if err != nil {
+				t.Fatalf("GenerateCode failed: %v", err)
+			}
+
+			goldenPath := filepath.Join(fixture, "wire_gen.golden")
+			if *updateGolden {
+				if err := os.WriteFile(goldenPath, code, 0644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+			}
+			golden, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("Failed to read golden file (run go test -update): %v", err)
+			}
+			if string(code) != string(golden) {
+				t.Errorf("Generated code differs from %s:\n%s", goldenPath, UnifiedDiff("golden", "generated", golden, code))
+			}
+
+			// The output must not depend on the order the file system walk returned
+			rng := rand.New(rand.NewSource(1))
+			for i := 0; i < 10; i++ {
+				shuffled := append([]Component(nil), components...)
+				rng.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+
+				reordered, err := NewGenerator(shuffled).GenerateCode()
+				if err != nil {
+					t.Fatalf("GenerateCode failed for shuffled components: %v", err)
+				}
+				if string(reordered) != string(golden) {
+					t.Fatalf("Generated code depends on component order:\n%s", UnifiedDiff("golden", "shuffled", golden, reordered))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,587 @@
+package wire
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// GraphFormat identifies an output format for dependency graph export
+type GraphFormat string
+
+// Supported graph export formats
+const (
+	GraphFormatDOT      GraphFormat = "dot"
+	GraphFormatMermaid  GraphFormat = "mermaid"
+	GraphFormatPlantUML GraphFormat = "plantuml"
+	GraphFormatGraphML  GraphFormat = "graphml"
+)
+
+// GraphFormats lists every supported graph export format
+var GraphFormats = []GraphFormat{GraphFormatDOT, GraphFormatMermaid, GraphFormatPlantUML, GraphFormatGraphML}
+
+// GraphOptions controls which part of the dependency graph is exported
+type GraphOptions struct {
+	RootComponent string // Only include the subgraph reachable from this component
+	RootPackage   string // Only include the subgraph reachable from components in this package
+	ByStereotype  bool   // Cluster the nodes by stereotype instead of package
+}
+
+// DependencyGraph is the resolved component graph with cycles and missing dependencies marked
+type DependencyGraph struct {
+	Nodes        []GraphNode
+	Edges        []GraphEdge
+	ByStereotype bool // Whether the nodes are clustered by stereotype instead of package
+}
+
+// GraphNode is a single vertex in the dependency graph
+type GraphNode struct {
+	ID         string     // Stable identifier (package.Type, or missing:Type for unsatisfied dependencies)
+	Label      string     // Short display label
+	Package    string     // Package used for clustering
+	Stereotype string     // Role of the component's stereotype, colors the node
+	Component  *Component // The component, nil for unsatisfied dependencies
+	Missing    bool       // Whether this node stands for an unsatisfied dependency
+	InCycle    bool       // Whether this node is part of a dependency cycle
+}
+
+// GraphEdge is a resolved dependency between two graph nodes
+type GraphEdge struct {
+	From      string // ID of the dependent node
+	To        string // ID of the dependency node
+	FieldName string // Autowired field that carries the dependency
+	Qualifier string // Qualifier requested by the field
+	InCycle   bool   // Whether this edge is part of a dependency cycle
+}
+
+// ParseGraphFormat validates a user supplied format name
+func ParseGraphFormat(name string) (GraphFormat, error) {
+	for _, f := range GraphFormats {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+	var names []string
+	for _, f := range GraphFormats {
+		names = append(names, string(f))
+	}
+	return "", fmt.Errorf("unknown graph format %q (supported: %s)", name, strings.Join(names, ", "))
+}
+
+// BuildGraph resolves every autowired dependency into graph edges to the injected
+// components, highlighting
+// cycles and unsatisfied dependencies, optionally restricted to a rooted subgraph
+func (a *DependencyAnalyzer) BuildGraph(opts GraphOptions) (*DependencyGraph, error) {
+	graph := &DependencyGraph{ByStereotype: opts.ByStereotype}
+	nodes := make(map[string]*GraphNode)
+	adjacency := make(map[string][]int)
+
+	for i := range a.components {
+		comp := &a.components[i]
+		key := componentKey(*comp)
+		nodes[key] = &GraphNode{
+			ID:         key,
+			Label:      comp.Type,
+			Package:    comp.Package,
+			Stereotype: comp.Stereotype,
+			Component:  comp,
+		}
+	}
+
+	for i := range a.components {
+		comp := a.components[i]
+		from := componentKey(comp)
+		for _, dep := range comp.Dependencies {
+			targets := a.injectedComponents(dep)
+			if len(targets) == 0 {
+				missingID := "missing:" + dep.Type
+				if dep.Qualifier != "" {
+					missingID += "[" + dep.Qualifier + "]"
+				}
+				if _, ok := nodes[missingID]; !ok {
+					nodes[missingID] = &GraphNode{
+						ID:      missingID,
+						Label:   dep.Type,
+						Package: "",
+						Missing: true,
+					}
+				}
+				adjacency[from] = append(adjacency[from], len(graph.Edges))
+				graph.Edges = append(graph.Edges, GraphEdge{From: from, To: missingID, FieldName: dep.FieldName, Qualifier: dep.Qualifier})
+				continue
+			}
+			for _, target := range targets {
+				adjacency[from] = append(adjacency[from], len(graph.Edges))
+				graph.Edges = append(graph.Edges, GraphEdge{From: from, To: componentKey(target), FieldName: dep.FieldName, Qualifier: dep.Qualifier})
+			}
+		}
+	}
+
+	markCycles(nodes, graph.Edges, adjacency)
+
+	// Restrict to the subgraph reachable from the requested roots
+	include := make(map[string]bool)
+	if opts.RootComponent != "" || opts.RootPackage != "" {
+		var queue []string
+		for _, comp := range a.components {
+			if (opts.RootComponent != "" && matchesComponentRef(comp, opts.RootComponent)) ||
+				(opts.RootPackage != "" && matchesPackageRef(comp.Package, opts.RootPackage)) {
+				queue = append(queue, componentKey(comp))
+			}
+		}
+		if len(queue) == 0 {
+			return nil, fmt.Errorf("no component matches root %q", opts.RootComponent+opts.RootPackage)
+		}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			if include[id] {
+				continue
+			}
+			include[id] = true
+			for _, idx := range adjacency[id] {
+				queue = append(queue, graph.Edges[idx].To)
+			}
+		}
+	} else {
+		for id := range nodes {
+			include[id] = true
+		}
+	}
+
+	var edges []GraphEdge
+	for _, edge := range graph.Edges {
+		if include[edge.From] && include[edge.To] {
+			edges = append(edges, edge)
+		}
+	}
+	graph.Edges = edges
+
+	for id, node := range nodes {
+		if include[id] {
+			graph.Nodes = append(graph.Nodes, *node)
+		}
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+
+	return graph, nil
+}
+
+// markCycles flags every node and edge that belongs to a strongly connected
+// component with more than one member (or a self loop) using Tarjan's algorithm
+func markCycles(nodes map[string]*GraphNode, edges []GraphEdge, adjacency map[string][]int) {
+	index := 0
+	indices := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	sccOf := make(map[string]int)
+	sccSize := make(map[int]int)
+	sccCount := 0
+
+	var ids []string
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var strongConnect func(id string)
+	strongConnect = func(id string) {
+		indices[id] = index
+		lowLink[id] = index
+		index++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, idx := range adjacency[id] {
+			to := edges[idx].To
+			if _, seen := indices[to]; !seen {
+				strongConnect(to)
+				lowLink[id] = min(lowLink[id], lowLink[to])
+			} else if onStack[to] {
+				lowLink[id] = min(lowLink[id], indices[to])
+			}
+		}
+
+		if lowLink[id] == indices[id] {
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				sccOf[top] = sccCount
+				sccSize[sccCount]++
+				if top == id {
+					break
+				}
+			}
+			sccCount++
+		}
+	}
+
+	for _, id := range ids {
+		if _, seen := indices[id]; !seen {
+			strongConnect(id)
+		}
+	}
+
+	for i := range edges {
+		from, to := edges[i].From, edges[i].To
+		if sccOf[from] == sccOf[to] && (sccSize[sccOf[from]] > 1 || from == to) {
+			edges[i].InCycle = true
+			nodes[from].InCycle = true
+			nodes[to].InCycle = true
+		}
+	}
+}
+
+// Write renders the graph in the requested format
+func (g *DependencyGraph) Write(w io.Writer, format GraphFormat) error {
+	switch format {
+	case GraphFormatDOT:
+		return g.writeDOT(w)
+	case GraphFormatMermaid:
+		return g.writeMermaid(w)
+	case GraphFormatPlantUML:
+		return g.writePlantUML(w)
+	case GraphFormatGraphML:
+		return g.writeGraphML(w)
+	}
+	return fmt.Errorf("unsupported graph format %q", format)
+}
+
+// packageClusters groups node indices by package, or by stereotype when the
+// graph is clustered by stereotype, in sorted order
+func (g *DependencyGraph) packageClusters() ([]string, map[string][]int) {
+	clusters := make(map[string][]int)
+	for i, node := range g.Nodes {
+		cluster := node.Package
+		if g.ByStereotype {
+			cluster = node.Stereotype
+		}
+		clusters[cluster] = append(clusters[cluster], i)
+	}
+	var packages []string
+	for pkg := range clusters {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+	return packages, clusters
+}
+
+// stereotypePalette are the fill colors of stereotyped nodes
+var stereotypePalette = []string{"#dbeafe", "#dcfce7", "#fef3c7", "#f3e8ff", "#fce7f3", "#e0f2fe", "#ecfccb", "#ffedd5"}
+
+// stereotypeColors assigns a fill color to every stereotype of the graph in sorted order
+func (g *DependencyGraph) stereotypeColors() ([]string, map[string]string) {
+	var components []Component
+	for _, node := range g.Nodes {
+		if node.Component != nil {
+			components = append(components, *node.Component)
+		}
+	}
+	stereotypes := Stereotypes(components)
+	colors := make(map[string]string, len(stereotypes))
+	for i, stereotype := range stereotypes {
+		colors[stereotype] = stereotypePalette[i%len(stereotypePalette)]
+	}
+	return stereotypes, colors
+}
+
+// shortIDs assigns compact, format safe identifiers to nodes
+func (g *DependencyGraph) shortIDs() map[string]string {
+	ids := make(map[string]string, len(g.Nodes))
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+	}
+	return ids
+}
+
+// edgeLabel formats the field name and qualifier carried by an edge
+func edgeLabel(edge GraphEdge) string {
+	if edge.Qualifier != "" {
+		return edge.FieldName + " [" + edge.Qualifier + "]"
+	}
+	return edge.FieldName
+}
+
+// nodeLabel returns the display label including the component qualifier
+func nodeLabel(node GraphNode) string {
+	if node.Missing {
+		return "missing: " + node.Label
+	}
+	if node.Component != nil && node.Component.Qualifier != "" {
+		return node.Label + " (" + node.Component.Qualifier + ")"
+	}
+	return node.Label
+}
+
+func (g *DependencyGraph) writeDOT(w io.Writer) error {
+	ids := g.shortIDs()
+	packages, clusters := g.packageClusters()
+	_, colors := g.stereotypeColors()
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n")
+
+	for i, pkg := range packages {
+		indent := "  "
+		if pkg != "" {
+			fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+			fmt.Fprintf(&b, "    label=%q;\n", pkg)
+			b.WriteString("    style=rounded;\n")
+			indent = "    "
+		}
+		for _, idx := range clusters[pkg] {
+			node := g.Nodes[idx]
+			attrs := fmt.Sprintf("label=%q, tooltip=%q", nodeLabel(node), node.ID)
+			if node.Stereotype != "" {
+				attrs += fmt.Sprintf(", style=filled, fillcolor=%q", colors[node.Stereotype])
+			}
+			if node.Missing {
+				attrs += ", style=dashed, color=orange, fontcolor=orange"
+			} else if node.InCycle {
+				attrs += ", color=red, penwidth=2"
+			}
+			fmt.Fprintf(&b, "%s%s [%s];\n", indent, ids[node.ID], attrs)
+		}
+		if pkg != "" {
+			b.WriteString("  }\n")
+		}
+	}
+
+	for _, edge := range g.Edges {
+		attrs := fmt.Sprintf("label=%q", edgeLabel(edge))
+		if edge.InCycle {
+			attrs += ", color=red, fontcolor=red, penwidth=2"
+		} else if strings.HasPrefix(edge.To, "missing:") {
+			attrs += ", style=dashed, color=orange"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", ids[edge.From], ids[edge.To], attrs)
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mermaidEscape replaces characters that break Mermaid labels
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "[", "#91;", "]", "#93;").Replace(s)
+}
+
+func (g *DependencyGraph) writeMermaid(w io.Writer) error {
+	ids := g.shortIDs()
+	packages, clusters := g.packageClusters()
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, pkg := range packages {
+		indent := "  "
+		if pkg != "" {
+			fmt.Fprintf(&b, "  subgraph p%d[\"%s\"]\n", i, mermaidEscape(pkg))
+			indent = "    "
+		}
+		for _, idx := range clusters[pkg] {
+			node := g.Nodes[idx]
+			fmt.Fprintf(&b, "%s%s[\"%s\"]\n", indent, ids[node.ID], mermaidEscape(nodeLabel(node)))
+		}
+		if pkg != "" {
+			b.WriteString("  end\n")
+		}
+	}
+
+	var cycleLinks []string
+	for i, edge := range g.Edges {
+		arrow := "-->"
+		if strings.HasPrefix(edge.To, "missing:") {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s|\"%s\"| %s\n", ids[edge.From], arrow, mermaidEscape(edgeLabel(edge)), ids[edge.To])
+		if edge.InCycle {
+			cycleLinks = append(cycleLinks, fmt.Sprint(i))
+		}
+	}
+
+	b.WriteString("  classDef cycle stroke:#d33,stroke-width:2px\n")
+	b.WriteString("  classDef missing stroke:#f90,stroke-dasharray:4 2,color:#f90\n")
+	stereotypes, colors := g.stereotypeColors()
+	classes := make(map[string]string)
+	for i, stereotype := range stereotypes {
+		classes[stereotype] = fmt.Sprintf("s%d", i)
+		fmt.Fprintf(&b, "  classDef s%d fill:%s\n", i, colors[stereotype])
+	}
+	for _, node := range g.Nodes {
+		if node.Stereotype != "" {
+			fmt.Fprintf(&b, "  class %s %s\n", ids[node.ID], classes[node.Stereotype])
+		}
+	}
+	for _, node := range g.Nodes {
+		if node.Missing {
+			fmt.Fprintf(&b, "  class %s missing\n", ids[node.ID])
+		} else if node.InCycle {
+			fmt.Fprintf(&b, "  class %s cycle\n", ids[node.ID])
+		}
+	}
+	if len(cycleLinks) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %s stroke:#d33,stroke-width:2px\n", strings.Join(cycleLinks, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (g *DependencyGraph) writePlantUML(w io.Writer) error {
+	ids := g.shortIDs()
+	packages, clusters := g.packageClusters()
+	_, colors := g.stereotypeColors()
+
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	b.WriteString("left to right direction\n")
+	for _, pkg := range packages {
+		indent := ""
+		if pkg != "" {
+			fmt.Fprintf(&b, "package %q {\n", pkg)
+			indent = "  "
+		}
+		for _, idx := range clusters[pkg] {
+			node := g.Nodes[idx]
+			stereotype, fill := "", ""
+			if node.Stereotype != "" {
+				stereotype, fill = " <<"+node.Stereotype+">>", strings.TrimPrefix(colors[node.Stereotype], "#")+";"
+			}
+			switch {
+			case node.Missing:
+				fmt.Fprintf(&b, "%scomponent %q as %s <<missing>> #line.dashed;line:orange\n", indent, nodeLabel(node), ids[node.ID])
+			case node.InCycle:
+				fmt.Fprintf(&b, "%scomponent %q as %s%s <<cycle>> #%sline:red\n", indent, nodeLabel(node), ids[node.ID], stereotype, fill)
+			case node.Stereotype != "":
+				fmt.Fprintf(&b, "%scomponent %q as %s%s %s\n", indent, nodeLabel(node), ids[node.ID], stereotype, colors[node.Stereotype])
+			default:
+				fmt.Fprintf(&b, "%scomponent %q as %s\n", indent, nodeLabel(node), ids[node.ID])
+			}
+		}
+		if pkg != "" {
+			b.WriteString("}\n")
+		}
+	}
+
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.InCycle {
+			arrow = "-[#red,bold]->"
+		} else if strings.HasPrefix(edge.To, "missing:") {
+			arrow = "-[#orange,dashed]->"
+		}
+		fmt.Fprintf(&b, "%s %s %s : %s\n", ids[edge.From], arrow, ids[edge.To], edgeLabel(edge))
+	}
+	b.WriteString("@enduml\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (g *DependencyGraph) writeGraphML(w io.Writer) error {
+	ids := g.shortIDs()
+	packages, clusters := g.packageClusters()
+
+	escape := func(s string) string {
+		var b strings.Builder
+		xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	b.WriteString(`  <key id="label" for="node" attr.name="label" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="package" for="node" attr.name="package" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="stereotype" for="node" attr.name="stereotype" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="missing" for="node" attr.name="missing" attr.type="boolean"><default>false</default></key>` + "\n")
+	b.WriteString(`  <key id="nodeCycle" for="node" attr.name="cycle" attr.type="boolean"><default>false</default></key>` + "\n")
+	b.WriteString(`  <key id="field" for="edge" attr.name="field" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="qualifier" for="edge" attr.name="qualifier" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="edgeCycle" for="edge" attr.name="cycle" attr.type="boolean"><default>false</default></key>` + "\n")
+	b.WriteString(`  <graph id="dependencies" edgedefault="directed">` + "\n")
+
+	writeNode := func(indent string, node GraphNode) {
+		fmt.Fprintf(&b, "%s<node id=\"%s\">\n", indent, ids[node.ID])
+		fmt.Fprintf(&b, "%s  <data key=\"label\">%s</data>\n", indent, escape(nodeLabel(node)))
+		fmt.Fprintf(&b, "%s  <data key=\"package\">%s</data>\n", indent, escape(node.Package))
+		if node.Stereotype != "" {
+			fmt.Fprintf(&b, "%s  <data key=\"stereotype\">%s</data>\n", indent, escape(node.Stereotype))
+		}
+		if node.Missing {
+			fmt.Fprintf(&b, "%s  <data key=\"missing\">true</data>\n", indent)
+		}
+		if node.InCycle {
+			fmt.Fprintf(&b, "%s  <data key=\"nodeCycle\">true</data>\n", indent)
+		}
+		fmt.Fprintf(&b, "%s</node>\n", indent)
+	}
+
+	for i, pkg := range packages {
+		if pkg == "" {
+			for _, idx := range clusters[pkg] {
+				writeNode("    ", g.Nodes[idx])
+			}
+			continue
+		}
+		fmt.Fprintf(&b, "    <node id=\"p%d\">\n", i)
+		fmt.Fprintf(&b, "      <data key=\"label\">%s</data>\n", escape(pkg))
+		fmt.Fprintf(&b, "      <graph id=\"p%d:\" edgedefault=\"directed\">\n", i)
+		for _, idx := range clusters[pkg] {
+			writeNode("        ", g.Nodes[idx])
+		}
+		b.WriteString("      </graph>\n")
+		b.WriteString("    </node>\n")
+	}
+
+	for i, edge := range g.Edges {
+		fmt.Fprintf(&b, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, ids[edge.From], ids[edge.To])
+		fmt.Fprintf(&b, "      <data key=\"field\">%s</data>\n", escape(edge.FieldName))
+		if edge.Qualifier != "" {
+			fmt.Fprintf(&b, "      <data key=\"qualifier\">%s</data>\n", escape(edge.Qualifier))
+		}
+		if edge.InCycle {
+			b.WriteString("      <data key=\"edgeCycle\">true</data>\n")
+		}
+		b.WriteString("    </edge>\n")
+	}
+
+	b.WriteString("  </graph>\n")
+	b.WriteString("</graphml>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// componentKey returns the fully qualified key of a component (package.Type)
+func componentKey(comp Component) string {
+	return comp.Package + "." + comp.Type
+}
+
+// matchesComponentRef reports whether a user supplied reference names the component.
+// References may be fully qualified (github.com/x/service.UserService), package
+// qualified (service.UserService), a bare type name or the component name
+func matchesComponentRef(comp Component, ref string) bool {
+	if ref == componentKey(comp) || ref == comp.Type || ref == comp.Name {
+		return true
+	}
+	parts := strings.Split(comp.Package, "/")
+	return ref == parts[len(parts)-1]+"."+comp.Type
+}
+
+// matchesPackageRef reports whether a user supplied reference names the package,
+// either by full import path, by trailing path segments or by base name
+func matchesPackageRef(pkg, ref string) bool {
+	ref = strings.TrimSuffix(ref, "/")
+	return pkg == ref || strings.HasSuffix(pkg, "/"+ref)
+}
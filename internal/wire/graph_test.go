@@ -0,0 +1,174 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func graphTestComponents() []Component {
+	return []Component{
+		{
+			Name:       "ConsoleLogger",
+			Type:       "ConsoleLogger",
+			Package:    "example.com/app/logger",
+			Qualifier:  "console",
+			Implements: []string{"example.com/app/logger.Logger"},
+		},
+		{
+			Name:    "UserService",
+			Type:    "UserService",
+			Package: "example.com/app/service",
+			Dependencies: []Dependency{
+				{FieldName: "Logger", Type: "logger.Logger", Qualifier: "console"},
+				{FieldName: "Repo", Type: "repository.UserRepository"},
+			},
+		},
+		{
+			Name:    "OrderService",
+			Type:    "OrderService",
+			Package: "example.com/app/service",
+			Dependencies: []Dependency{
+				{FieldName: "Payments", Type: "PaymentService"},
+			},
+		},
+		{
+			Name:    "PaymentService",
+			Type:    "PaymentService",
+			Package: "example.com/app/service",
+			Dependencies: []Dependency{
+				{FieldName: "Orders", Type: "OrderService"},
+			},
+		},
+	}
+}
+
+func TestAnalyzer_BuildGraph(t *testing.T) {
+	graph, err := NewAnalyzer(graphTestComponents()).BuildGraph(GraphOptions{})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+
+	// 4 components plus one placeholder for the unsatisfied repository
+	if len(graph.Nodes) != 5 {
+		t.Errorf("Expected 5 nodes, got %d", len(graph.Nodes))
+	}
+	if len(graph.Edges) != 4 {
+		t.Errorf("Expected 4 edges, got %d", len(graph.Edges))
+	}
+
+	for _, node := range graph.Nodes {
+		switch node.Label {
+		case "OrderService", "PaymentService":
+			if !node.InCycle {
+				t.Errorf("Expected %s to be marked as part of a cycle", node.Label)
+			}
+		case "repository.UserRepository":
+			if !node.Missing {
+				t.Error("Expected unsatisfied dependency to be marked as missing")
+			}
+		default:
+			if node.InCycle {
+				t.Errorf("Did not expect %s to be part of a cycle", node.Label)
+			}
+		}
+	}
+
+	for _, edge := range graph.Edges {
+		if edge.FieldName == "Logger" {
+			if edge.To != "example.com/app/logger.ConsoleLogger" || edge.Qualifier != "console" {
+				t.Errorf("Logger edge resolved incorrectly: %+v", edge)
+			}
+		}
+	}
+}
+
+func TestAnalyzer_BuildGraphSubgraph(t *testing.T) {
+	analyzer := NewAnalyzer(graphTestComponents())
+
+	graph, err := analyzer.BuildGraph(GraphOptions{RootComponent: "service.UserService"})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+	if len(graph.Nodes) != 3 {
+		t.Errorf("Expected 3 nodes reachable from UserService, got %d", len(graph.Nodes))
+	}
+
+	graph, err = analyzer.BuildGraph(GraphOptions{RootPackage: "logger"})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+	if len(graph.Nodes) != 1 || len(graph.Edges) != 0 {
+		t.Errorf("Expected only the logger node, got %d nodes and %d edges", len(graph.Nodes), len(graph.Edges))
+	}
+
+	if _, err := analyzer.BuildGraph(GraphOptions{RootComponent: "DoesNotExist"}); err == nil {
+		t.Error("Expected error for unknown root component")
+	}
+}
+
+func TestDependencyGraph_Write(t *testing.T) {
+	graph, err := NewAnalyzer(graphTestComponents()).BuildGraph(GraphOptions{})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+
+	tests := []struct {
+		format   GraphFormat
+		expected []string
+	}{
+		{GraphFormatDOT, []string{"digraph dependencies {", "subgraph cluster_", `label="Logger [console]"`, "color=red", "style=dashed"}},
+		{GraphFormatMermaid, []string{"flowchart LR", "subgraph p", "-.->", "linkStyle", "class n4 missing"}},
+		{GraphFormatPlantUML, []string{"@startuml", `package "example.com/app/service" {`, "-[#red,bold]->", "<<missing>>", "@enduml"}},
+		{GraphFormatGraphML, []string{"<graphml", `<data key="qualifier">console</data>`, `<data key="edgeCycle">true</data>`}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := graph.Write(&buf, tt.format); err != nil {
+			t.Fatalf("Write(%s) failed: %v", tt.format, err)
+		}
+		for _, exp := range tt.expected {
+			if !strings.Contains(buf.String(), exp) {
+				t.Errorf("Expected %s output to contain %q\n%s", tt.format, exp, buf.String())
+			}
+		}
+		if tt.format == GraphFormatGraphML {
+			if err := xml.Unmarshal(buf.Bytes(), new(struct{})); err != nil {
+				t.Errorf("GraphML output is not valid XML: %v", err)
+			}
+		}
+	}
+}
+
+func TestDependencyGraph_WritePlantUMLStereotypedCycle(t *testing.T) {
+	components := graphTestComponents()
+	components[2].Stereotype = "service"
+	graph, err := NewAnalyzer(components).BuildGraph(GraphOptions{})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := graph.Write(&buf, GraphFormatPlantUML); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	// The stereotype fill and the cycle border share one color spec
+	out := buf.String()
+	if !strings.Contains(out, "<<service>> <<cycle>> #dbeafe;line:red") || !strings.Contains(out, "<<cycle>> #line:red") {
+		t.Errorf("Expected the cycle nodes to be drawn with a red border:\n%s", out)
+	}
+	if strings.Contains(out, "##") {
+		t.Errorf("Expected no doubled color prefix:\n%s", out)
+	}
+}
+
+func TestParseGraphFormat(t *testing.T) {
+	if f, err := ParseGraphFormat("Mermaid"); err != nil || f != GraphFormatMermaid {
+		t.Errorf("Expected mermaid format, got %q (%v)", f, err)
+	}
+	if _, err := ParseGraphFormat("svg"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
@@ -0,0 +1,113 @@
+package wire
+
+import (
+	"sort"
+	"strings"
+)
+
+// componentIndex resolves dependencies to components. It is built once per
+// component set and shared by the generator and the analyzer, so resolving a
+// dependency only looks at the components whose type or interface name matches
+// instead of scanning every component
+type componentIndex struct {
+	components []Component
+	byKey      map[string]Component   // package.Type -> component
+	byName     map[string][]int       // Type name or implemented interface name -> components, in order
+	resolved   map[string][]Component // Memoized results by dependency type and qualifier
+}
+
+// newComponentIndex indexes the components by key, type name and interface name
+func newComponentIndex(components []Component) *componentIndex {
+	index := &componentIndex{
+		components: components,
+		byKey:      make(map[string]Component, len(components)),
+		byName:     make(map[string][]int, len(components)),
+		resolved:   make(map[string][]Component),
+	}
+
+	for i, comp := range components {
+		index.byKey[componentKey(comp)] = comp
+		index.byName[comp.Type] = append(index.byName[comp.Type], i)
+		for _, iface := range comp.Implements {
+			_, name := splitTypeRef(iface)
+			if names := index.byName[name]; len(names) == 0 || names[len(names)-1] != i {
+				index.byName[name] = append(names, i)
+			}
+		}
+	}
+
+	return index
+}
+
+// resolve returns every component satisfying a dependency: components of the
+// requested type, or for package qualified types also the implementations of
+// the interface, always with an equal qualifier
+func (x *componentIndex) resolve(dep Dependency) []Component {
+	memoKey := dep.Type + "\x00" + dep.Qualifier
+	if matches, ok := x.resolved[memoKey]; ok {
+		return matches
+	}
+
+	var matches []Component
+	depBase, depName := splitTypeRef(dep.Type)
+	for _, i := range x.byName[depName] {
+		comp := x.components[i]
+		if comp.Qualifier != dep.Qualifier {
+			continue
+		}
+
+		// Direct type match - check full package.Type, package-qualified Type and just Type
+		compKey := comp.Package + "." + comp.Type
+		if comp.Type == dep.Type || compKey == dep.Type ||
+			(comp.Type == depName && packageBase(comp.Package) == depBase) {
+			matches = append(matches, comp)
+			continue
+		}
+
+		// Interface implementation match
+		if strings.Contains(dep.Type, ".") && implementsInterface(comp, depBase, depName) {
+			matches = append(matches, comp)
+		}
+	}
+
+	// A Primary component is preferred over the other matches
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Primary && !matches[j].Primary })
+
+	x.resolved[memoKey] = matches
+	return matches
+}
+
+// implementsInterface reports whether the component implements the interface
+// named by a package base and type name. Interfaces declared without a package
+// match any package
+func implementsInterface(comp Component, base, name string) bool {
+	for _, iface := range comp.Implements {
+		ifaceBase, ifaceName := splitTypeRef(iface)
+		if ifaceName == name && (ifaceBase == "" || ifaceBase == base) {
+			return true
+		}
+	}
+	return false
+}
+
+// singlePrimary reports whether exactly one of the components, given by key,
+// has a Primary marker, which resolves the conflict between them in its favor
+func (x *componentIndex) singlePrimary(keys []string) bool {
+	primaries := 0
+	for _, key := range keys {
+		if x.byKey[key].Primary {
+			primaries++
+		}
+	}
+	return primaries == 1
+}
+
+// injected returns the components a dependency is wired to: only the Primary
+// match when a single Primary settles the choice, otherwise every match
+func (x *componentIndex) injected(dep Dependency) []Component {
+	matches := x.resolve(dep)
+	if len(matches) > 1 && matches[0].Primary && !matches[1].Primary {
+		return matches[:1]
+	}
+	return matches
+}
@@ -0,0 +1,195 @@
+package wire
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// syntheticComponents builds a layered component model: every package has a
+// repository, an interface implementation per qualifier and services depending
+// on components of the previous package
+func syntheticComponents(n int) []Component {
+	const perPackage = 50
+	var components []Component
+	for i := 0; len(components) < n; i++ {
+		pkg := fmt.Sprintf("example.com/app/pkg%d", i)
+		prev := fmt.Sprintf("pkg%d", i-1)
+		for j := 0; j < perPackage && len(components) < n; j++ {
+			comp := Component{
+				Name:    fmt.Sprintf("Service%d", j),
+				Type:    fmt.Sprintf("Service%d", j),
+				Package: pkg,
+			}
+			switch {
+			case j < 2:
+				// Two qualified implementations of the package's Store interface
+				comp.Implements = []string{pkg + ".Store"}
+				comp.Qualifier = fmt.Sprintf("q%d", j)
+			case i > 0:
+				comp.Dependencies = []Dependency{
+					{FieldName: "Store", Type: prev + ".Store", Qualifier: "q1"},
+					{FieldName: "Peer", Type: fmt.Sprintf("%s.Service%d", prev, j)},
+				}
+			}
+			components = append(components, comp)
+		}
+	}
+	return components
+}
+
+// scanResolve is the linear resolution the index replaces, kept as a reference
+func scanResolve(components []Component, dep Dependency) []Component {
+	var matches []Component
+	depBase, depName := splitTypeRef(dep.Type)
+	for _, comp := range components {
+		if comp.Qualifier != dep.Qualifier {
+			continue
+		}
+		if comp.Type == dep.Type || componentKey(comp) == dep.Type ||
+			(comp.Type == depName && packageBase(comp.Package) == depBase) {
+			matches = append(matches, comp)
+			continue
+		}
+		if strings.Contains(dep.Type, ".") {
+			for _, iface := range comp.Implements {
+				ifaceBase, ifaceName := splitTypeRef(iface)
+				if ifaceName == depName && (ifaceBase == "" || ifaceBase == depBase) {
+					matches = append(matches, comp)
+					break
+				}
+			}
+		}
+	}
+	return matches
+}
+
+func TestComponentIndex_MatchesLinearResolution(t *testing.T) {
+	components := syntheticComponents(500)
+	components = append(components, Component{
+		Name:       "Logger",
+		Type:       "Logger",
+		Package:    "example.com/app/logger",
+		Implements: []string{"example.com/app/logger.Logger"},
+	})
+	index := newComponentIndex(components)
+
+	deps := []Dependency{
+		{Type: "logger.Logger"},
+		{Type: "Logger"},
+		{Type: "example.com/app/logger.Logger"},
+		{Type: "pkg3.Store", Qualifier: "q0"},
+		{Type: "pkg3.Store"},
+		{Type: "Service7"},
+		{Type: "pkg4.Service7"},
+		{Type: "other.Service7"},
+		{Type: "Missing"},
+	}
+	for _, comp := range components {
+		deps = append(deps, comp.Dependencies...)
+	}
+
+	for _, dep := range deps {
+		expected := scanResolve(components, dep)
+		// Resolve twice to cover the memoized path
+		for i := 0; i < 2; i++ {
+			if got := index.resolve(dep); !reflect.DeepEqual(got, expected) {
+				t.Fatalf("resolve(%s[%s]) = %d components, expected %d", dep.Type, dep.Qualifier, len(got), len(expected))
+			}
+		}
+	}
+}
+
+func BenchmarkResolve_Index10k(b *testing.B) {
+	components := syntheticComponents(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index := newComponentIndex(components)
+		for _, comp := range components {
+			for _, dep := range comp.Dependencies {
+				index.resolve(dep)
+			}
+		}
+	}
+}
+
+func BenchmarkResolve_LinearScan10k(b *testing.B) {
+	components := syntheticComponents(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, comp := range components {
+			for _, dep := range comp.Dependencies {
+				scanResolve(components, dep)
+			}
+		}
+	}
+}
+
+func BenchmarkGenerateCode10k(b *testing.B) {
+	components := syntheticComponents(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewGenerator(components).GenerateCode(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAnalyzeCycles10k(b *testing.B) {
+	components := syntheticComponents(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		analyzer := NewAnalyzer(components)
+		analyzer.FindCircularDependencies()
+		analyzer.FindUnusedComponents()
+		analyzer.CalculateDependencyDepth()
+	}
+}
+
+// writeSyntheticTree writes 10k components as source files, 50 per package
+func writeSyntheticTree(b *testing.B) string {
+	b.Helper()
+	root := b.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.23\n"), 0644); err != nil {
+		b.Fatal(err)
+	}
+	for pkg := 0; pkg < 200; pkg++ {
+		dir := filepath.Join(root, fmt.Sprintf("pkg%d", pkg))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatal(err)
+		}
+		for file := 0; file < 10; file++ {
+			var src strings.Builder
+			fmt.Fprintf(&src, "package pkg%d\n\n", pkg)
+			for j := 0; j < 5; j++ {
+				fmt.Fprintf(&src, "type Service%d_%d struct {\n\tComponent struct{}\n\tPeer *Service0_0 `autowired:\"true\"`\n}\n\n", file, j)
+			}
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%d.go", file)), []byte(src.String()), 0644); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	return root
+}
+
+func benchmarkParse(b *testing.B, opts ParseOptions) {
+	root := writeSyntheticTree(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		components, _, err := ParseComponentsWithOptions(root, opts)
+		if err != nil || len(components) != 10000 {
+			b.Fatalf("Expected 10000 components, got %d (%v)", len(components), err)
+		}
+	}
+}
+
+func BenchmarkParseComponents10k_Serial(b *testing.B) {
+	benchmarkParse(b, ParseOptions{Workers: 1})
+}
+
+func BenchmarkParseComponents10k_Parallel(b *testing.B) {
+	benchmarkParse(b, ParseOptions{})
+}
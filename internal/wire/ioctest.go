@@ -0,0 +1,358 @@
+package wire
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ioctestPackage is the import path of the runtime the test constructors register with
+const ioctestPackage = "github.com/tuhuynh27/go-ioc/ioctest"
+
+// testConstructor is the ioctest.Constructor of one component
+type testConstructor struct {
+	Type          string    // Component type as package.Type
+	Constructor   string    // Constructor function as package.NewType, empty for struct literals
+	Dependencies  []testDep // Autowired fields in declaration order
+	PostConstruct bool
+	PreDestroy    bool
+}
+
+// testDep is an autowired field resolved through ioctest.Resolve
+type testDep struct {
+	Field string // Name of the struct field
+	Expr  string // Expression resolving the fake or the real component
+}
+
+// ForTestPackage makes the generator write the ioctest constructors into
+// outputDir, in a package named after outputDir
+func (g *Generator) ForTestPackage(baseDir, outputDir string) *Generator {
+	g.outputDir = outputDir
+	g.packageName = packageNameFor(outputDir)
+	g.generateArgs = "ioctest --dir=" + relativeDir(outputDir, baseDir)
+	if outputDir != filepath.Join(baseDir, "wire", "wiretest") {
+		g.generateArgs += " --out=" + relativeDir(baseDir, outputDir)
+	}
+	return g
+}
+
+// GenerateTestPackage writes ioctest_gen.go into the output directory set by
+// ForTestPackage and returns the path of the file
+func (g *Generator) GenerateTestPackage() (string, error) {
+	code, err := g.GenerateTestCode()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(g.outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create test package directory: %w", err)
+	}
+	path := filepath.Join(g.outputDir, "ioctest_gen.go")
+	if err := os.WriteFile(path, code, 0644); err != nil {
+		return "", fmt.Errorf("failed to write generated constructors: %w", err)
+	}
+	return path, nil
+}
+
+// GenerateTestCode renders ioctest_gen.go in memory. It registers a
+// reflection-free constructor per component with the ioctest package, which
+// resolves every autowired field to a fake of the test or to the real component
+// the container would inject
+func (g *Generator) GenerateTestCode() ([]byte, error) {
+	inits, err := g.testInits()
+	if err != nil {
+		return nil, err
+	}
+	return renderTestCode(g.packageName, g.generateArgs, "", inits, nil)
+}
+
+// ForInPackageTests makes the generator write the ioctest constructors into the
+// packages of the components below baseDir, for tests inside those packages
+// that cannot import a package importing every component. stereotypeConfig is
+// the absolute path of the stereotype config, empty for the default
+func (g *Generator) ForInPackageTests(baseDir, stereotypeConfig string) *Generator {
+	g.outputDir = baseDir
+	g.generateArgs = "--in-package"
+	g.stereotypeConfig = stereotypeConfig
+	return g
+}
+
+// GenerateInPackageTests writes an ioctest_gen_test.go into every component
+// package below the directory set by ForInPackageTests and returns the paths of
+// the files. Each registers the components of its package and the components
+// they depend on, except those whose package imports the package under test
+func (g *Generator) GenerateInPackageTests() ([]string, error) {
+	inits, err := g.testInits()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make(map[string]string)
+	for _, comp := range g.components {
+		if comp.SourceFile == "" {
+			continue
+		}
+		dir, err := filepath.Abs(filepath.Dir(comp.SourceFile))
+		if err != nil {
+			return nil, err
+		}
+		// Dependency modules are read only, vendored ones included
+		rel := relativeDir(g.outputDir, dir)
+		if isWithin(g.outputDir, dir) && rel != "vendor" && !strings.HasPrefix(rel, "vendor/") {
+			dirs[comp.Package] = dir
+		}
+	}
+	var packages []string
+	for pkg := range dirs {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+
+	imports := packageImports(inits)
+	var paths []string
+	for _, pkg := range packages {
+		dir := dirs[pkg]
+		generateArgs := "ioctest --dir=" + relativeDir(dir, g.outputDir) + " " + g.generateArgs
+		if g.stereotypeConfig != "" {
+			generateArgs += " " + generateFlag("stereotypes", relativeDir(dir, g.stereotypeConfig))
+		}
+		code, err := renderTestCode(packageBase(pkg), generateArgs, pkg, inits, func(other string) bool {
+			return other == pkg || !imports.reaches(other, pkg)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pkg, err)
+		}
+		path := filepath.Join(dir, "ioctest_gen_test.go")
+		if err := os.WriteFile(path, code, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write generated constructors: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// testInits resolves the components the ioctest constructors are generated for
+func (g *Generator) testInits() ([]componentInit, error) {
+	if len(g.components) == 0 {
+		return nil, fmt.Errorf("no components found")
+	}
+	g.visited = make(map[string]bool)
+	g.cyclicMap = make(map[string]bool)
+	if err := g.checkModuleAccess(); err != nil {
+		return nil, err
+	}
+	ordered, err := g.topologicalSort()
+	if err != nil {
+		return nil, err
+	}
+	return g.generateComponentInits(ordered)
+}
+
+// importGraph maps a package to the packages its components' autowired fields
+// make it import
+type importGraph map[string]map[string]bool
+
+// packageImports derives the imports between component packages from the
+// types of the autowired fields
+func packageImports(inits []componentInit) importGraph {
+	byVar := make(map[string]componentInit, len(inits))
+	for _, init := range inits {
+		byVar[init.VarName] = init
+	}
+	graph := make(importGraph)
+	for _, init := range inits {
+		for _, dep := range init.Dependencies {
+			imported := dep.InterfacePackage
+			if imported == "" {
+				imported = byVar[dep.VarName].Package
+			}
+			if imported == init.Package {
+				continue
+			}
+			if graph[init.Package] == nil {
+				graph[init.Package] = make(map[string]bool)
+			}
+			graph[init.Package][imported] = true
+		}
+	}
+	return graph
+}
+
+// reaches reports whether from imports to, directly or through other packages
+func (g importGraph) reaches(from, to string) bool {
+	seen := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		for imported := range g[pkg] {
+			if imported == to {
+				return true
+			}
+			if !seen[imported] {
+				seen[imported] = true
+				queue = append(queue, imported)
+			}
+		}
+	}
+	return false
+}
+
+// renderTestCode renders the ioctest constructors of a package. For a file
+// inside the component package local, only the components of local and their
+// dependencies in packages accepted by usable are registered, and the types of
+// local are not qualified. Dependencies in other packages fail the test when
+// they are not faked. An empty local registers every component
+func renderTestCode(packageName, generateArgs, local string, inits []componentInit, usable func(pkg string) bool) ([]byte, error) {
+	byVar := make(map[string]componentInit, len(inits))
+	for _, init := range inits {
+		byVar[init.VarName] = init
+	}
+
+	selected := make(map[string]bool, len(inits))
+	if local == "" {
+		for _, init := range inits {
+			selected[init.VarName] = true
+		}
+	} else {
+		var queue []string
+		for _, init := range inits {
+			if init.Package == local {
+				selected[init.VarName] = true
+				queue = append(queue, init.VarName)
+			}
+		}
+		for len(queue) > 0 {
+			init := byVar[queue[0]]
+			queue = queue[1:]
+			for _, dep := range init.Dependencies {
+				target := byVar[dep.VarName]
+				if !selected[target.VarName] && usable(target.Package) {
+					selected[target.VarName] = true
+					queue = append(queue, target.VarName)
+				}
+			}
+		}
+	}
+
+	imports := map[string]bool{ioctestPackage: true}
+	qualify := func(pkg, name string) string {
+		if pkg == local {
+			return name
+		}
+		imports[pkg] = true
+		return packageBase(pkg) + "." + name
+	}
+
+	var constructors []testConstructor
+	for _, init := range inits {
+		if !selected[init.VarName] {
+			continue
+		}
+		constructor := testConstructor{
+			Type:          qualify(init.Package, init.Type),
+			PostConstruct: init.PostConstruct,
+			PreDestroy:    init.PreDestroy,
+		}
+		if init.Constructor != "" {
+			constructor.Constructor = qualify(init.Package, init.Constructor)
+		}
+
+		for _, dep := range init.Dependencies {
+			target := byVar[dep.VarName]
+
+			// Fakes are matched by the type of the field
+			var fieldType string
+			if dep.InterfacePackage != "" {
+				fieldType = qualify(dep.InterfacePackage, dep.InterfaceName)
+			}
+			var real string
+			if selected[target.VarName] {
+				targetType := qualify(target.Package, target.Type)
+				if fieldType == "" {
+					fieldType = "*" + targetType
+				}
+				real = fmt.Sprintf("ioctest.Real[%s](r)", targetType)
+			} else {
+				real = fmt.Sprintf("ioctest.Unavailable[%s](r, %q)", fieldType, target.Package+"."+target.Type)
+			}
+			constructor.Dependencies = append(constructor.Dependencies, testDep{
+				Field: dep.FieldName,
+				Expr:  fmt.Sprintf("ioctest.Resolve(r, %q, func() %s { return %s })", dep.Qualifier, fieldType, real),
+			})
+		}
+		constructors = append(constructors, constructor)
+	}
+
+	var importList []string
+	for imp := range imports {
+		importList = append(importList, imp)
+	}
+	sort.Strings(importList)
+
+	data := struct {
+		Package      string
+		GenerateArgs string
+		InPackage    bool
+		Imports      []string
+		Constructors []testConstructor
+	}{packageName, generateArgs, local != "", importList, constructors}
+
+	var buf bytes.Buffer
+	if err := testPackageTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("template execution failed: %w", err)
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated constructors failed: %w", err)
+	}
+	return code, nil
+}
+
+var testPackageTemplate = template.Must(template.New("ioctest").Parse(`// File: ioctest_gen{{if .InPackage}}_test{{end}}.go
+// Code generated by Go IoC. DO NOT EDIT.
+//go:generate go run github.com/tuhuynh27/go-ioc/cmd/iocgen {{.GenerateArgs}}
+{{if not .InPackage}}
+// Package {{.Package}} registers the constructors ioctest.Build wires components
+// with. Import it for its side effects in tests only
+{{- end}}
+package {{.Package}}
+
+import ({{range .Imports}}
+    "{{.}}"{{end}}
+)
+
+{{if .InPackage -}}
+// init registers the constructors ioctest.Build wires the components of this
+// package with in its tests
+{{end -}}
+func init() {
+    {{- range $c := .Constructors}}
+    ioctest.Register(ioctest.Constructor[{{$c.Type}}]{
+        {{- if $c.Constructor}}
+        New: func(r *ioctest.Resolver) *{{$c.Type}} {
+            return {{$c.Constructor}}({{range $i, $dep := $c.Dependencies}}{{if $i}}, {{end}}
+                {{$dep.Expr}}{{end}}{{if $c.Dependencies}},
+            {{end}})
+        },
+        {{- else if $c.Dependencies}}
+        Inject: func(c *{{$c.Type}}, r *ioctest.Resolver) {
+            {{- range $c.Dependencies}}
+            c.{{.Field}} = {{.Expr}}
+            {{- end}}
+        },
+        {{- end}}
+        {{- if $c.PostConstruct}}
+        PostConstruct: (*{{$c.Type}}).PostConstruct,
+        {{- end}}
+        {{- if $c.PreDestroy}}
+        PreDestroy: (*{{$c.Type}}).PreDestroy,
+        {{- end}}
+    })
+    {{- end}}
+}
+`))
@@ -0,0 +1,146 @@
+package wire
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGenerator_GenerateTestCode(t *testing.T) {
+	components, err := ParseComponents(filepath.Join("testdata", "golden", "lifecycle"))
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+
+	code, err := NewGenerator(components).ForTestPackage("/src/app", "/src/app/wire/wiretest").GenerateTestCode()
+	if err != nil {
+		t.Fatalf("GenerateTestCode failed: %v", err)
+	}
+	for _, want := range []string{
+		"iocgen ioctest --dir=../..\n",
+		"\npackage wiretest\n",
+		"\t\"github.com/tuhuynh27/go-ioc/ioctest\"\n",
+		// Constructor functions receive their dependencies as arguments
+		"New: func(r *ioctest.Resolver) *cache.Cache {\n\t\t\treturn cache.NewCache(\n\t\t\t\tioctest.Resolve(r, \"\", func() *db.Pool { return ioctest.Real[db.Pool](r) }),\n\t\t\t)\n\t\t},",
+		"PreDestroy: (*cache.Cache).PreDestroy,",
+		"c.Pool = ioctest.Resolve(r, \"\", func() *db.Pool { return ioctest.Real[db.Pool](r) })",
+		"PostConstruct: (*server.Server).PostConstruct,",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Expected generated constructors to contain %q:\n%s", want, code)
+		}
+	}
+	if strings.Contains(string(code), "reflect") {
+		t.Errorf("Expected the constructors to do without reflection:\n%s", code)
+	}
+}
+
+func TestGenerator_GenerateTestCodeInterfaces(t *testing.T) {
+	components, err := ParseComponents(filepath.Join("testdata", "golden", "messaging"))
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	path, err := NewGenerator(components).ForTestPackage(tmpDir, filepath.Join(tmpDir, "internal", "testkit")).GenerateTestPackage()
+	if err != nil {
+		t.Fatalf("GenerateTestPackage failed: %v", err)
+	}
+	code, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the constructors to be written: %v", err)
+	}
+	// Fakes are matched by the interface the field injects, with its qualifier
+	for _, want := range []string{
+		"iocgen ioctest --dir=../.. --out=internal/testkit\n",
+		"c.Email = ioctest.Resolve(r, \"email\", func() message.Sender { return ioctest.Real[message.EmailSender](r) })",
+		"c.Logger = ioctest.Resolve(r, \"console\", func() logger.Logger { return ioctest.Real[logger.ConsoleLogger](r) })",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Expected generated constructors to contain %q:\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerator_GenerateInPackageTests(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := func(dir string) string {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return filepath.Join(tmpDir, dir, "component.go")
+	}
+	// The audit sender imports the notification package for its Notifier interface
+	components := []Component{
+		{
+			Name: "NotificationService", Type: "NotificationService", Package: "example.com/app/notification",
+			SourceFile:   source("notification"),
+			Dependencies: []Dependency{{FieldName: "Sender", Type: "message.Sender"}},
+		},
+		{
+			Name: "AuditSender", Type: "AuditSender", Package: "example.com/app/audit",
+			SourceFile:   source("audit"),
+			Implements:   []string{"example.com/app/message.Sender"},
+			Dependencies: []Dependency{{FieldName: "Notifier", Type: "notification.Notifier"}},
+		},
+		{
+			Name: "PushNotifier", Type: "PushNotifier", Package: "example.com/app/push",
+			SourceFile: source("push"),
+			Implements: []string{"example.com/app/notification.Notifier"},
+		},
+		{
+			Name: "Client", Type: "Client", Package: "example.com/lib/client",
+			SourceFile: source(filepath.Join("vendor", "example.com", "lib", "client")),
+		},
+	}
+
+	paths, err := NewGenerator(components).ForInPackageTests(tmpDir, filepath.Join(tmpDir, "config", "stereotypes.json")).GenerateInPackageTests()
+	if err != nil {
+		t.Fatalf("GenerateInPackageTests failed: %v", err)
+	}
+	expected := []string{
+		filepath.Join(tmpDir, "audit", "ioctest_gen_test.go"),
+		filepath.Join(tmpDir, "notification", "ioctest_gen_test.go"),
+		filepath.Join(tmpDir, "push", "ioctest_gen_test.go"),
+	}
+	if !slices.Equal(paths, expected) {
+		t.Fatalf("Expected constructors in the local packages %v, got %v", expected, paths)
+	}
+
+	code, err := os.ReadFile(expected[1])
+	if err != nil {
+		t.Fatalf("Expected the constructors to be written: %v", err)
+	}
+	// The package under test is not imported, and neither is the audit package importing it
+	for _, want := range []string{
+		"iocgen ioctest --dir=.. --in-package --stereotypes=../config/stereotypes.json\n",
+		"\npackage notification\n",
+		"ioctest.Register(ioctest.Constructor[NotificationService]{",
+		"return ioctest.Unavailable[message.Sender](r, \"example.com/app/audit.AuditSender\")",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Expected in-package constructors to contain %q:\n%s", want, code)
+		}
+	}
+	for _, unwanted := range []string{"example.com/app/notification\"", "example.com/app/audit\"", "PushNotifier", "// Package"} {
+		if strings.Contains(string(code), unwanted) {
+			t.Errorf("Did not expect in-package constructors to contain %q:\n%s", unwanted, code)
+		}
+	}
+
+	// Packages not importing the audit package build it with its real dependencies
+	code, err = os.ReadFile(expected[0])
+	if err != nil {
+		t.Fatalf("Expected the constructors to be written: %v", err)
+	}
+	for _, want := range []string{
+		"\npackage audit\n",
+		"c.Notifier = ioctest.Resolve(r, \"\", func() notification.Notifier { return ioctest.Real[push.PushNotifier](r) })",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Expected in-package constructors to contain %q:\n%s", want, code)
+		}
+	}
+}
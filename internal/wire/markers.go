@@ -0,0 +1,41 @@
+package wire
+
+import (
+	"go/ast"
+	"strconv"
+)
+
+// markersPackage is the import path of the typed markers like ioc.Component
+const markersPackage = "github.com/tuhuynh27/go-ioc/ioc"
+
+// markersImport returns the name a file imports the typed markers under, empty
+// when it does not import them
+func markersImport(file *ast.File) string {
+	for _, imp := range file.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err != nil || path != markersPackage {
+			continue
+		}
+		if imp.Name == nil {
+			return "ioc"
+		}
+		if imp.Name.Name != "_" && imp.Name.Name != "." {
+			return imp.Name.Name
+		}
+	}
+	return ""
+}
+
+// markerName returns the marker a struct field declares, empty for other
+// fields. A typed marker like ioc.Component is recognized by its type, embedded
+// or as a field of any name, an untyped marker by its name and struct{} type
+func markerName(field *ast.Field, markers string) string {
+	if sel, ok := field.Type.(*ast.SelectorExpr); ok && markers != "" {
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == markers {
+			return sel.Sel.Name
+		}
+	}
+	if _, ok := field.Type.(*ast.StructType); ok && len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+	return ""
+}
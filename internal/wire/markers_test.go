@@ -0,0 +1,197 @@
+package wire
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseComponents_TypedMarkers(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.23\n",
+		"message/message.go": `package message
+
+import "github.com/tuhuynh27/go-ioc/ioc"
+
+type Sender interface {
+	Send(msg string)
+}
+
+type EmailSender struct {
+	ioc.Component ` + "`implements:\"Sender\"`" + `
+	ioc.Qualifier ` + "`value:\"email\"`" + `
+	ioc.Primary
+}
+
+type SmsSender struct {
+	Marker    ioc.Component ` + "`name:\"sms\" implements:\"Sender\"`" + `
+	Qualifier ioc.Qualifier ` + "`value:\"sms\"`" + `
+	Profile   ioc.Profile   ` + "`value:\"prod\"`" + `
+}
+`,
+		"server/server.go": `package server
+
+import (
+	di "github.com/tuhuynh27/go-ioc/ioc"
+	"example.com/app/message"
+)
+
+type Server struct {
+	di.Component
+	di.EntryPoint ` + "`value:\"api\"`" + `
+	Sender message.Sender ` + "`autowired:\"true\" qualifier:\"email\"`" + `
+}
+
+// Untyped markers keep working
+type Worker struct {
+	Component struct{}
+	Primary   struct{}
+}
+
+// Fields of other types named like markers are no markers
+type Options struct {
+	Component string
+	Primary   bool
+}
+`,
+		"module/module.go": `package module
+
+import "github.com/tuhuynh27/go-ioc/ioc"
+
+type Billing struct {
+	ioc.Module  ` + "`value:\"billing\"`" + `
+	ioc.Exports ` + "`value:\"*\"`" + `
+}
+
+type Invoices struct {
+	ioc.Component
+}
+`,
+	})
+
+	components, _, err := ParseComponentsWithOptions(tmpDir, ParseOptions{Profiles: []string{"prod"}})
+	if err != nil {
+		t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+	}
+	byType := make(map[string]Component)
+	for _, comp := range components {
+		byType[comp.Type] = comp
+	}
+	if len(components) != 5 {
+		t.Fatalf("Expected 5 components, got %+v", components)
+	}
+
+	email := byType["EmailSender"]
+	if email.Qualifier != "email" || !email.Primary || len(email.Implements) != 1 {
+		t.Errorf("Expected the embedded markers to configure the email sender, got %+v", email)
+	}
+	sms := byType["SmsSender"]
+	if sms.Name != "sms" || sms.Qualifier != "sms" || sms.Primary || strings.Join(sms.Profiles, ",") != "prod" {
+		t.Errorf("Expected the named marker fields to configure the sms sender, got %+v", sms)
+	}
+	server := byType["Server"]
+	if !server.EntryPoint || strings.Join(server.Containers, ",") != "api" || len(server.Dependencies) != 1 {
+		t.Errorf("Expected the aliased markers to configure the server, got %+v", server)
+	}
+	if !byType["Worker"].Primary {
+		t.Errorf("Expected the untyped Primary marker to be recognized")
+	}
+	if invoices := byType["Invoices"]; invoices.Module != "billing" || !invoices.Exported {
+		t.Errorf("Expected the typed module markers to declare the billing module, got %+v", invoices)
+	}
+}
+
+func TestPrimaryMarker(t *testing.T) {
+	components := []Component{
+		{Type: "SmsSender", Package: "example.com/app/message", Implements: []string{"example.com/app/message/Sender"}},
+		{Type: "EmailSender", Package: "example.com/app/message", Implements: []string{"example.com/app/message/Sender"}, Primary: true},
+		{Type: "Service", Package: "example.com/app/service", Dependencies: []Dependency{
+			{FieldName: "Sender", Type: "message.Sender"},
+		}},
+	}
+
+	matches := newComponentIndex(components).resolve(components[2].Dependencies[0])
+	if len(matches) != 2 || matches[0].Type != "EmailSender" {
+		t.Fatalf("Expected the primary sender to be resolved first, got %+v", matches)
+	}
+
+	code, err := NewGenerator(components).GenerateCode()
+	if err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	if !strings.Contains(string(code), `(o.Sender, "", container.EmailSender)`) {
+		t.Errorf("Expected the primary sender to be injected:\n%s", code)
+	}
+
+	if conflicts := NewAnalyzer(components).FindQualifierConflicts(); len(conflicts) != 0 {
+		t.Errorf("Expected a single primary to resolve the conflict, got %+v", conflicts)
+	}
+	components[0].Primary = true
+	if conflicts := NewAnalyzer(components).FindQualifierConflicts(); len(conflicts) != 1 {
+		t.Errorf("Expected two primaries to conflict, got %+v", conflicts)
+	}
+}
+
+func TestPrimaryMarkerIgnoresAlternativesForCycles(t *testing.T) {
+	components := []Component{
+		{Type: "App", Package: "example.com/app/app", EntryPoint: true, Dependencies: []Dependency{
+			{FieldName: "Store", Type: "s.Store"},
+		}},
+		{Type: "DBStore", Package: "example.com/app/s", Implements: []string{"example.com/app/s/Store"}, Primary: true},
+		{Type: "AuditStore", Package: "example.com/app/s", Implements: []string{"example.com/app/s/Store"}, Dependencies: []Dependency{
+			{FieldName: "App", Type: "App"},
+		}},
+	}
+
+	code, err := NewGenerator(components).GenerateCode()
+	if err != nil {
+		t.Fatalf("Expected only the primary store to be wired, got: %v", err)
+	}
+	if !strings.Contains(string(code), "container.DBStore") {
+		t.Errorf("Expected the primary store to be injected:\n%s", code)
+	}
+
+	analyzer := NewAnalyzer(components)
+	if cycles := analyzer.FindCircularDependencies(); len(cycles) != 0 {
+		t.Errorf("Expected no cycle through the alternative store, got %+v", cycles)
+	}
+	unused := analyzer.FindUnusedComponents()
+	if len(unused) != 1 || unused[0].Type != "AuditStore" {
+		t.Errorf("Expected only the alternative store to be unused, got %+v", unused)
+	}
+}
+
+func TestPrimaryMarkerAnalysisAgreesWithInjection(t *testing.T) {
+	components := []Component{
+		{Type: "Main", Package: "example.com/app/root", Implements: []string{"example.com/app/api/Sender"}, Primary: true},
+		{Type: "Alt", Package: "example.com/app/billing", Implements: []string{"example.com/app/api/Sender"}},
+		{Type: "User", Package: "example.com/app/root", Dependencies: []Dependency{
+			{FieldName: "S", Type: "api.Sender"},
+		}},
+	}
+	analyzer := NewAnalyzer(components)
+
+	graph, err := analyzer.BuildGraph(GraphOptions{})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+	if len(graph.Edges) != 1 || graph.Edges[0].To != "example.com/app/root.Main" {
+		t.Errorf("Expected a single edge to the primary sender, got %+v", graph.Edges)
+	}
+
+	metrics := analyzer.CalculateComponentMetrics()
+	if user := metrics["example.com/app/root.User"]; user.FanOut != 1 || user.TransitiveDependencies != 1 {
+		t.Errorf("Expected the user to depend on the primary sender only, got %+v", user)
+	}
+	if alt := metrics["example.com/app/billing.Alt"]; alt.FanIn != 0 {
+		t.Errorf("Expected no dependents of the alternative sender, got %+v", alt)
+	}
+	if packages := analyzer.CalculatePackageMetrics(); packages["example.com/app/billing"].AfferentCoupling != 0 {
+		t.Errorf("Expected no coupling to the package of the alternative sender, got %+v", packages["example.com/app/billing"])
+	}
+
+	unused := analyzer.FindUnusedComponents()
+	if len(unused) != 2 || unused[0].Type != "Alt" || unused[1].Type != "User" {
+		t.Errorf("Expected the alternative sender and the user to be unused, got %+v", unused)
+	}
+}
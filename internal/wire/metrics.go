@@ -0,0 +1,231 @@
+package wire
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ComponentMetrics contains complexity metrics for a single component
+type ComponentMetrics struct {
+	FanIn                  int // Number of components that depend on this component
+	FanOut                 int // Number of distinct dependencies (resolved or not) of this component
+	TransitiveDependencies int // Number of components reachable through dependencies
+	Depth                  int // Dependency depth (-1 when part of a cycle)
+}
+
+// PackageMetrics contains coupling metrics for a package
+type PackageMetrics struct {
+	Components            int     // Number of components in the package
+	AfferentCoupling      int     // Ca: number of other packages depending on this package
+	EfferentCoupling      int     // Ce: number of other packages this package depends on
+	Instability           float64 // I = Ce / (Ca + Ce)
+	Abstractness          float64 // A = interface-typed incoming dependencies / all incoming dependencies
+	DistanceFromMainSeq   float64 // D = |A + I - 1|
+	InterfaceDependencies int     // Incoming dependencies declared against an interface
+	IncomingDependencies  int     // All incoming dependencies from other packages
+}
+
+// MetricThresholds configures the limits enforced by CheckThresholds. Zero disables a check
+type MetricThresholds struct {
+	MaxFanIn                  int
+	MaxFanOut                 int
+	MaxDepth                  int
+	MaxTransitiveDependencies int
+}
+
+// ThresholdViolation is a component metric exceeding a configured threshold
+type ThresholdViolation struct {
+	Component Component
+	Metric    string
+	Value     int // -1 for the unbounded depth of a component in or depending on a cycle
+	Threshold int
+	Via       string // For an unbounded depth outside a cycle, the dependency leading into one
+}
+
+// CalculateComponentMetrics computes fan-in, fan-out, transitive dependency count
+// and depth for every component
+func (a *DependencyAnalyzer) CalculateComponentMetrics() map[string]ComponentMetrics {
+	forward, _ := a.resolvedEdges()
+	depths := a.CalculateDependencyDepth()
+	metrics := make(map[string]ComponentMetrics, len(a.components))
+
+	dependents := make(map[string]map[string]bool)
+	for _, edges := range forward {
+		for _, edge := range edges {
+			if dependents[edge.To] == nil {
+				dependents[edge.To] = make(map[string]bool)
+			}
+			dependents[edge.To][edge.From] = true
+		}
+	}
+
+	for _, comp := range a.components {
+		key := componentKey(comp)
+
+		targets := make(map[string]bool)
+		for _, dep := range comp.Dependencies {
+			resolved := a.injectedComponents(dep)
+			if len(resolved) == 0 {
+				targets["missing:"+dep.Type+"["+dep.Qualifier+"]"] = true
+			}
+			for _, target := range resolved {
+				targets[componentKey(target)] = true
+			}
+		}
+
+		// Count the transitive closure of resolved dependencies
+		reachable := make(map[string]bool)
+		queue := []string{key}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, edge := range forward[current] {
+				if edge.To != key && !reachable[edge.To] {
+					reachable[edge.To] = true
+					queue = append(queue, edge.To)
+				}
+			}
+		}
+
+		metrics[key] = ComponentMetrics{
+			FanIn:                  len(dependents[key]),
+			FanOut:                 len(targets),
+			TransitiveDependencies: len(reachable),
+			Depth:                  depths[key],
+		}
+	}
+
+	return metrics
+}
+
+// CalculatePackageMetrics computes afferent and efferent coupling, instability and
+// abstractness per package. Abstractness is derived from how other packages consume
+// the package: the share of incoming dependencies declared against an interface
+func (a *DependencyAnalyzer) CalculatePackageMetrics() map[string]PackageMetrics {
+	afferent := make(map[string]map[string]bool)
+	efferent := make(map[string]map[string]bool)
+	metrics := make(map[string]PackageMetrics)
+
+	for _, comp := range a.components {
+		m := metrics[comp.Package]
+		m.Components++
+		metrics[comp.Package] = m
+	}
+
+	for _, comp := range a.components {
+		for _, dep := range comp.Dependencies {
+			for _, target := range a.injectedComponents(dep) {
+				if target.Package == comp.Package {
+					continue
+				}
+				if efferent[comp.Package] == nil {
+					efferent[comp.Package] = make(map[string]bool)
+				}
+				if afferent[target.Package] == nil {
+					afferent[target.Package] = make(map[string]bool)
+				}
+				efferent[comp.Package][target.Package] = true
+				afferent[target.Package][comp.Package] = true
+
+				m := metrics[target.Package]
+				m.IncomingDependencies++
+				if !isDirectTypeDependency(dep, target) {
+					m.InterfaceDependencies++
+				}
+				metrics[target.Package] = m
+			}
+		}
+	}
+
+	for pkg, m := range metrics {
+		m.AfferentCoupling = len(afferent[pkg])
+		m.EfferentCoupling = len(efferent[pkg])
+		if total := m.AfferentCoupling + m.EfferentCoupling; total > 0 {
+			m.Instability = float64(m.EfferentCoupling) / float64(total)
+		}
+		if m.IncomingDependencies > 0 {
+			m.Abstractness = float64(m.InterfaceDependencies) / float64(m.IncomingDependencies)
+		}
+		m.DistanceFromMainSeq = math.Abs(m.Abstractness + m.Instability - 1)
+		metrics[pkg] = m
+	}
+
+	return metrics
+}
+
+// isDirectTypeDependency reports whether dep names the target's concrete type
+func isDirectTypeDependency(dep Dependency, target Component) bool {
+	_, depName := splitTypeRef(dep.Type)
+	return target.Type == depName || componentKey(target) == dep.Type
+}
+
+// CheckThresholds returns every component metric exceeding the configured
+// thresholds. Components in or depending on a dependency cycle exceed any depth threshold
+func (a *DependencyAnalyzer) CheckThresholds(metrics map[string]ComponentMetrics, thresholds MetricThresholds) []ThresholdViolation {
+	var violations []ThresholdViolation
+
+	// Components outside a cycle with an unbounded depth reach one through a dependency
+	inCycle := make(map[string]bool)
+	via := make(map[string]string)
+	if thresholds.MaxDepth > 0 {
+		graph, _ := a.BuildGraph(GraphOptions{})
+		for _, node := range graph.Nodes {
+			inCycle[node.ID] = node.InCycle
+		}
+		for _, edge := range graph.Edges {
+			if _, found := via[edge.From]; !found && !inCycle[edge.From] && metrics[edge.To].Depth < 0 && !strings.HasPrefix(edge.To, "missing:") {
+				via[edge.From] = edge.To
+			}
+		}
+	}
+
+	check := func(comp Component, metric string, value, threshold int) {
+		if threshold > 0 && value > threshold {
+			violations = append(violations, ThresholdViolation{Component: comp, Metric: metric, Value: value, Threshold: threshold})
+		}
+	}
+
+	for _, comp := range a.components {
+		m := metrics[componentKey(comp)]
+		check(comp, "fan-in", m.FanIn, thresholds.MaxFanIn)
+		check(comp, "fan-out", m.FanOut, thresholds.MaxFanOut)
+		if thresholds.MaxDepth > 0 && m.Depth < 0 {
+			violations = append(violations, ThresholdViolation{Component: comp, Metric: "depth", Value: m.Depth, Threshold: thresholds.MaxDepth, Via: via[componentKey(comp)]})
+		} else {
+			check(comp, "depth", m.Depth, thresholds.MaxDepth)
+		}
+		check(comp, "transitive dependencies", m.TransitiveDependencies, thresholds.MaxTransitiveDependencies)
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return componentKey(violations[i].Component) < componentKey(violations[j].Component)
+	})
+	return violations
+}
+
+// WriteThresholdViolations prints threshold violations in a human readable form
+func WriteThresholdViolations(w io.Writer, violations []ThresholdViolation) {
+	if len(violations) == 0 {
+		fmt.Fprintln(w, "\n✅ All components are within metric thresholds")
+		return
+	}
+	fmt.Fprintf(w, "\n🚨 Metric Threshold Violations (%d found):\n", len(violations))
+	for _, v := range violations {
+		if v.Metric == "depth" && v.Value < 0 {
+			reason := "in a dependency cycle"
+			if v.Via != "" {
+				reason = "depends on a cycle through " + v.Via
+			}
+			fmt.Fprintf(w, "  - %s.%s: depth is unbounded, %s, maximum %d [%s:%d]\n",
+				v.Component.Package, v.Component.Type, reason, v.Threshold,
+				v.Component.SourceFile, v.Component.LineNumber)
+			continue
+		}
+		fmt.Fprintf(w, "  - %s.%s: %s %d exceeds maximum %d [%s:%d]\n",
+			v.Component.Package, v.Component.Type, v.Metric, v.Value, v.Threshold,
+			v.Component.SourceFile, v.Component.LineNumber)
+	}
+}
@@ -0,0 +1,109 @@
+package wire
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzer_CalculateComponentMetrics(t *testing.T) {
+	metrics := NewAnalyzer(queryTestComponents()).CalculateComponentMetrics()
+
+	tests := []struct {
+		key        string
+		fanIn      int
+		fanOut     int
+		transitive int
+		depth      int
+	}{
+		{"example.com/app/logger.ConsoleLogger", 2, 0, 0, 0},
+		{"example.com/app/repository.UserRepository", 1, 1, 1, 1},
+		{"example.com/app/service.UserService", 1, 2, 2, 2},
+		{"example.com/app/handler.UserHandler", 0, 1, 3, 3},
+	}
+
+	for _, tt := range tests {
+		m, ok := metrics[tt.key]
+		if !ok {
+			t.Errorf("No metrics for %s", tt.key)
+			continue
+		}
+		if m.FanIn != tt.fanIn || m.FanOut != tt.fanOut || m.TransitiveDependencies != tt.transitive || m.Depth != tt.depth {
+			t.Errorf("%s: got fan-in %d, fan-out %d, transitive %d, depth %d; want %d, %d, %d, %d",
+				tt.key, m.FanIn, m.FanOut, m.TransitiveDependencies, m.Depth,
+				tt.fanIn, tt.fanOut, tt.transitive, tt.depth)
+		}
+	}
+}
+
+func TestAnalyzer_CalculatePackageMetrics(t *testing.T) {
+	metrics := NewAnalyzer(queryTestComponents()).CalculatePackageMetrics()
+
+	logger := metrics["example.com/app/logger"]
+	if logger.AfferentCoupling != 2 || logger.EfferentCoupling != 0 {
+		t.Errorf("Expected logger Ca=2 Ce=0, got Ca=%d Ce=%d", logger.AfferentCoupling, logger.EfferentCoupling)
+	}
+	if logger.Instability != 0 || logger.Abstractness != 1 {
+		t.Errorf("Expected logger I=0 A=1, got I=%.2f A=%.2f", logger.Instability, logger.Abstractness)
+	}
+
+	service := metrics["example.com/app/service"]
+	if service.AfferentCoupling != 1 || service.EfferentCoupling != 2 {
+		t.Errorf("Expected service Ca=1 Ce=2, got Ca=%d Ce=%d", service.AfferentCoupling, service.EfferentCoupling)
+	}
+	if math.Abs(service.Instability-2.0/3.0) > 1e-9 {
+		t.Errorf("Expected service instability 0.67, got %.2f", service.Instability)
+	}
+	if service.Abstractness != 0 {
+		t.Errorf("Expected service abstractness 0 (concrete dependency), got %.2f", service.Abstractness)
+	}
+
+	handler := metrics["example.com/app/handler"]
+	if handler.Instability != 1 || handler.DistanceFromMainSeq != 0 {
+		t.Errorf("Expected handler I=1 D=0, got I=%.2f D=%.2f", handler.Instability, handler.DistanceFromMainSeq)
+	}
+}
+
+func TestAnalyzer_CheckThresholds(t *testing.T) {
+	analyzer := NewAnalyzer(queryTestComponents())
+	metrics := analyzer.CalculateComponentMetrics()
+
+	violations := analyzer.CheckThresholds(metrics, MetricThresholds{MaxFanOut: 1, MaxDepth: 2})
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %d: %+v", len(violations), violations)
+	}
+	if violations[0].Component.Type != "UserHandler" || violations[0].Metric != "depth" {
+		t.Errorf("Expected UserHandler depth violation first, got %+v", violations[0])
+	}
+	if violations[1].Component.Type != "UserService" || violations[1].Metric != "fan-out" {
+		t.Errorf("Expected UserService fan-out violation, got %+v", violations[1])
+	}
+
+	if violations := analyzer.CheckThresholds(metrics, MetricThresholds{}); len(violations) != 0 {
+		t.Errorf("Expected zero thresholds to disable all checks, got %d violations", len(violations))
+	}
+
+	// Components in or depending on a cycle exceed any depth threshold
+	cyclic := NewAnalyzer([]Component{
+		{Name: "A", Type: "A", Package: "example.com/app/a", Dependencies: []Dependency{{FieldName: "B", Type: "b.B"}}},
+		{Name: "B", Type: "B", Package: "example.com/app/b", Dependencies: []Dependency{{FieldName: "A", Type: "a.A"}}},
+		{Name: "C", Type: "C", Package: "example.com/app/c", Dependencies: []Dependency{{FieldName: "A", Type: "a.A"}}},
+	})
+	violations = cyclic.CheckThresholds(cyclic.CalculateComponentMetrics(), MetricThresholds{MaxDepth: 10})
+	if len(violations) != 3 || violations[0].Metric != "depth" || violations[0].Value != -1 {
+		t.Fatalf("Expected depth violations for all three components, got %+v", violations)
+	}
+	if violations[0].Via != "" || violations[2].Via != "example.com/app/a.A" {
+		t.Errorf("Expected only C to depend on the cycle through A, got %+v", violations)
+	}
+	var out strings.Builder
+	WriteThresholdViolations(&out, violations)
+	for _, want := range []string{
+		"example.com/app/a.A: depth is unbounded, in a dependency cycle, maximum 10",
+		"example.com/app/c.C: depth is unbounded, depends on a cycle through example.com/app/a.A, maximum 10",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,702 @@
+package wire
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// DefaultMockProfile is the profile the generated fakes are active in
+const DefaultMockProfile = "test"
+
+// MockOptions configures the generation of fakes
+type MockOptions struct {
+	Scope     ParseOptions // Files searched for the interface declarations
+	OutputDir string       // Directory of the generated mocks_gen.go, defaults to <rootDir>/mocks
+	Profile   string       // Profile the fakes are active in, defaults to DefaultMockProfile
+}
+
+// mockFake is a call-recording fake of one interface
+type mockFake struct {
+	Name       string        // Fake type, e.g. FakeSender
+	Interface  string        // Faked interface as referenced from the mocks package, e.g. message.Sender
+	Implements string        // Interface as written in the implements tag of the fake
+	Methods    []mockMethod  // Method set of the interface
+	Markers    bool          // Whether the fake itself is the component of unqualified implementations
+	Qualified  []mockBinding // Components embedding the fake per qualifier of the implementations
+}
+
+// mockBinding is a component standing in for the implementations with one qualifier
+type mockBinding struct {
+	Name      string // Component type, e.g. FakeEmailSender
+	Qualifier string
+}
+
+// mockMethod is a method of a faked interface
+type mockMethod struct {
+	Name    string
+	Params  []mockParam
+	Results []string // Result types
+}
+
+// mockParam is a parameter of a faked method
+type mockParam struct {
+	Name     string // Parameter name in the fake's method
+	Field    string // Field of the call record
+	Type     string // Type as written in the signature, e.g. ...string
+	Variadic bool
+}
+
+// FuncType returns the type of the method's <Method>Func field
+func (m mockMethod) FuncType() string {
+	var params []string
+	for _, param := range m.Params {
+		params = append(params, param.Type)
+	}
+	signature := "func(" + strings.Join(params, ", ") + ")"
+	switch len(m.Results) {
+	case 0:
+		return signature
+	case 1:
+		return signature + " " + m.Results[0]
+	}
+	return signature + " (" + strings.Join(m.Results, ", ") + ")"
+}
+
+// ResultList returns the named results of the fake's method, which are returned
+// as zero values when no <Method>Func is set
+func (m mockMethod) ResultList() string {
+	if len(m.Results) == 0 {
+		return ""
+	}
+	var results []string
+	for i, result := range m.Results {
+		results = append(results, fmt.Sprintf("r%d %s", i, result))
+	}
+	return "(" + strings.Join(results, ", ") + ")"
+}
+
+// FieldType returns the type of the call record field of a parameter
+func (p mockParam) FieldType() string {
+	if p.Variadic {
+		return "[]" + strings.TrimPrefix(p.Type, "...")
+	}
+	return p.Type
+}
+
+// Arg returns the parameter as argument when forwarding the call
+func (p mockParam) Arg() string {
+	if p.Variadic {
+		return p.Name + "..."
+	}
+	return p.Name
+}
+
+// GenerateMocks writes fakes of the interfaces the components implement into
+// mocks_gen.go of the output directory and returns the path of the file
+func GenerateMocks(rootDir string, components []Component, opts MockOptions) (string, error) {
+	code, err := GenerateMockCode(rootDir, components, opts)
+	if err != nil {
+		return "", err
+	}
+	outputDir := mockOutputDir(rootDir, opts)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create mocks directory: %w", err)
+	}
+	path := filepath.Join(outputDir, "mocks_gen.go")
+	if err := os.WriteFile(path, code, 0644); err != nil {
+		return "", fmt.Errorf("failed to write generated mocks: %w", err)
+	}
+	return path, nil
+}
+
+// GenerateMockCode renders mocks_gen.go in memory. Every interface named in the
+// implements tag of a component gets a fake recording its calls, with a
+// <Method>Func field per method to configure the results. The fakes are
+// components of the mock profile implementing the interface, one per qualifier
+// of the real implementations, so that a container generated with that profile
+// injects them instead. Interfaces whose declaration cannot be read or faked are
+// skipped with a warning
+func GenerateMockCode(rootDir string, components []Component, opts MockOptions) ([]byte, error) {
+	scope, err := newScanScope(rootDir, opts.Scope)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Profile == "" {
+		opts.Profile = DefaultMockProfile
+	}
+	outputDir := mockOutputDir(rootDir, opts)
+
+	m := &mockPackage{
+		scope:    scope,
+		fset:     token.NewFileSet(),
+		dirs:     make(map[string]string),
+		bases:    make(map[string][]string),
+		decls:    make(map[string]map[string]interfaceDecl),
+		aliases:  make(map[string]string),
+		imported: make(map[string]bool),
+	}
+	dirs, err := scope.walkDirs()
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		if _, pkgPath, err := scope.modules.packagePath(dir); err == nil {
+			m.dirs[pkgPath] = dir
+			m.bases[packageBase(pkgPath)] = append(m.bases[packageBase(pkgPath)], pkgPath)
+		}
+	}
+	_, mocksPath, _ := scope.modules.packagePath(outputDir)
+
+	// Collect the faked interfaces with the qualifiers of their implementations
+	type fakedInterface struct {
+		pkgPath, name string
+		qualifiers    []string
+	}
+	faked := make(map[string]*fakedInterface)
+	for _, comp := range components {
+		if comp.Package == mocksPath {
+			continue
+		}
+		for _, iface := range comp.Implements {
+			pkgPath, name := m.resolveInterface(iface, comp)
+			if pkgPath == "" {
+				opts.Scope.logger().Printf("Warning: cannot find the package of interface %s implemented by %s, no fake generated", iface, componentKey(comp))
+				continue
+			}
+			key := pkgPath + "." + name
+			if faked[key] == nil {
+				faked[key] = &fakedInterface{pkgPath: pkgPath, name: name}
+			}
+			faked[key].qualifiers = appendUnique(faked[key].qualifiers, comp.Qualifier)
+		}
+	}
+	var keys []string
+	for key := range faked {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Interfaces sharing a name across packages are told apart by the package name
+	names := make(map[string]int)
+	for _, key := range keys {
+		names[faked[key].name]++
+	}
+
+	taken := make(map[string]bool)
+	unique := func(name string) string {
+		candidate := name
+		for n := 2; taken[candidate]; n++ {
+			candidate = fmt.Sprintf("%s%d", name, n)
+		}
+		taken[candidate] = true
+		return candidate
+	}
+
+	var fakes []mockFake
+	for _, key := range keys {
+		iface := faked[key]
+		imported := len(m.aliases)
+		methods, err := m.methodSet(iface.pkgPath, iface.name)
+		if err != nil {
+			opts.Scope.logger().Printf("Warning: no fake generated for %s: %v", key, err)
+			m.dropImports(imported)
+			continue
+		}
+
+		prefix := ""
+		if names[iface.name] > 1 {
+			prefix = exportedIdent(packageBase(iface.pkgPath))
+		}
+		fake := mockFake{
+			Name:       unique("Fake" + prefix + iface.name),
+			Interface:  m.importAlias(iface.pkgPath) + "." + iface.name,
+			Implements: key,
+			Methods:    methods,
+		}
+		sort.Strings(iface.qualifiers)
+		for _, qualifier := range iface.qualifiers {
+			if qualifier == "" {
+				fake.Markers = true
+				continue
+			}
+			fake.Qualified = append(fake.Qualified, mockBinding{
+				Name:      unique("Fake" + prefix + exportedIdent(qualifier) + iface.name),
+				Qualifier: qualifier,
+			})
+		}
+		fakes = append(fakes, fake)
+	}
+	if len(fakes) == 0 {
+		return nil, fmt.Errorf("no component implements an interface that can be faked")
+	}
+	return m.render(rootDir, outputDir, opts, fakes)
+}
+
+// mockOutputDir returns the absolute directory of the generated fakes
+func mockOutputDir(rootDir string, opts MockOptions) string {
+	switch {
+	case opts.OutputDir == "":
+		return filepath.Join(rootDir, "mocks")
+	case filepath.IsAbs(opts.OutputDir):
+		return filepath.Clean(opts.OutputDir)
+	}
+	return filepath.Join(rootDir, opts.OutputDir)
+}
+
+// interfaceDecl is an interface type declaration with the imports of its file
+type interfaceDecl struct {
+	pkgPath string
+	iface   *ast.InterfaceType
+	spec    *ast.TypeSpec
+	imports map[string]string // Package name -> import path
+}
+
+// mockPackage collects the interface declarations and the imports of the fakes
+type mockPackage struct {
+	scope *scanScope
+	fset  *token.FileSet
+	dirs  map[string]string   // Import path -> directory of the scanned packages
+	bases map[string][]string // Package name -> import paths of the scanned packages
+	decls map[string]map[string]interfaceDecl
+
+	order    []string          // Import paths in the order they were first used
+	aliases  map[string]string // Import path -> name used in the mocks package
+	imported map[string]bool   // Names already used for imports
+}
+
+// resolveInterface returns the import path and name of an interface of the
+// implements tag, which is recorded as path/Name for names of the component's
+// own package and otherwise as written: path.Name or pkg.Name
+func (m *mockPackage) resolveInterface(iface string, comp Component) (string, string) {
+	base, name := splitTypeRef(iface)
+	slash := strings.LastIndex(iface, "/")
+	if dot := strings.LastIndex(iface, "."); dot > slash && slash >= 0 {
+		return iface[:dot], name
+	}
+	if slash >= 0 {
+		return iface[:slash], name
+	}
+	if base == "" || base == packageBase(comp.Package) {
+		return comp.Package, name
+	}
+	if candidates := m.bases[base]; len(candidates) == 1 {
+		return candidates[0], name
+	}
+	return "", name
+}
+
+// methodSet returns the methods of an interface in declaration order, with the
+// methods of embedded interfaces in place of the embedding
+func (m *mockPackage) methodSet(pkgPath, name string) ([]mockMethod, error) {
+	decl, err := m.lookup(pkgPath, name)
+	if err != nil {
+		return nil, err
+	}
+	if decl.spec.TypeParams != nil {
+		return nil, fmt.Errorf("generic interfaces are not supported")
+	}
+
+	var methods []mockMethod
+	seen := make(map[string]bool)
+	add := func(method mockMethod) {
+		if !seen[method.Name] {
+			seen[method.Name] = true
+			methods = append(methods, method)
+		}
+	}
+	for _, field := range decl.iface.Methods.List {
+		if len(field.Names) > 0 {
+			funcType, ok := field.Type.(*ast.FuncType)
+			if !ok {
+				return nil, fmt.Errorf("unexpected method %s", field.Names[0].Name)
+			}
+			method, err := m.method(field.Names[0].Name, funcType, decl)
+			if err != nil {
+				return nil, err
+			}
+			add(method)
+			continue
+		}
+
+		// Embedded interface
+		var embedded []mockMethod
+		switch typ := field.Type.(type) {
+		case *ast.Ident:
+			if typ.Name == "error" {
+				embedded = []mockMethod{{Name: "Error", Results: []string{"string"}}}
+				break
+			}
+			embedded, err = m.methodSet(pkgPath, typ.Name)
+		case *ast.SelectorExpr:
+			pkg, ok := typ.X.(*ast.Ident)
+			if !ok || decl.imports[pkg.Name] == "" {
+				return nil, fmt.Errorf("cannot resolve embedded interface %s", types.ExprString(typ))
+			}
+			embedded, err = m.methodSet(decl.imports[pkg.Name], typ.Sel.Name)
+		default:
+			return nil, fmt.Errorf("type constraints are not supported")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("embedded %s: %w", types.ExprString(field.Type), err)
+		}
+		for _, method := range embedded {
+			add(method)
+		}
+	}
+	return methods, nil
+}
+
+// method converts a method declaration, qualifying its types for the mocks package
+func (m *mockPackage) method(name string, funcType *ast.FuncType, decl interfaceDecl) (mockMethod, error) {
+	method := mockMethod{Name: name}
+
+	used := map[string]bool{"f": true, "fn": true}
+	fields := make(map[string]bool)
+	index := 0
+	for _, field := range funcType.Params.List {
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{nil}
+		}
+		typ, err := m.qualify(field.Type, decl)
+		if err != nil {
+			return mockMethod{}, fmt.Errorf("method %s: %w", name, err)
+		}
+		_, variadic := field.Type.(*ast.Ellipsis)
+		for _, ident := range names {
+			paramName := fmt.Sprintf("p%d", index)
+			if ident != nil && ident.Name != "_" && !used[ident.Name] && !resultName.MatchString(ident.Name) {
+				paramName = ident.Name
+			}
+			used[paramName] = true
+
+			fieldName := exportedIdent(paramName)
+			for n := 2; fields[fieldName]; n++ {
+				fieldName = fmt.Sprintf("%s%d", exportedIdent(paramName), n)
+			}
+			fields[fieldName] = true
+
+			method.Params = append(method.Params, mockParam{
+				Name:     paramName,
+				Field:    fieldName,
+				Type:     types.ExprString(typ),
+				Variadic: variadic,
+			})
+			index++
+		}
+	}
+
+	if funcType.Results != nil {
+		for _, field := range funcType.Results.List {
+			typ, err := m.qualify(field.Type, decl)
+			if err != nil {
+				return mockMethod{}, fmt.Errorf("method %s: %w", name, err)
+			}
+			for range max(len(field.Names), 1) {
+				method.Results = append(method.Results, types.ExprString(typ))
+			}
+		}
+	}
+	return method, nil
+}
+
+// resultName matches the names of the fake's named results, which parameters must not shadow
+var resultName = regexp.MustCompile(`^r[0-9]+$`)
+
+// qualify returns a copy of a type expression of the interface's package that
+// is valid in the mocks package: types of the interface's own package are
+// qualified with its package name and imported packages are renamed to their
+// import names in the mocks package
+func (m *mockPackage) qualify(expr ast.Expr, decl interfaceDecl) (ast.Expr, error) {
+	var err error
+	var rewrite func(expr ast.Expr) ast.Expr
+	rewriteFields := func(list *ast.FieldList) *ast.FieldList {
+		if list == nil {
+			return nil
+		}
+		result := &ast.FieldList{}
+		for _, field := range list.List {
+			result.List = append(result.List, &ast.Field{Names: field.Names, Type: rewrite(field.Type)})
+		}
+		return result
+	}
+	rewrite = func(expr ast.Expr) ast.Expr {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			if types.Universe.Lookup(e.Name) != nil {
+				return e
+			}
+			if !ast.IsExported(e.Name) {
+				err = fmt.Errorf("unexported type %s cannot be used outside of its package", e.Name)
+				return e
+			}
+			return &ast.SelectorExpr{X: ast.NewIdent(m.importAlias(decl.pkgPath)), Sel: e}
+		case *ast.SelectorExpr:
+			pkg, ok := e.X.(*ast.Ident)
+			if !ok || decl.imports[pkg.Name] == "" {
+				err = fmt.Errorf("cannot resolve type %s", types.ExprString(e))
+				return e
+			}
+			return &ast.SelectorExpr{X: ast.NewIdent(m.importAlias(decl.imports[pkg.Name])), Sel: e.Sel}
+		case *ast.StarExpr:
+			return &ast.StarExpr{X: rewrite(e.X)}
+		case *ast.ParenExpr:
+			return &ast.ParenExpr{X: rewrite(e.X)}
+		case *ast.Ellipsis:
+			return &ast.Ellipsis{Elt: rewrite(e.Elt)}
+		case *ast.ArrayType:
+			return &ast.ArrayType{Len: e.Len, Elt: rewrite(e.Elt)}
+		case *ast.MapType:
+			return &ast.MapType{Key: rewrite(e.Key), Value: rewrite(e.Value)}
+		case *ast.ChanType:
+			return &ast.ChanType{Dir: e.Dir, Value: rewrite(e.Value)}
+		case *ast.FuncType:
+			return &ast.FuncType{Params: rewriteFields(e.Params), Results: rewriteFields(e.Results)}
+		case *ast.StructType:
+			return &ast.StructType{Fields: rewriteFields(e.Fields)}
+		case *ast.InterfaceType:
+			return &ast.InterfaceType{Methods: rewriteFields(e.Methods)}
+		case *ast.IndexExpr:
+			return &ast.IndexExpr{X: rewrite(e.X), Index: rewrite(e.Index)}
+		case *ast.IndexListExpr:
+			result := &ast.IndexListExpr{X: rewrite(e.X)}
+			for _, index := range e.Indices {
+				result.Indices = append(result.Indices, rewrite(index))
+			}
+			return result
+		}
+		err = fmt.Errorf("unsupported type %s", types.ExprString(expr))
+		return expr
+	}
+	result := rewrite(expr)
+	return result, err
+}
+
+// lookup returns the declaration of an interface, parsing its package on first use
+func (m *mockPackage) lookup(pkgPath, name string) (interfaceDecl, error) {
+	if _, ok := m.decls[pkgPath]; !ok {
+		dir, ok := m.dirs[pkgPath]
+		if !ok {
+			return interfaceDecl{}, fmt.Errorf("package %s is not scanned", pkgPath)
+		}
+		decls, err := m.parseInterfaces(pkgPath, dir)
+		if err != nil {
+			return interfaceDecl{}, err
+		}
+		m.decls[pkgPath] = decls
+	}
+	decl, ok := m.decls[pkgPath][name]
+	if !ok {
+		return interfaceDecl{}, fmt.Errorf("no interface %s is declared in %s", name, pkgPath)
+	}
+	return decl, nil
+}
+
+// parseInterfaces returns the interface declarations of the files of a package
+// that are part of the build
+func (m *mockPackage) parseInterfaces(pkgPath, dir string) (map[string]interfaceDecl, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	decls := make(map[string]interfaceDecl)
+	for _, entry := range entries {
+		fileName := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || !m.scope.includeFileName(fileName) {
+			continue
+		}
+		content, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		if match, err := m.scope.matchBuild(fileName, content); err != nil || !match {
+			continue
+		}
+		file, err := parser.ParseFile(m.fset, fileName, content, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+
+		imports := make(map[string]string)
+		for _, imp := range file.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			name := importName(path)
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			imports[name] = path
+		}
+		for _, d := range file.Decls {
+			gen, ok := d.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if iface, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+					decls[typeSpec.Name.Name] = interfaceDecl{pkgPath: pkgPath, iface: iface, spec: typeSpec, imports: imports}
+				}
+			}
+		}
+	}
+	return decls, nil
+}
+
+// dropImports forgets the imports added after the first count ones, which
+// belong to an interface that could not be faked
+func (m *mockPackage) dropImports(count int) {
+	for _, pkgPath := range m.order[count:] {
+		delete(m.imported, m.aliases[pkgPath])
+		delete(m.aliases, pkgPath)
+	}
+	m.order = m.order[:count]
+}
+
+// importAlias returns the name a package is imported with in the mocks package
+func (m *mockPackage) importAlias(pkgPath string) string {
+	if alias, ok := m.aliases[pkgPath]; ok {
+		return alias
+	}
+	name := importName(pkgPath)
+	alias := name
+	for n := 2; m.imported[alias] || alias == "sync"; n++ {
+		alias = fmt.Sprintf("%s%d", name, n)
+	}
+	m.order = append(m.order, pkgPath)
+	m.aliases[pkgPath] = alias
+	m.imported[alias] = true
+	return alias
+}
+
+// majorVersion matches the version suffix of module paths, e.g. v2
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName guesses the package name of an import path from its last element,
+// skipping a major version suffix
+func importName(pkgPath string) string {
+	parts := strings.Split(pkgPath, "/")
+	name := parts[len(parts)-1]
+	if majorVersion.MatchString(name) && len(parts) > 1 {
+		name = parts[len(parts)-2]
+	}
+	return strings.ReplaceAll(strings.TrimPrefix(name, "go-"), "-", "")
+}
+
+// render executes the template of mocks_gen.go
+func (m *mockPackage) render(rootDir, outputDir string, opts MockOptions, fakes []mockFake) ([]byte, error) {
+	generateArgs := "mocks --dir=" + relativeDir(outputDir, rootDir)
+	if outputDir != filepath.Join(rootDir, "mocks") {
+		generateArgs += " --out=" + relativeDir(rootDir, outputDir)
+	}
+	if opts.Profile != DefaultMockProfile {
+		generateArgs += " --mock-profile=" + opts.Profile
+	}
+	generateArgs += scanArgs(opts.Scope)
+
+	type importSpec struct{ Alias, Path string }
+	imports := []importSpec{{Path: "sync"}}
+	for pkgPath, alias := range m.aliases {
+		spec := importSpec{Path: pkgPath}
+		if alias != importName(pkgPath) || alias != packageBase(pkgPath) {
+			spec.Alias = alias
+		}
+		imports = append(imports, spec)
+	}
+	sort.Slice(imports, func(i, j int) bool { return imports[i].Path < imports[j].Path })
+
+	data := struct {
+		GenerateArgs string
+		Package      string
+		Profile      string
+		Imports      []importSpec
+		Fakes        []mockFake
+	}{generateArgs, packageNameFor(outputDir), opts.Profile, imports, fakes}
+
+	var buf bytes.Buffer
+	if err := mockTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("template execution failed: %w", err)
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated mocks failed: %w", err)
+	}
+	return code, nil
+}
+
+var mockTemplate = template.Must(template.New("mocks").Parse(`// File: mocks_gen.go
+// Code generated by Go IoC. DO NOT EDIT.
+//go:generate go run github.com/tuhuynh27/go-ioc/cmd/iocgen {{.GenerateArgs}}
+
+package {{.Package}}
+
+import ({{range .Imports}}
+    {{if .Alias}}{{.Alias}} {{end}}"{{.Path}}"{{end}}
+)
+{{range $fake := .Fakes}}
+// {{$fake.Name}} is a call-recording fake of {{$fake.Interface}}. Set the
+// <Method>Func fields to control its results, calls are recorded in <Method>Calls
+type {{$fake.Name}} struct {
+    {{- if $fake.Markers}}
+    Component struct{} ` + "`" + `implements:"{{$fake.Implements}}"` + "`" + `
+    Profile   struct{} ` + "`" + `value:"{{$.Profile}}"` + "`" + `
+    {{end}}
+    mu sync.Mutex
+    {{range $method := $fake.Methods}}
+    {{$method.Name}}Func  {{$method.FuncType}}
+    {{$method.Name}}Calls []{{$fake.Name}}{{$method.Name}}Call
+    {{- end}}
+}
+{{range $method := $fake.Methods}}
+// {{$fake.Name}}{{$method.Name}}Call holds the arguments of a call to {{$method.Name}}
+type {{$fake.Name}}{{$method.Name}}Call struct{{if $method.Params}} {
+    {{- range $method.Params}}
+    {{.Field}} {{.FieldType}}
+    {{- end}}
+}{{else}}{}{{end}}
+
+{{if $method.Results -}}
+// {{$method.Name}} records the call and returns the results of {{$method.Name}}Func,
+// or zero values if it is not set
+{{- else -}}
+// {{$method.Name}} records the call and calls {{$method.Name}}Func if it is set
+{{- end}}
+func (f *{{$fake.Name}}) {{$method.Name}}({{range $i, $p := $method.Params}}{{if $i}}, {{end}}{{$p.Name}} {{$p.Type}}{{end}}) {{$method.ResultList}} {
+    f.mu.Lock()
+    f.{{$method.Name}}Calls = append(f.{{$method.Name}}Calls, {{$fake.Name}}{{$method.Name}}Call{ {{- range $i, $p := $method.Params}}{{if $i}}, {{end}}{{$p.Field}}: {{$p.Name}}{{end -}} })
+    fn := f.{{$method.Name}}Func
+    f.mu.Unlock()
+    if fn != nil {
+        {{if $method.Results}}return {{end}}fn({{range $i, $p := $method.Params}}{{if $i}}, {{end}}{{$p.Arg}}{{end}})
+    }
+    {{- if $method.Results}}
+    return
+    {{- end}}
+}
+{{end}}
+{{- range $fake.Qualified}}
+// {{.Name}} is the {{$fake.Name}} replacing the implementations qualified
+// "{{.Qualifier}}" in the {{$.Profile}} profile
+type {{.Name}} struct {
+    Component struct{} ` + "`" + `implements:"{{$fake.Implements}}"` + "`" + `
+    Qualifier struct{} ` + "`" + `value:"{{.Qualifier}}"` + "`" + `
+    Profile   struct{} ` + "`" + `value:"{{$.Profile}}"` + "`" + `
+    {{$fake.Name}}
+}
+{{end}}
+var _ {{$fake.Interface}} = (*{{$fake.Name}})(nil)
+{{end}}`))
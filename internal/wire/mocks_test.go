@@ -0,0 +1,160 @@
+package wire
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// mockFiles is a project whose components implement interfaces of their own
+// package, of another package and with unexported parameter types
+var mockFiles = map[string]string{
+	"go.mod": "module example.com/shop\n\ngo 1.23\n",
+	"store/store.go": `package store
+
+import "context"
+
+type Order struct{ ID int }
+
+type Reader interface {
+	Find(ctx context.Context, id int) (*Order, error)
+}
+
+type Store interface {
+	Reader
+	Save(context.Context, ...*Order) error
+	Close()
+}
+
+type Memory struct {
+	Component struct{} ` + "`implements:\"Store\"`" + `
+}
+`,
+	"notify/notify.go": `package notify
+
+type Sender interface {
+	Send(to, body string) error
+}
+
+type options struct{}
+
+type Tuner interface {
+	Tune(o options)
+}
+
+type Radio struct {
+	Component struct{} ` + "`implements:\"Tuner\"`" + `
+}
+
+type Email struct {
+	Component struct{} ` + "`implements:\"Sender\"`" + `
+	Qualifier struct{} ` + "`value:\"email\"`" + `
+}
+
+type Sms struct {
+	Component struct{} ` + "`implements:\"Sender\"`" + `
+	Qualifier struct{} ` + "`value:\"sms\"`" + `
+}
+`,
+	"orders/service.go": `package orders
+
+import (
+	"example.com/shop/notify"
+	"example.com/shop/store"
+)
+
+type Service struct {
+	Component struct{}
+	Store     store.Store   ` + "`autowired:\"true\"`" + `
+	Sender    notify.Sender ` + "`autowired:\"true\" qualifier:\"email\"`" + `
+}
+`,
+}
+
+func TestGenerateMockCode(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, mockFiles)
+	components, err := ParseComponents(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+
+	code, err := GenerateMockCode(tmpDir, components, MockOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMockCode failed: %v", err)
+	}
+	for _, want := range []string{
+		"iocgen mocks --dir=..\n",
+		"\npackage mocks\n",
+		"\t\"context\"\n",
+		// The embedded interface's methods are part of the fake, local types are qualified
+		"FindFunc   func(context.Context, int) (*store.Order, error)",
+		"func (f *FakeStore) Find(ctx context.Context, id int) (r0 *store.Order, r1 error) {",
+		"func (f *FakeStore) Save(p0 context.Context, p1 ...*store.Order) (r0 error) {",
+		"P1 []*store.Order",
+		"return fn(p0, p1...)",
+		"func (f *FakeStore) Close() {",
+		"type FakeStoreCloseCall struct{}",
+		"Component struct{} `implements:\"example.com/shop/store.Store\"`\n\tProfile   struct{} `value:\"test\"`",
+		// Qualified implementations get one component per qualifier embedding the fake
+		"type FakeEmailSender struct {\n\tComponent struct{} `implements:\"example.com/shop/notify.Sender\"`\n\tQualifier struct{} `value:\"email\"`\n\tProfile   struct{} `value:\"test\"`\n\tFakeSender\n}",
+		"type FakeSmsSender struct {",
+		"var _ notify.Sender = (*FakeSender)(nil)",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Expected generated mocks to contain %q:\n%s", want, code)
+		}
+	}
+	// An interface with unexported parameter types cannot be implemented elsewhere
+	if strings.Contains(string(code), "Tuner") {
+		t.Errorf("Expected no fake of the Tuner interface:\n%s", code)
+	}
+}
+
+func TestGenerateMocks_ReplaceImplementationsInProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, mockFiles)
+	components, err := ParseComponents(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+
+	path, err := GenerateMocks(tmpDir, components, MockOptions{OutputDir: "internal/fakes", Profile: "unit"})
+	if err != nil {
+		t.Fatalf("GenerateMocks failed: %v", err)
+	}
+	if path != filepath.Join(tmpDir, "internal", "fakes", "mocks_gen.go") {
+		t.Errorf("Unexpected path of the generated mocks: %s", path)
+	}
+	code, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the mocks to be written: %v", err)
+	}
+	if !strings.Contains(string(code), "iocgen mocks --dir=../.. --out=internal/fakes --mock-profile=unit\n") || !strings.Contains(string(code), "\npackage fakes\n") {
+		t.Errorf("Unexpected header of the generated mocks:\n%s", code)
+	}
+
+	// Without the profile the fakes are left out
+	if again, err := ParseComponents(tmpDir); err != nil || len(again) != len(components) {
+		t.Fatalf("Expected the fakes to be inactive by default, got %d components (%v)", len(again), err)
+	}
+
+	active, _, err := ParseComponentsWithOptions(tmpDir, ParseOptions{Profiles: []string{"unit"}})
+	if err != nil {
+		t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+	}
+	var types []string
+	for _, comp := range active {
+		types = append(types, comp.Type)
+	}
+	sort.Strings(types)
+	expected := "FakeEmailSender,FakeSmsSender,FakeStore,Radio,Service"
+	if strings.Join(types, ",") != expected {
+		t.Errorf("Expected the fakes to replace the implementations, got %v", types)
+	}
+	if _, err := NewGenerator(active).GenerateCode(); err != nil {
+		t.Errorf("Expected the fakes to satisfy every dependency: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package wire
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Header markers recording the component model a wire_gen.go was generated from
+const (
+	modelHashMarker     = "// iocgen:model-hash "
+	componentHashMarker = "// iocgen:component "
+)
+
+// ModelHashes maps component keys (package.Type) to a hash of everything about
+// the component that influences the generated code
+type ModelHashes map[string]string
+
+// ComputeModelHashes hashes every component. Source positions, suppressions and
+// other analysis-only metadata are left out, so moving code does not change the hash
+func ComputeModelHashes(components []Component) ModelHashes {
+	hashes := make(ModelHashes, len(components))
+	for _, comp := range components {
+		h := sha256.New()
+		fmt.Fprintf(h, "name=%s\ntype=%s\npackage=%s\nqualifier=%s\n", comp.Name, comp.Type, comp.Package, comp.Qualifier)
+		fmt.Fprintf(h, "implements=%s\n", strings.Join(comp.Implements, ","))
+		for _, dep := range comp.Dependencies {
+			fmt.Fprintf(h, "dep=%s %s %s\n", dep.FieldName, dep.Type, dep.Qualifier)
+		}
+		fmt.Fprintf(h, "postConstruct=%t\npreDestroy=%t\nconstructor=%s\n", comp.PostConstruct, comp.PreDestroy, comp.Constructor)
+		if comp.Primary {
+			fmt.Fprintf(h, "primary=true\n")
+		}
+		if comp.Module != "" {
+			fmt.Fprintf(h, "module=%s\nexported=%t\n", comp.Module, comp.Exported)
+		}
+		if len(comp.Profiles) > 0 {
+			fmt.Fprintf(h, "profiles=%s\n", strings.Join(comp.Profiles, ","))
+		}
+		hashes[componentKey(comp)] = hex.EncodeToString(h.Sum(nil))[:16]
+	}
+	return hashes
+}
+
+// ModelHash combines the component hashes into a single hash for the whole model
+func (m ModelHashes) ModelHash() string {
+	h := sha256.New()
+	for _, key := range m.keys() {
+		fmt.Fprintf(h, "%s %s\n", key, m[key])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// keys returns the component keys in sorted order
+func (m ModelHashes) keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// modelHeader renders the header comment lines embedding the model hashes
+func modelHeader(components []Component) []string {
+	hashes := ComputeModelHashes(components)
+	lines := []string{modelHashMarker + hashes.ModelHash()}
+	for _, key := range hashes.keys() {
+		lines = append(lines, componentHashMarker+key+" "+hashes[key])
+	}
+	return lines
+}
+
+// ParseModelHeader extracts the model hashes from the header of a generated file.
+// ok is false when the file was generated without a model header
+func ParseModelHeader(code []byte) (modelHash string, hashes ModelHashes, ok bool) {
+	hashes = make(ModelHashes)
+	scanner := bufio.NewScanner(bytes.NewReader(code))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		if hash, found := strings.CutPrefix(line, modelHashMarker); found {
+			modelHash = strings.TrimSpace(hash)
+			ok = true
+		}
+		if entry, found := strings.CutPrefix(line, componentHashMarker); found {
+			if key, hash, valid := strings.Cut(strings.TrimSpace(entry), " "); valid {
+				hashes[key] = hash
+			}
+		}
+	}
+	return modelHash, hashes, ok
+}
+
+// ModelDrift lists the component changes between two models
+type ModelDrift struct {
+	Added   []string // Components that are new in the current model
+	Removed []string // Components that no longer exist
+	Changed []string // Components whose generated wiring changed
+}
+
+// Empty reports whether the models are identical
+func (d ModelDrift) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// CompareModels returns the component changes from the previous to the current model
+func CompareModels(previous, current ModelHashes) ModelDrift {
+	var drift ModelDrift
+	for _, key := range current.keys() {
+		old, existed := previous[key]
+		switch {
+		case !existed:
+			drift.Added = append(drift.Added, key)
+		case old != current[key]:
+			drift.Changed = append(drift.Changed, key)
+		}
+	}
+	for _, key := range previous.keys() {
+		if _, exists := current[key]; !exists {
+			drift.Removed = append(drift.Removed, key)
+		}
+	}
+	return drift
+}
@@ -0,0 +1,59 @@
+package wire
+
+import (
+	"testing"
+)
+
+func TestComputeModelHashes(t *testing.T) {
+	comp := Component{
+		Name:    "UserService",
+		Type:    "UserService",
+		Package: "example.com/app/service",
+		Dependencies: []Dependency{
+			{FieldName: "Logger", Type: "logger.Logger", LineNumber: 10},
+		},
+		SourceFile: "/app/service/user.go",
+		LineNumber: 8,
+	}
+	before := ComputeModelHashes([]Component{comp})
+
+	// Moving code around does not change the model
+	moved := comp
+	moved.LineNumber = 20
+	moved.Dependencies = []Dependency{{FieldName: "Logger", Type: "logger.Logger", LineNumber: 22}}
+	if ComputeModelHashes([]Component{moved}).ModelHash() != before.ModelHash() {
+		t.Error("Expected source positions not to affect the model hash")
+	}
+
+	// Changing the wiring does
+	requalified := comp
+	requalified.Dependencies = []Dependency{{FieldName: "Logger", Type: "logger.Logger", Qualifier: "json"}}
+	if ComputeModelHashes([]Component{requalified}).ModelHash() == before.ModelHash() {
+		t.Error("Expected a qualifier change to affect the model hash")
+	}
+}
+
+func TestCompareModels(t *testing.T) {
+	previous := ModelHashes{"a.A": "1", "a.B": "2", "a.C": "3"}
+	current := ModelHashes{"a.A": "1", "a.B": "9", "a.D": "4"}
+
+	drift := CompareModels(previous, current)
+	if len(drift.Added) != 1 || drift.Added[0] != "a.D" {
+		t.Errorf("Expected a.D to be added, got %v", drift.Added)
+	}
+	if len(drift.Removed) != 1 || drift.Removed[0] != "a.C" {
+		t.Errorf("Expected a.C to be removed, got %v", drift.Removed)
+	}
+	if len(drift.Changed) != 1 || drift.Changed[0] != "a.B" {
+		t.Errorf("Expected a.B to be changed, got %v", drift.Changed)
+	}
+	if !CompareModels(previous, previous).Empty() {
+		t.Error("Expected no drift between identical models")
+	}
+}
+
+func TestParseModelHeaderWithoutHeader(t *testing.T) {
+	if _, _, ok := ParseModelHeader([]byte("// Code generated by Go IoC. DO NOT EDIT.\npackage wire\n")); ok {
+		t.Error("Expected files without a model header to be detected")
+	}
+}
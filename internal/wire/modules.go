@@ -0,0 +1,296 @@
+package wire
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// moduleDecl is a container module declared by a struct with a Module marker:
+//
+//	type BillingModule struct {
+//		Module  struct{} `value:"billing"`
+//		Exports struct{} `value:"InvoiceService,PaymentGateway"`
+//	}
+//
+// The declaring package and its sub-packages belong to the module, unless a
+// sub-package declares a module of its own
+type moduleDecl struct {
+	Name       string   `json:"name"`
+	Package    string   `json:"package"`
+	Exports    []string `json:"exports,omitempty"` // Type names, component names or interfaces visible to other modules
+	SourceFile string   `json:"-"`
+	LineNumber int      `json:"line"`
+}
+
+// PrivateAccess is an autowired dependency on a component that another module does not export
+type PrivateAccess struct {
+	Component  Component  // Component declaring the dependency
+	Dependency Dependency // The autowired field
+	Target     Component  // Private component the field resolves to
+}
+
+// ModuleEdge aggregates the dependencies from the components of one module on another
+type ModuleEdge struct {
+	From         string // Depending module, empty for the root container
+	To           string // Module depended on, empty for the root container
+	Dependencies int    // Number of autowired fields crossing the boundary
+	Private      int    // How many of them inject components the module does not export
+}
+
+// parseModuleDecl recognizes a struct declaring a container module
+func parseModuleDecl(structType *ast.StructType, fullPkgPath, markers string) (moduleDecl, bool) {
+	decl := moduleDecl{Package: fullPkgPath}
+	for _, field := range structType.Fields.List {
+		marker := markerName(field, markers)
+		if marker == "Component" {
+			return moduleDecl{}, false
+		}
+		if field.Tag == nil {
+			continue
+		}
+		value := parseStructTag(field.Tag.Value)["value"]
+		switch marker {
+		case "Module":
+			decl.Name = strings.TrimSpace(value)
+		case "Exports":
+			for _, export := range strings.Split(value, ",") {
+				if export = strings.TrimSpace(export); export != "" {
+					decl.Exports = append(decl.Exports, export)
+				}
+			}
+		}
+	}
+	return decl, decl.Name != ""
+}
+
+// assignModules places every component into the module declared in its package
+// or the closest parent package, and marks the components their module exports
+func assignModules(components []Component, decls []moduleDecl, logger Logger) []Component {
+	if len(decls) == 0 {
+		return components
+	}
+
+	byPackage := make(map[string]moduleDecl)
+	exports := make(map[string][]string) // Module name -> exports of all its declarations
+	for _, decl := range decls {
+		if existing, ok := byPackage[decl.Package]; ok && existing.Name != decl.Name {
+			logger.Printf("Warning: package %s declares modules %s and %s, using %s (%s:%d)",
+				decl.Package, existing.Name, decl.Name, existing.Name, decl.SourceFile, decl.LineNumber)
+			continue
+		}
+		byPackage[decl.Package] = decl
+		exports[decl.Name] = append(exports[decl.Name], decl.Exports...)
+	}
+
+	for i := range components {
+		comp := &components[i]
+		for pkg := comp.Package; pkg != ""; pkg = parentPackage(pkg) {
+			if decl, ok := byPackage[pkg]; ok {
+				comp.Module = decl.Name
+				comp.Exported = exportsComponent(exports[decl.Name], *comp)
+				break
+			}
+		}
+	}
+	return components
+}
+
+// parentPackage returns the import path one level up, or an empty string
+func parentPackage(pkg string) string {
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		return pkg[:i]
+	}
+	return ""
+}
+
+// exportsComponent reports whether an export list names the component, by type,
+// component name, package.Type or an interface it implements. "*" exports everything
+func exportsComponent(exports []string, comp Component) bool {
+	for _, export := range exports {
+		if export == "*" || export == comp.Type || export == comp.Name || export == componentKey(comp) ||
+			export == packageBase(comp.Package)+"."+comp.Type {
+			return true
+		}
+		for _, iface := range comp.Implements {
+			ifaceBase, ifaceName := splitTypeRef(iface)
+			if export == iface || export == ifaceName || export == packageBase(ifaceBase)+"."+ifaceName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// canAccess reports whether a component of module from may inject target. Components
+// of the root container are visible everywhere, module components only inside
+// their module unless exported
+func canAccess(from string, target Component) bool {
+	return target.Module == "" || target.Module == from || target.Exported
+}
+
+// findPrivateAccesses returns the dependencies resolving to components that are
+// private to another module, in component order. Only the injected
+// implementations are checked, an alternative to a Primary is never wired
+func findPrivateAccesses(components []Component, index *componentIndex) []PrivateAccess {
+	var accesses []PrivateAccess
+	for _, comp := range components {
+		for _, dep := range comp.Dependencies {
+			for _, target := range index.injected(dep) {
+				if !canAccess(comp.Module, target) {
+					accesses = append(accesses, PrivateAccess{Component: comp, Dependency: dep, Target: target})
+				}
+			}
+		}
+	}
+	return accesses
+}
+
+// Message describes the access for diagnostics
+func (p PrivateAccess) Message() string {
+	from := "the root container"
+	if p.Component.Module != "" {
+		from = "module " + p.Component.Module
+	}
+	return fmt.Sprintf("Field %s of %s in %s injects %s, which is private to module %s; export it from %s or move the dependency",
+		p.Dependency.FieldName, componentKey(p.Component), from, componentKey(p.Target), p.Target.Module, p.Target.Module)
+}
+
+// FindPrivateAccesses returns the autowired dependencies that cross a module
+// boundary to a component the module does not export
+func (a *DependencyAnalyzer) FindPrivateAccesses() []PrivateAccess {
+	return findPrivateAccesses(a.components, a.index)
+}
+
+// HasModules reports whether any component belongs to a container module
+func (a *DependencyAnalyzer) HasModules() bool {
+	for _, comp := range a.components {
+		if comp.Module != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildModuleGraph aggregates the component dependencies into dependencies
+// between modules. Dependencies within a module are left out
+func (a *DependencyAnalyzer) BuildModuleGraph() []ModuleEdge {
+	edges := make(map[[2]string]*ModuleEdge)
+	index := a.componentsByKey()
+	forward, _ := a.resolvedEdges()
+	for _, comp := range a.components {
+		for _, edge := range forward[componentKey(comp)] {
+			target := index[edge.To]
+			if target.Module == comp.Module {
+				continue
+			}
+			key := [2]string{comp.Module, target.Module}
+			if edges[key] == nil {
+				edges[key] = &ModuleEdge{From: comp.Module, To: target.Module}
+			}
+			edges[key].Dependencies++
+			if !canAccess(comp.Module, target) {
+				edges[key].Private++
+			}
+		}
+	}
+
+	var graph []ModuleEdge
+	for _, edge := range edges {
+		graph = append(graph, *edge)
+	}
+	sort.Slice(graph, func(i, j int) bool {
+		if graph[i].From != graph[j].From {
+			return graph[i].From < graph[j].From
+		}
+		return graph[i].To < graph[j].To
+	})
+	return graph
+}
+
+// printModules prints the components per module and the module-level dependency graph
+func printModules(a *DependencyAnalyzer, analysis *AnalysisResult) {
+	counts := make(map[string][2]int) // Module -> exported, private
+	for _, comp := range a.components {
+		count := counts[comp.Module]
+		if comp.Exported || comp.Module == "" {
+			count[0]++
+		} else {
+			count[1]++
+		}
+		counts[comp.Module] = count
+	}
+
+	fmt.Printf("\n🧱 Modules (%d):\n", len(counts))
+	for _, module := range sortedKeys(counts) {
+		if module == "" {
+			fmt.Printf("  %s: %d components\n", moduleLabel(module), counts[module][0])
+			continue
+		}
+		fmt.Printf("  %s: %d exported, %d private\n", moduleLabel(module), counts[module][0], counts[module][1])
+	}
+
+	if len(analysis.ModuleGraph) > 0 {
+		fmt.Printf("\n🧱 Module Graph:\n")
+		for _, edge := range analysis.ModuleGraph {
+			fmt.Printf("  %s → %s (%d dependencies)", moduleLabel(edge.From), moduleLabel(edge.To), edge.Dependencies)
+			if edge.Private > 0 {
+				fmt.Printf(" ❌ %d on private components", edge.Private)
+			}
+			fmt.Println()
+		}
+	}
+
+	if len(analysis.PrivateAccesses) > 0 {
+		fmt.Printf("\n🔒 Private Component Access (%d found):\n", len(analysis.PrivateAccesses))
+		for _, access := range analysis.PrivateAccesses {
+			fmt.Printf("  - %s [%s:%d]\n", access.Message(), access.Component.SourceFile, access.Dependency.LineNumber)
+		}
+	} else {
+		fmt.Printf("\n✅ No module boundary violations found\n")
+	}
+}
+
+// moduleLabel names a module in reports
+func moduleLabel(module string) string {
+	if module == "" {
+		return "(root)"
+	}
+	return module
+}
+
+// exportedIdent turns a module name such as "user-accounts" into an exported Go identifier
+func exportedIdent(name string) string {
+	var ident strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if ident.Len() == 0 && unicode.IsDigit(r) {
+			ident.WriteByte('M')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		ident.WriteRune(r)
+	}
+	return ident.String()
+}
+
+// unexportedIdent lower-cases the first letter of an identifier, avoiding keywords
+func unexportedIdent(name string) string {
+	if name == "" {
+		return name
+	}
+	ident := strings.ToLower(name[:1]) + name[1:]
+	if token.IsKeyword(ident) {
+		ident += "_"
+	}
+	return ident
+}
@@ -0,0 +1,165 @@
+package wire
+
+import (
+	"log"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseComponents_Modules(t *testing.T) {
+	fixture := filepath.Join("testdata", "golden", "modules")
+	for _, opts := range []ParseOptions{{}, {CacheDir: t.TempDir()}} {
+		// Parse twice so that module declarations are also read back from the cache
+		for run := 0; run < 2; run++ {
+			components, _, err := ParseComponentsWithOptions(fixture, opts)
+			if err != nil {
+				t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+			}
+
+			modules := make(map[string]string)
+			for _, comp := range components {
+				visibility := "private"
+				if comp.Exported {
+					visibility = "exported"
+				}
+				modules[comp.Type] = comp.Module + ":" + visibility
+			}
+			expected := map[string]string{
+				"InvoiceService": "billing:exported",
+				"Ledger":         "billing:private",
+				"Table":          "billing:private", // Sub-packages belong to the module
+				"OrderService":   "orders:exported",
+				"Repository":     "orders:private",
+				"Clock":          ":private",
+				"Server":         ":private",
+			}
+			if !reflect.DeepEqual(modules, expected) {
+				t.Errorf("Expected modules %v, got %v", expected, modules)
+			}
+		}
+	}
+}
+
+func TestAssignModules_Exports(t *testing.T) {
+	components := []Component{
+		{Name: "Gateway", Type: "StripeGateway", Package: "example.com/app/billing/stripe", Implements: []string{"example.com/app/billing.PaymentGateway"}},
+		{Name: "invoices", Type: "InvoiceService", Package: "example.com/app/billing"},
+		{Name: "Ledger", Type: "Ledger", Package: "example.com/app/billing"},
+		{Name: "Mailer", Type: "Mailer", Package: "example.com/app/notify"},
+	}
+	decls := []moduleDecl{
+		{Name: "billing", Package: "example.com/app/billing", Exports: []string{"billing.PaymentGateway", "invoices"}},
+		{Name: "notify", Package: "example.com/app/notify", Exports: []string{"*"}},
+	}
+
+	exported := make(map[string]bool)
+	for _, comp := range assignModules(components, decls, log.Default()) {
+		exported[comp.Type] = comp.Exported
+	}
+	expected := map[string]bool{"StripeGateway": true, "InvoiceService": true, "Ledger": false, "Mailer": true}
+	if !reflect.DeepEqual(exported, expected) {
+		t.Errorf("Expected exports %v, got %v", expected, exported)
+	}
+}
+
+// privateAccessComponents lets the orders module inject the ledger billing keeps private
+func privateAccessComponents() []Component {
+	return []Component{
+		{Name: "Ledger", Type: "Ledger", Package: "example.com/app/billing", Module: "billing"},
+		{Name: "InvoiceService", Type: "InvoiceService", Package: "example.com/app/billing", Module: "billing", Exported: true,
+			Dependencies: []Dependency{{FieldName: "Ledger", Type: "Ledger"}}},
+		{Name: "OrderService", Type: "OrderService", Package: "example.com/app/orders", Module: "orders", SourceFile: "orders.go",
+			Dependencies: []Dependency{
+				{FieldName: "Invoices", Type: "billing.InvoiceService"},
+				{FieldName: "Ledger", Type: "billing.Ledger", LineNumber: 7},
+			}},
+	}
+}
+
+func TestGenerator_RejectsPrivateComponents(t *testing.T) {
+	_, err := NewGenerator(privateAccessComponents()).GenerateCode()
+	if err == nil {
+		t.Fatal("Expected GenerateCode to reject injecting a private component")
+	}
+	if !strings.Contains(err.Error(), "orders.go:7") || !strings.Contains(err.Error(), "private to module billing") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestGenerator_PrivateAlternativeToPrimary(t *testing.T) {
+	components := []Component{
+		{Type: "Main", Package: "example.com/app/root", Implements: []string{"example.com/app/api/Sender"}, Primary: true},
+		{Type: "Alt", Package: "example.com/app/billing", Implements: []string{"example.com/app/api/Sender"}, Module: "billing"},
+		{Type: "User", Package: "example.com/app/root", Dependencies: []Dependency{
+			{FieldName: "S", Type: "api.Sender"},
+		}},
+	}
+
+	// Only the primary implementation is injected, the private one is never wired
+	if _, err := NewGenerator(components).GenerateCode(); err != nil {
+		t.Fatalf("Expected the primary implementation to be accessible, got: %v", err)
+	}
+	if accesses := NewAnalyzer(components).FindPrivateAccesses(); len(accesses) != 0 {
+		t.Errorf("Expected no private accesses, got %+v", accesses)
+	}
+}
+
+func TestAnalyzer_PrivateComponentFinding(t *testing.T) {
+	result := NewAnalyzer(privateAccessComponents()).PerformComprehensiveAnalysis()
+
+	if len(result.PrivateAccesses) != 1 || result.PrivateAccesses[0].Dependency.FieldName != "Ledger" {
+		t.Fatalf("Expected one private access through field Ledger, got %+v", result.PrivateAccesses)
+	}
+	var found bool
+	for _, finding := range result.Findings {
+		if finding.RuleID == RulePrivateComponent {
+			found = true
+			if finding.Severity != "error" || finding.Fingerprint != "private-component:example.com/app/orders.OrderService.Ledger" {
+				t.Errorf("Unexpected finding %+v", finding)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected a private-component finding")
+	}
+
+	expected := []ModuleEdge{{From: "orders", To: "billing", Dependencies: 2, Private: 1}}
+	if !reflect.DeepEqual(result.ModuleGraph, expected) {
+		t.Errorf("Expected module graph %+v, got %+v", expected, result.ModuleGraph)
+	}
+}
+
+func TestAnalyzer_BuildModuleGraph(t *testing.T) {
+	components, err := ParseComponents(filepath.Join("testdata", "golden", "modules"))
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+
+	analyzer := NewAnalyzer(components)
+	expected := []ModuleEdge{
+		{From: "", To: "orders", Dependencies: 1},
+		{From: "billing", To: "", Dependencies: 1},
+		{From: "orders", To: "billing", Dependencies: 1},
+	}
+	if graph := analyzer.BuildModuleGraph(); !reflect.DeepEqual(graph, expected) {
+		t.Errorf("Expected module graph %+v, got %+v", expected, graph)
+	}
+	if accesses := analyzer.FindPrivateAccesses(); len(accesses) != 0 {
+		t.Errorf("Expected no private accesses, got %+v", accesses)
+	}
+}
+
+func TestModuleIdents(t *testing.T) {
+	for name, expected := range map[string]string{"billing": "Billing", "user-accounts": "UserAccounts", "v2_api": "V2Api", "2fa": "M2fa"} {
+		if got := exportedIdent(name); got != expected {
+			t.Errorf("exportedIdent(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+	for name, expected := range map[string]string{"Ledger": "ledger", "Type": "type_", "URLMapper": "uRLMapper"} {
+		if got := unexportedIdent(name); got != expected {
+			t.Errorf("unexportedIdent(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
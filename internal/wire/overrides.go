@@ -0,0 +1,138 @@
+package wire
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// optionInit is a typed Option of InitializeWithOverrides
+type optionInit struct {
+	Name      string // Option function, e.g. WithEmailSender
+	Field     string // Field of the overrides struct holding the replacement
+	Type      string // Go type of the replacement, e.g. *message.EmailSender or message.Sender
+	Interface bool   // Whether the option replaces an interface per qualifier instead of a component
+	Package   string // Import path of the replacement type
+	Subject   string // What the option replaces, for its doc comment
+}
+
+// overrideSite is an interface option and qualifier through which a component is injected
+type overrideSite struct {
+	Field     string // Override field of the interface option
+	Qualifier string // Qualifier of the injection
+}
+
+// overrideOptions returns one option per component and per interface that is
+// injected somewhere, with unique names. The dependencies injected through an
+// interface are pointed at the override field of their interface option
+func overrideOptions(inits []componentInit) []optionInit {
+	var options []optionInit
+	taken := make(map[string]bool)
+	for _, init := range inits {
+		taken[init.VarName] = true
+		options = append(options, optionInit{
+			Name:    "With" + init.VarName,
+			Field:   init.VarName,
+			Type:    "*" + packageBase(init.Package) + "." + init.Type,
+			Package: init.Package,
+			Subject: "the " + packageBase(init.Package) + "." + init.Type + " component",
+		})
+	}
+
+	byInterface := make(map[string]string) // Interface import path and name -> override field
+	for i := range inits {
+		for j := range inits[i].Dependencies {
+			dep := &inits[i].Dependencies[j]
+			if dep.InterfacePackage == "" {
+				continue
+			}
+			key := dep.InterfacePackage + "." + dep.InterfaceName
+			if field, ok := byInterface[key]; ok {
+				dep.Override = field
+				continue
+			}
+
+			field := dep.InterfaceName
+			if taken[field] {
+				field = exportedIdent(packageBase(dep.InterfacePackage)) + dep.InterfaceName
+			}
+			for n := 2; taken[field]; n++ {
+				field = fmt.Sprintf("%s%s%d", exportedIdent(packageBase(dep.InterfacePackage)), dep.InterfaceName, n)
+			}
+			taken[field] = true
+			byInterface[key] = field
+			dep.Override = field
+
+			options = append(options, optionInit{
+				Name:      "With" + field,
+				Field:     field,
+				Type:      packageBase(dep.InterfacePackage) + "." + dep.InterfaceName,
+				Interface: true,
+				Package:   dep.InterfacePackage,
+				Subject:   "the " + packageBase(dep.InterfacePackage) + "." + dep.InterfaceName + " implementation with the given qualifier",
+			})
+		}
+	}
+	return options
+}
+
+// overrideSites records on the components injected only through interface
+// options the options and qualifiers replacing them. Such a component is not
+// built when every one of them has a replacement. Components that are injected
+// by their own type, returned as roots or not injected at all are always built
+func overrideSites(inits []componentInit, roots []string) {
+	sites := make(map[string][]overrideSite) // Component variable -> interface injections
+	direct := make(map[string]bool)          // Component variables injected by their own type
+	for _, init := range inits {
+		for _, dep := range init.Dependencies {
+			if dep.Override == "" {
+				direct[dep.VarName] = true
+				continue
+			}
+			site := overrideSite{Field: dep.Override, Qualifier: dep.Qualifier}
+			if !slices.Contains(sites[dep.VarName], site) {
+				sites[dep.VarName] = append(sites[dep.VarName], site)
+			}
+		}
+	}
+
+	for i := range inits {
+		init := &inits[i]
+		if direct[init.VarName] || slices.Contains(roots, init.Package+"."+init.Type) {
+			continue
+		}
+		init.Overrides = sites[init.VarName]
+	}
+}
+
+// injectedInterface returns the import path and name of the interface through
+// which a dependency is injected, or empty strings when the field has the
+// component's own type or the interface package is unknown
+func (g *Generator) injectedInterface(dep Dependency, target Component) (string, string) {
+	depBase, depName := splitTypeRef(dep.Type)
+	if depBase == "" || (target.Type == depName && packageBase(target.Package) == depBase) {
+		return "", ""
+	}
+
+	for _, iface := range target.Implements {
+		ifaceBase, ifaceName := splitTypeRef(iface)
+		if ifaceName != depName || (ifaceBase != "" && ifaceBase != depBase) {
+			continue
+		}
+		// Interfaces are recorded as path/Name for local names and as written otherwise
+		slash := strings.LastIndex(iface, "/")
+		if dot := strings.LastIndex(iface, "."); dot > slash && slash >= 0 {
+			return iface[:dot], ifaceName
+		}
+		if slash >= 0 {
+			return iface[:slash], ifaceName
+		}
+		if packageBase(target.Package) == depBase {
+			return target.Package, ifaceName
+		}
+		if pkg := g.findPackageForType(depBase); pkg != "" {
+			return pkg, ifaceName
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,93 @@
+package wire
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerator_OverrideOptions(t *testing.T) {
+	components := []Component{
+		{Name: "Logger", Type: "Logger", Package: "example.com/app/audit", Implements: []string{"example.com/app/log/Logger"}},
+		{Name: "Mailer", Type: "Mailer", Package: "example.com/app/mail", Qualifier: "smtp", Implements: []string{"example.com/app/notify.Sender"}},
+		{Name: "Service", Type: "Service", Package: "example.com/app/service", Dependencies: []Dependency{
+			{FieldName: "Log", Type: "log.Logger"},
+			{FieldName: "Audit", Type: "audit.Logger"},
+			{FieldName: "Sender", Type: "notify.Sender", Qualifier: "smtp"},
+		}},
+	}
+
+	code, err := NewGenerator(components).GenerateCode()
+	if err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	for _, want := range []string{
+		// The component keeps the plain name, the interface is prefixed with its package
+		"func WithLogger(impl *audit.Logger) Option {",
+		"func WithLogLogger(qualifier string, impl log.Logger) Option {",
+		"func WithSender(qualifier string, impl notify.Sender) Option {",
+		"\"example.com/app/log\"",
+		"\"example.com/app/notify\"",
+		"Log:    override[log.Logger](o.LogLogger, \"\", container.Logger),",
+		"Audit:  container.Logger,",
+		"Sender: override[notify.Sender](o.Sender, \"smtp\", container.Mailer),",
+		"func InitializeWithOverrides(opts ...Option) (*Container, func()) {",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Expected generated code to contain %q:\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerator_InterfaceOverrideLifecycle(t *testing.T) {
+	components := []Component{
+		{Name: "Mailer", Type: "Mailer", Package: "example.com/app/mail", Qualifier: "smtp", Implements: []string{"example.com/app/notify.Sender"}, PostConstruct: true, PreDestroy: true},
+		{Name: "Pager", Type: "Pager", Package: "example.com/app/page", Implements: []string{"example.com/app/notify.Sender"}, PreDestroy: true},
+		{Name: "Service", Type: "Service", Package: "example.com/app/service", Dependencies: []Dependency{
+			{FieldName: "Sender", Type: "notify.Sender", Qualifier: "smtp"},
+			{FieldName: "Pager", Type: "notify.Sender"},
+		}},
+		{Name: "Admin", Type: "Admin", Package: "example.com/app/admin", Dependencies: []Dependency{
+			{FieldName: "Pager", Type: "page.Pager"},
+		}},
+	}
+
+	code, err := NewGenerator(components).GenerateCode()
+	if err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	for _, want := range []string{
+		// An implementation replaced wherever it is injected is neither built nor initialized
+		"if container.Mailer == nil && !overridden(o.Sender, \"smtp\") {\n\t\tcontainer.Mailer = &mail.Mailer{}\n\t\tcontainer.Mailer.PostConstruct()\n\t}",
+		"if o.Mailer == nil && container.Mailer != nil {\n\t\t\tcontainer.Mailer.PreDestroy()",
+		// An implementation also injected by its own type is always built
+		"if container.Pager == nil {",
+		"if o.Pager == nil {\n\t\t\tcontainer.Pager.PreDestroy()",
+		"func overridden[T any](replacements map[string]T, qualifier string) bool {",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Expected generated code to contain %q:\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerator_InjectedInterface(t *testing.T) {
+	gen := NewGenerator([]Component{{Type: "Config", Package: "example.com/app/ports"}})
+	tests := []struct {
+		depType    string
+		implements string
+		pkg        string
+	}{
+		{"store.Store", "example.com/app/store/Store", "example.com/app/store"},
+		{"store.Store", "example.com/app/store.Store", "example.com/app/store"},
+		{"ports.Store", "ports.Store", "example.com/app/ports"}, // Package of another component
+		{"other.Store", "other.Store", ""},                      // Unknown package, no option
+		{"store.Memory", "example.com/app/store/Store", ""},     // The component's own type
+	}
+	for _, tt := range tests {
+		target := Component{Type: "Memory", Package: "example.com/app/store", Implements: []string{tt.implements}}
+		pkg, _ := gen.injectedInterface(Dependency{Type: tt.depType}, target)
+		if pkg != tt.pkg {
+			t.Errorf("injectedInterface(%s, %s) = %q, expected %q", tt.depType, tt.implements, pkg, tt.pkg)
+		}
+	}
+}
@@ -1,312 +1,613 @@
 package wire
 
 import (
-	"bufio"
+	"context"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Component represents a parsed IoC component with its metadata
 type Component struct {
-	Name          string       // Name of the component (from name tag or type name)
-	Type          string       // The Go type name
-	Package       string       // Full package path
-	Qualifier     string       // Qualifier value for disambiguation
-	Implements    []string     // Interfaces implemented by this component
-	Dependencies  []Dependency // List of autowired dependencies
-	PostConstruct bool         // Whether component has PostConstruct method
-	PreDestroy    bool         // Whether component has PreDestroy method
-	Constructor   string       // Name of the constructor function (e.g., "NewUserService")
-	SourceFile    string       // Source file where component is defined
-	LineNumber    int          // Line number where component is defined
+	Name          string            // Name of the component (from name tag or type name)
+	Type          string            // The Go type name
+	Package       string            // Full package path
+	Qualifier     string            // Qualifier value for disambiguation
+	Implements    []string          // Interfaces implemented by this component
+	Dependencies  []Dependency      // List of autowired dependencies
+	PostConstruct bool              // Whether component has PostConstruct method
+	PreDestroy    bool              // Whether component has PreDestroy method
+	Constructor   string            // Name of the constructor function (e.g., "NewUserService")
+	SourceFile    string            // Source file where component is defined
+	LineNumber    int               // Line number where component is defined
+	Suppressions  map[string]string // Analyzer rule IDs suppressed for this component, mapped to the reason
+	Stereotype    string            // Role of the stereotype marker the component is declared with, like "service"
+	Primary       bool              // Whether the component is preferred over other implementations with its qualifier (Primary marker)
+	EntryPoint    bool              // Whether the component is an application root (EntryPoint marker)
+	Containers    []string          // Named containers the component is a root of (EntryPoint value)
+	Profiles      []string          // Profiles the component is only active in (Profile value), empty when always active
+	Module        string            // Container module the component belongs to, empty for the root container
+	Exported      bool              // Whether the component is exported by its module to other modules
 }
 
 // Dependency represents an autowired dependency field in a component
 type Dependency struct {
-	FieldName string // Name of the struct field
-	Type      string // Type of the dependency
-	Qualifier string // Qualifier for selecting specific implementation
+	FieldName  string // Name of the struct field
+	Type       string // Type of the dependency
+	Qualifier  string // Qualifier for selecting specific implementation
+	LineNumber int    // Line number of the autowired field
+	Column     int    // Column of the autowired field
 }
 
-// getModulePath finds and returns the Go module path from go.mod file
-// by walking up directories from rootDir until go.mod is found
-func getModulePath(rootDir string) (string, error) {
-	dir := rootDir
-	for {
-		modPath := filepath.Join(dir, "go.mod")
-		if _, err := os.Stat(modPath); err == nil {
-			// Found go.mod, read module path
-			file, err := os.Open(modPath)
-			if err != nil {
-				return "", err
-			}
-			defer file.Close()
+// ParseOptions controls how ParseComponentsWithOptions scans a directory tree
+type ParseOptions struct {
+	CacheDir string   // Directory of the scan cache, empty disables caching
+	Workers  int      // Number of directories parsed concurrently, defaults to GOMAXPROCS
+	Roots    []string // Directories to scan, relative to the root directory, defaults to all of it
+	Modules  []string // Package patterns of required modules to scan, like github.com/acme/platform/...
+	Include  []string // Glob patterns of files to scan below the root directory, all files when empty
+	Exclude  []string // Glob patterns of files and directories to leave out
+	Tags     []string // Build tags that are satisfied when evaluating build constraints
+	GOOS     string   // Target operating system for build constraints, defaults to $GOOS or the host
+	GOARCH   string   // Target architecture for build constraints, defaults to $GOARCH or the host
+	Profiles []string // Active profiles, components with a Profile marker are left out unless one of theirs is active
+	Logger   Logger   // Destination of progress messages and warnings, defaults to the standard logger
+
+	Stereotypes map[string]string // Markers counting as Component mapped to their role, besides types with a Stereotype meta-marker
+}
 
-			scanner := bufio.NewScanner(file)
-			for scanner.Scan() {
-				line := scanner.Text()
-				if strings.HasPrefix(line, "module ") {
-					return strings.TrimSpace(strings.TrimPrefix(line, "module ")), nil
-				}
-			}
-			return "", scanner.Err()
-		}
+// Logger receives progress messages and warnings, *log.Logger implements it
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
 
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			return "", os.ErrNotExist
-		}
-		dir = parent
+// logger returns the logger of the options or the standard logger
+func (opts ParseOptions) logger() Logger {
+	if opts.Logger == nil {
+		return log.Default()
 	}
+	return opts.Logger
+}
+
+// ParseStats describes the work done by a scan
+type ParseStats struct {
+	Files       int           // Go files scanned
+	CacheHits   int           // Files whose components were loaded from the cache
+	CacheMisses int           // Files that had to be parsed
+	Duration    time.Duration // Total scan time
 }
 
 // ParseComponents scans the given root directory for Go files containing IoC components
 // and returns a slice of parsed Component structs
 func ParseComponents(rootDir string) ([]Component, error) {
+	components, _, err := ParseComponentsWithOptions(rootDir, ParseOptions{})
+	return components, err
+}
+
+// ParseComponentsWithOptions scans the given root directory like ParseComponents.
+// Test files, hidden directories, vendor, testdata and node_modules are skipped,
+// as are files excluded by the patterns or build constraints of the options.
+// With a cache directory, files whose content did not change since the last scan
+// are not parsed again
+func ParseComponentsWithOptions(rootDir string, opts ParseOptions) ([]Component, ParseStats, error) {
+	return ParseComponentsContext(context.Background(), rootDir, opts)
+}
+
+// ParseComponentsContext is ParseComponentsWithOptions with a context. When the
+// context is canceled, directories not parsed yet are skipped and its error is returned
+func ParseComponentsContext(ctx context.Context, rootDir string, opts ParseOptions) ([]Component, ParseStats, error) {
 	startTime := time.Now()
+	logger := opts.logger()
 	var components []Component
+	var stats ParseStats
 	fset := token.NewFileSet() // Used for parsing Go source files
 
-	// First get the module path which is needed for constructing full package paths
-	modulePath, err := getModulePath(rootDir)
+	var cache *scanCache
+	if opts.CacheDir != "" {
+		cache = openScanCache(opts.CacheDir)
+	}
+
+	// Map directories to their modules, which are needed for constructing full package paths
+	scope, err := newScanScope(rootDir, opts)
 	if err != nil {
-		return nil, err
+		return nil, stats, err
 	}
 
-	// Walk through all directories under rootDir
-	err = filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	// Walk through all directories under the scan roots
+	dirs, err := scope.walkDirs()
+	if err != nil {
+		return nil, stats, err
+	}
+
+	// Parse directories on a pool of workers. Results are collected per directory
+	// so the component order stays the same as with a serial walk
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	results := make([]fileScan, len(dirs))
+	dirStats := make([]ParseStats, len(dirs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				// Parse all Go files in the current directory
+				dirScan, err := parsePackageDir(fset, scope, dirs[i], cache, &dirStats[i])
+				if err != nil {
+					logger.Printf("Error parsing directory %s: %v", dirs[i], err)
+					continue
+				}
+				results[i] = dirScan
+			}
+		}()
+	}
+queue:
+	for i := range dirs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break queue
 		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, stats, err
+	}
+
+	var decls []moduleDecl
+	var stereotypes []stereotypeDecl
+	var candidates []stereotypeCandidate
+	for i := range dirs {
+		components = append(components, results[i].Components...)
+		decls = append(decls, results[i].Modules...)
+		stereotypes = append(stereotypes, results[i].Stereotypes...)
+		candidates = append(candidates, results[i].Candidates...)
+		stats.Files += dirStats[i].Files
+		stats.CacheHits += dirStats[i].CacheHits
+		stats.CacheMisses += dirStats[i].CacheMisses
+	}
+
+	components = applyStereotypes(components, candidates, stereotypes, opts.Stereotypes, logger)
+	components = ActivateProfiles(assignModules(components, decls, logger), opts.Profiles)
+
+	if err := cache.save(); err != nil {
+		logger.Printf("Warning: failed to write scan cache: %v", err)
+	}
+
+	for _, modulePath := range scope.modules.missingRequirements(components) {
+		logger.Printf("Warning: components of module %s are wired into %s, but its go.mod does not require %s", modulePath, scope.modules.main.Path, modulePath)
+	}
+
+	stats.Duration = time.Since(startTime)
+	logger.Printf("Found %d components (scan completed in %v)", len(components), stats.Duration)
+
+	return components, stats, nil
+}
+
+// parsePackageDir parses the Go files of a single directory and returns the
+// components, container modules and stereotypes declared in it. Package paths are based on the module owning the
+// directory and only files in scope are parsed. When cache is not nil, files
+// with unchanged content are not parsed again
+func parsePackageDir(fset *token.FileSet, scope *scanScope, path string, cache *scanCache, stats *ParseStats) (fileScan, error) {
+	// Construct the full package path from the owning module
+	_, fullPkgPath, err := scope.modules.packagePath(path)
+	if err != nil {
+		return fileScan{}, err
+	}
 
-		// Skip non-directories and hidden directories (starting with .)
-		if !info.IsDir() {
-			return nil
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return fileScan{}, err
+	}
+
+	// Visit files in name order so the result does not depend on the file system
+	var dirScan fileScan
+	for _, entry := range entries {
+		fileName := filepath.Join(path, entry.Name())
+		if entry.IsDir() || !scope.includeFileName(fileName) {
+			continue
+		}
+		content, err := os.ReadFile(fileName)
+		if err != nil {
+			return fileScan{}, err
 		}
-		if strings.HasPrefix(filepath.Base(path), ".") {
-			return filepath.SkipDir
+		match, err := scope.matchBuild(fileName, content)
+		if err != nil {
+			return fileScan{}, err
 		}
+		if !match {
+			continue
+		}
+		stats.Files++
 
-		// Parse all Go files in the current directory
-		pkgs, err := parser.ParseDir(fset, path, nil, parser.ParseComments)
+		key := fileCacheKey(fullPkgPath, content)
+		if cached, ok := cache.lookup(key, fileName); ok {
+			for _, comp := range cached.Components {
+				comp.SourceFile = fileName
+				dirScan.Components = append(dirScan.Components, comp)
+			}
+			for _, decl := range cached.Modules {
+				decl.SourceFile = fileName
+				dirScan.Modules = append(dirScan.Modules, decl)
+			}
+			for _, candidate := range cached.Candidates {
+				candidate.Component.SourceFile = fileName
+				dirScan.Candidates = append(dirScan.Candidates, candidate)
+			}
+			dirScan.Stereotypes = append(dirScan.Stereotypes, cached.Stereotypes...)
+			stats.CacheHits++
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, fileName, content, parser.ParseComments)
 		if err != nil {
-			log.Printf("Error parsing directory %s: %v", path, err)
-			return nil
+			return fileScan{}, err
 		}
+		scan := extractComponents(fset, file, fileName, fullPkgPath)
+		cache.store(key, fileName, scan)
+		dirScan.Components = append(dirScan.Components, scan.Components...)
+		dirScan.Modules = append(dirScan.Modules, scan.Modules...)
+		dirScan.Stereotypes = append(dirScan.Stereotypes, scan.Stereotypes...)
+		dirScan.Candidates = append(dirScan.Candidates, scan.Candidates...)
+		stats.CacheMisses++
+	}
 
-		// Process each package
-		for _, pkg := range pkgs {
-			// Process each file in the package
-			for fileName, file := range pkg.Files {
-				// Inspect the AST of each file
-				ast.Inspect(file, func(n ast.Node) bool {
-					// Look for type declarations
-					typeSpec, ok := n.(*ast.TypeSpec)
-					if !ok {
-						return true
-					}
+	return dirScan, nil
+}
 
-					// Check if it's a struct type
-					structType, ok := typeSpec.Type.(*ast.StructType)
-					if !ok {
-						return true
-					}
+// extractComponents returns the components, container modules, stereotypes and
+// possibly stereotyped structs declared in a parsed file
+func extractComponents(fset *token.FileSet, file *ast.File, fileName, fullPkgPath string) fileScan {
+	var components []Component
+	var modules []moduleDecl
+
+	// Collect doc comments of type declarations for //ioc: directives
+	docs := typeDocs(file)
+	markers := markersImport(file)
+	imports := fileImports(file)
+	var stereotypes []stereotypeDecl
+	var candidates []stereotypeCandidate
+
+	// Inspect the AST of each file
+	ast.Inspect(file, func(n ast.Node) bool {
+		// Look for type declarations
+		typeSpec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
 
-					// Get relative package path and construct full package path
-					relPath, err := filepath.Rel(rootDir, path)
-					if err != nil {
-						log.Printf("Error getting relative path: %v", err)
-						return true
-					}
-					fullPkgPath := filepath.Join(modulePath, relPath)
-					fullPkgPath = strings.ReplaceAll(fullPkgPath, string(filepath.Separator), "/")
-
-					// Get source location information
-					position := fset.Position(typeSpec.Pos())
-
-					// Initialize component with basic info
-					comp := Component{
-						Name:       typeSpec.Name.Name,
-						Type:       typeSpec.Name.Name,
-						Package:    fullPkgPath,
-						SourceFile: fileName,
-						LineNumber: position.Line,
-					}
+		// Check if it's a struct type
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
 
-					// Analyze struct fields for component markers and metadata
-					hasComponent := false
-					for _, field := range structType.Fields.List {
-						// Check if field is the IoC Component marker
-						if len(field.Names) > 0 && field.Names[0].Name == "Component" {
-							if _, ok := field.Type.(*ast.StructType); ok {
-								hasComponent = true
-								// Check for name override in tag
-								if field.Tag != nil {
-									tag := parseStructTag(field.Tag.Value)
-									if name, ok := tag["name"]; ok {
-										comp.Name = name
-									}
-								}
-							}
-						}
+		// Get source location information
+		position := fset.Position(typeSpec.Pos())
 
-						// Process struct tags if present
-						if field.Tag != nil {
-							tag := parseStructTag(field.Tag.Value)
+		// Initialize component with basic info
+		comp := Component{
+			Name:       typeSpec.Name.Name,
+			Type:       typeSpec.Name.Name,
+			Package:    fullPkgPath,
+			SourceFile: fileName,
+			LineNumber: position.Line,
+		}
+		addSuppressions(&comp, parseIgnoreDirectives(docs[typeSpec]))
+
+		// A struct with a Module marker declares a container module instead of a component
+		if decl, ok := parseModuleDecl(structType, fullPkgPath, markers); ok {
+			decl.SourceFile = fileName
+			decl.LineNumber = position.Line
+			modules = append(modules, decl)
+			return true
+		}
 
-							// Check if this field has a "value" tag and is a Qualifier field
-							value, hasValue := tag["value"]
-							isQualifierField := len(field.Names) > 0 && field.Names[0].Name == "Qualifier"
-							isEmptyStruct := false
-							if _, ok := field.Type.(*ast.StructType); ok {
-								isEmptyStruct = true
-							}
+		// A struct with a Stereotype meta-marker declares a stereotype instead of a component
+		if decl, ok := parseStereotypeDecl(typeSpec, structType, fullPkgPath, markers); ok {
+			stereotypes = append(stereotypes, decl)
+			return true
+		}
 
-							if hasValue && isQualifierField && isEmptyStruct {
-								comp.Qualifier = value
-							}
+		// Analyze struct fields for component markers and metadata
+		hasComponent := false
+		var stereotypeMarkers []string
+		for _, field := range structType.Fields.List {
+			// Markers are typed like ioc.Component, or struct{} fields named after the marker
+			marker := markerName(field, markers)
+
+			// Check if field is the IoC Component marker
+			if marker == "Component" {
+				hasComponent = true
+				if field.Tag != nil {
+					applyComponentTag(&comp, parseStructTag(field.Tag.Value))
+				}
+			}
 
-							// Check for implements declarations
-							if impl, ok := tag["implements"]; ok {
-								// Convert relative interface paths to absolute
-								if !strings.Contains(impl, ".") {
-									impl = filepath.Join(fullPkgPath, impl)
-									impl = strings.ReplaceAll(impl, string(filepath.Separator), "/")
-								}
-								comp.Implements = append(comp.Implements, impl)
-							}
+			// Any other marker may be a stereotype, which counts as Component once resolved
+			if stereotype := stereotypeMarker(field, fullPkgPath, markers, imports); stereotype != "" {
+				stereotypeMarkers = append(stereotypeMarkers, stereotype)
+				if field.Tag != nil && !hasComponent {
+					applyComponentTag(&comp, parseStructTag(field.Tag.Value))
+				}
+			}
 
-							// Process autowired dependencies
-							if _, ok := tag["autowired"]; ok {
-								dep := Dependency{
-									FieldName: field.Names[0].Name,
-									Qualifier: tag["qualifier"],
-								}
+			// Check for the Primary marker preferred among implementations of an interface
+			if marker == "Primary" {
+				comp.Primary = true
+			}
 
-								// Extract type information based on AST node type
-								var typ string
-								switch t := field.Type.(type) {
-								case *ast.Ident: // Simple type
-									typ = t.Name
-								case *ast.StarExpr: // Pointer type
-									switch x := t.X.(type) {
-									case *ast.Ident:
-										typ = x.Name
-									case *ast.SelectorExpr:
-										if y, ok := x.X.(*ast.Ident); ok {
-											typ = y.Name + "." + x.Sel.Name
-										}
-									}
-								case *ast.SelectorExpr: // Qualified type
-									if x, ok := t.X.(*ast.Ident); ok {
-										typ = x.Name + "." + t.Sel.Name
-									}
-								}
-								dep.Type = typ
+			// Check for the EntryPoint marker of application roots
+			if marker == "EntryPoint" {
+				comp.EntryPoint = true
+				if field.Tag != nil {
+					for _, name := range strings.Split(parseStructTag(field.Tag.Value)["value"], ",") {
+						if name = strings.TrimSpace(name); name != "" {
+							comp.Containers = append(comp.Containers, name)
+						}
+					}
+				}
+			}
+
+			// Check for the Profile marker of components only active in some profiles
+			if marker == "Profile" && field.Tag != nil {
+				for _, profile := range strings.Split(parseStructTag(field.Tag.Value)["value"], ",") {
+					if profile = strings.TrimSpace(profile); profile != "" {
+						comp.Profiles = append(comp.Profiles, profile)
+					}
+				}
+			}
 
-								comp.Dependencies = append(comp.Dependencies, dep)
+			// Process struct tags if present
+			if field.Tag != nil {
+				tag := parseStructTag(field.Tag.Value)
+
+				// Check if this field has a "value" tag and is a Qualifier field
+				if value, ok := tag["value"]; ok && marker == "Qualifier" {
+					comp.Qualifier = value
+				}
+
+				// Check for implements declarations
+				if impl, ok := tag["implements"]; ok {
+					// Convert relative interface paths to absolute
+					if !strings.Contains(impl, ".") {
+						impl = filepath.Join(fullPkgPath, impl)
+						impl = strings.ReplaceAll(impl, string(filepath.Separator), "/")
+					}
+					comp.Implements = append(comp.Implements, impl)
+				}
+
+				// Process autowired dependencies, embedded fields cannot be autowired
+				if _, ok := tag["autowired"]; ok && len(field.Names) > 0 {
+					fieldPosition := fset.Position(field.Pos())
+					dep := Dependency{
+						FieldName:  field.Names[0].Name,
+						Qualifier:  tag["qualifier"],
+						LineNumber: fieldPosition.Line,
+						Column:     fieldPosition.Column,
+					}
+
+					// Extract type information based on AST node type
+					var typ string
+					switch t := field.Type.(type) {
+					case *ast.Ident: // Simple type
+						typ = t.Name
+					case *ast.StarExpr: // Pointer type
+						switch x := t.X.(type) {
+						case *ast.Ident:
+							typ = x.Name
+						case *ast.SelectorExpr:
+							if y, ok := x.X.(*ast.Ident); ok {
+								typ = y.Name + "." + x.Sel.Name
 							}
 						}
+					case *ast.SelectorExpr: // Qualified type
+						if x, ok := t.X.(*ast.Ident); ok {
+							typ = x.Name + "." + t.Sel.Name
+						}
 					}
+					dep.Type = typ
+
+					comp.Dependencies = append(comp.Dependencies, dep)
+				}
+			}
+		}
 
-					// Only add if it's a valid component
-					if hasComponent {
-						// Check for PostConstruct and PreDestroy methods
-						for _, decl := range file.Decls {
-							if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-								// Check if it's a method on our component type
-								if funcDecl.Recv != nil && len(funcDecl.Recv.List) == 1 {
-									recvType := funcDecl.Recv.List[0].Type
-									if starExpr, ok := recvType.(*ast.StarExpr); ok {
-										if ident, ok := starExpr.X.(*ast.Ident); ok {
-											if ident.Name == comp.Type {
-												// Check for PostConstruct method
-												if funcDecl.Name.Name == "PostConstruct" {
-													// Check if method has no parameters and no return values
-													if funcDecl.Type.Params.NumFields() == 0 &&
-														(funcDecl.Type.Results == nil || funcDecl.Type.Results.NumFields() == 0) {
-														comp.PostConstruct = true
-													}
-												}
-												// Check for PreDestroy method
-												if funcDecl.Name.Name == "PreDestroy" {
-													// Check if method has no parameters and no return values
-													if funcDecl.Type.Params.NumFields() == 0 &&
-														(funcDecl.Type.Results == nil || funcDecl.Type.Results.NumFields() == 0) {
-														comp.PreDestroy = true
-													}
-												}
-											}
+		// Only add if it's a valid component or may be one with a stereotype
+		if hasComponent || len(stereotypeMarkers) > 0 {
+			// Check for PostConstruct and PreDestroy methods
+			for _, decl := range file.Decls {
+				if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+					// Check if it's a method on our component type
+					if funcDecl.Recv != nil && len(funcDecl.Recv.List) == 1 {
+						recvType := funcDecl.Recv.List[0].Type
+						if starExpr, ok := recvType.(*ast.StarExpr); ok {
+							if ident, ok := starExpr.X.(*ast.Ident); ok {
+								if ident.Name == comp.Type {
+									// Check for PostConstruct method
+									if funcDecl.Name.Name == "PostConstruct" {
+										// Check if method has no parameters and no return values
+										if funcDecl.Type.Params.NumFields() == 0 &&
+											(funcDecl.Type.Results == nil || funcDecl.Type.Results.NumFields() == 0) {
+											comp.PostConstruct = true
+										}
+									}
+									// Check for PreDestroy method
+									if funcDecl.Name.Name == "PreDestroy" {
+										// Check if method has no parameters and no return values
+										if funcDecl.Type.Params.NumFields() == 0 &&
+											(funcDecl.Type.Results == nil || funcDecl.Type.Results.NumFields() == 0) {
+											comp.PreDestroy = true
 										}
 									}
 								}
 							}
 						}
+					}
+				}
+			}
 
-						// Look for constructor function
-						for _, decl := range file.Decls {
-							if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-								// Check if it's a constructor function (returns pointer to our type)
-								if funcDecl.Type.Results != nil && len(funcDecl.Type.Results.List) == 1 {
-									if starExpr, ok := funcDecl.Type.Results.List[0].Type.(*ast.StarExpr); ok {
-										if ident, ok := starExpr.X.(*ast.Ident); ok {
-											if ident.Name == comp.Type {
-												comp.Constructor = funcDecl.Name.Name
-											}
-										}
-									}
+			// Look for constructor function
+			for _, decl := range file.Decls {
+				if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+					// Check if it's a constructor function (returns pointer to our type)
+					if funcDecl.Type.Results != nil && len(funcDecl.Type.Results.List) == 1 {
+						if starExpr, ok := funcDecl.Type.Results.List[0].Type.(*ast.StarExpr); ok {
+							if ident, ok := starExpr.X.(*ast.Ident); ok {
+								if ident.Name == comp.Type {
+									comp.Constructor = funcDecl.Name.Name
 								}
 							}
 						}
-
-						components = append(components, comp)
 					}
+				}
+			}
 
-					return true
-				})
+			if len(stereotypeMarkers) > 0 {
+				candidates = append(candidates, stereotypeCandidate{Component: comp, Markers: stereotypeMarkers, Declared: hasComponent})
+			}
+			if hasComponent {
+				components = append(components, comp)
 			}
 		}
 
-		return nil
+		return true
 	})
 
-	if err != nil {
-		return nil, err
-	}
-
-	log.Printf("Found %d components (scan completed in %v)", len(components), time.Since(startTime))
-
-	return components, nil
+	return fileScan{Components: components, Modules: modules, Stereotypes: stereotypes, Candidates: candidates}
 }
 
-// parseStructTag parses a Go struct tag string into a map of key-value pairs
+// parseStructTag parses a Go struct tag string into a map of key-value pairs.
+// Values follow the reflect.StructTag conventions and may contain spaces
 func parseStructTag(tag string) map[string]string {
 	tag = strings.Trim(tag, "`")
 	tags := make(map[string]string)
 
-	// Split tag into space-separated sections
-	for _, section := range strings.Split(tag, " ") {
-		if section == "" {
-			continue
+	for tag != "" {
+		// Skip leading space
+		tag = strings.TrimLeft(tag, " \t")
+
+		// Scan to the colon; a space, a quote or a control character is a syntax error
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		// Scan the quoted value
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			break
+		}
+		tags[key] = value
+		tag = tag[i+1:]
+	}
+
+	return tags
+}
+
+// applyComponentTag applies the tag of a Component marker or a stereotype to the component
+func applyComponentTag(comp *Component, tag map[string]string) {
+	// Check for name override in tag
+	if name, ok := tag["name"]; ok {
+		comp.Name = name
+	}
+	// Suppress analyzer findings, e.g. ignore:"unused" reason:"HTTP entry point"
+	if ignore, ok := tag["ignore"]; ok {
+		reason := tag["reason"]
+		if reason == "" {
+			reason = "suppressed by ignore tag"
+		}
+		suppressions := make(map[string]string)
+		for _, rule := range strings.Split(ignore, ",") {
+			suppressions[normalizeRuleID(strings.TrimSpace(rule))] = reason
 		}
+		addSuppressions(comp, suppressions)
+	}
+}
 
-		// Split each section into key:value
-		parts := strings.Split(section, ":")
-		if len(parts) != 2 {
+// typeDocs maps every type spec in a file to its doc comment. For single-spec
+// declarations the comment is attached to the enclosing GenDecl
+func typeDocs(file *ast.File) map[*ast.TypeSpec]*ast.CommentGroup {
+	docs := make(map[*ast.TypeSpec]*ast.CommentGroup)
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
 			continue
 		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if typeSpec.Doc != nil {
+				docs[typeSpec] = typeSpec.Doc
+			} else if len(genDecl.Specs) == 1 {
+				docs[typeSpec] = genDecl.Doc
+			}
+		}
+	}
+	return docs
+}
 
-		// Clean up quotes and store in map
-		key := strings.Trim(parts[0], "\"")
-		value := strings.Trim(parts[1], "\"")
-		tags[key] = value
+// parseIgnoreDirectives extracts "//ioc:ignore <rule>[,<rule>] [reason]" directives
+func parseIgnoreDirectives(doc *ast.CommentGroup) map[string]string {
+	suppressions := make(map[string]string)
+	if doc == nil {
+		return suppressions
 	}
+	for _, comment := range doc.List {
+		text, ok := strings.CutPrefix(comment.Text, "//ioc:ignore")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			fields = []string{"all"}
+		}
+		reason := strings.TrimSpace(strings.Join(fields[1:], " "))
+		if reason == "" {
+			reason = "suppressed by //ioc:ignore directive"
+		}
+		for _, rule := range strings.Split(fields[0], ",") {
+			suppressions[normalizeRuleID(rule)] = reason
+		}
+	}
+	return suppressions
+}
 
-	return tags
+// addSuppressions merges suppressions into the component
+func addSuppressions(comp *Component, suppressions map[string]string) {
+	for rule, reason := range suppressions {
+		if comp.Suppressions == nil {
+			comp.Suppressions = make(map[string]string)
+		}
+		comp.Suppressions[rule] = reason
+	}
 }
@@ -73,6 +73,9 @@ go 1.20
 			if comp.Dependencies[0].Type != "logger.Logger" {
 				t.Errorf("Expected logger.Logger dependency, got %s", comp.Dependencies[0].Type)
 			}
+			if comp.Dependencies[0].LineNumber != 11 {
+				t.Errorf("Expected dependency on line 11, got %d", comp.Dependencies[0].LineNumber)
+			}
 			if comp.Package != "example.com/test/message" {
 				t.Errorf("Expected package example.com/test/message, got %s", comp.Package)
 			}
@@ -187,6 +190,14 @@ func TestParseStructTag(t *testing.T) {
 				"implements": "logger.Logger",
 			},
 		},
+		{
+			name: "values with spaces and colons",
+			tag:  "`ignore:\"unused\" reason:\"started by main: HTTP server\"`",
+			expected: map[string]string{
+				"ignore": "unused",
+				"reason": "started by main: HTTP server",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -311,3 +322,78 @@ go 1.20
 		}
 	}
 }
+
+func TestParseComponentsWithSuppressions(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/test\ngo 1.20\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	content := `package app
+
+// Server is started by main.
+//
+//ioc:ignore unused started directly by main
+type Server struct {
+	Component struct{}
+}
+
+type LegacyMailer struct {
+	Component struct{} ` + "`ignore:\"qualifier,cycle\" reason:\"kept for old clients\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write source file: %v", err)
+	}
+
+	components, err := ParseComponents(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+
+	for _, comp := range components {
+		switch comp.Type {
+		case "Server":
+			if reason := comp.Suppressions[RuleUnusedComponent]; reason != "started directly by main" {
+				t.Errorf("Expected unused suppression with reason, got %v", comp.Suppressions)
+			}
+		case "LegacyMailer":
+			if comp.Suppressions[RuleQualifierConflict] != "kept for old clients" || comp.Suppressions[RuleCircularDependency] == "" {
+				t.Errorf("Expected qualifier and cycle suppressions from tag, got %v", comp.Suppressions)
+			}
+		}
+	}
+}
+
+func TestParseComponentsWithEntryPoint(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/test\ngo 1.20\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	content := `package app
+
+type Server struct {
+	Component  struct{}
+	EntryPoint struct{}
+}
+
+type Worker struct {
+	Component struct{}
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write source file: %v", err)
+	}
+
+	components, err := ParseComponents(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+
+	for _, comp := range components {
+		if comp.EntryPoint != (comp.Type == "Server") {
+			t.Errorf("Unexpected EntryPoint=%v for %s", comp.EntryPoint, comp.Type)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package wire
+
+import "strings"
+
+// ActivateProfiles returns the components that are active in the given profiles.
+// Components without a Profile marker are always active, profiled ones only when
+// one of their profiles is. An active profiled component replaces the unprofiled
+// components implementing the same interface with the same qualifier, so that a
+// fake of the test profile stands in for the real implementation
+func ActivateProfiles(components []Component, profiles []string) []Component {
+	active := make(map[string]bool, len(profiles))
+	for _, profile := range profiles {
+		active[profile] = true
+	}
+
+	replaced := make(map[string]bool) // Interface and qualifier provided by an active profile
+	for _, comp := range components {
+		if profileActive(comp, active) && len(comp.Profiles) > 0 {
+			for _, iface := range comp.Implements {
+				replaced[interfaceKey(iface, comp.Qualifier)] = true
+			}
+		}
+	}
+
+	var result []Component
+	for _, comp := range components {
+		if !profileActive(comp, active) {
+			continue
+		}
+		if len(comp.Profiles) == 0 && replacedByProfile(comp, replaced) {
+			continue
+		}
+		result = append(result, comp)
+	}
+	return result
+}
+
+// ForProfiles records the active profiles in the go:generate directive, so that
+// regenerating the file selects the same components
+func (g *Generator) ForProfiles(profiles []string) *Generator {
+	if len(profiles) > 0 {
+		g.generateArgs += " --profile=" + strings.Join(profiles, ",")
+	}
+	return g
+}
+
+// profileActive reports whether a component is active in the set of active profiles
+func profileActive(comp Component, active map[string]bool) bool {
+	if len(comp.Profiles) == 0 {
+		return true
+	}
+	for _, profile := range comp.Profiles {
+		if active[profile] {
+			return true
+		}
+	}
+	return false
+}
+
+// replacedByProfile reports whether a component implements an interface that an
+// active profiled component provides with the same qualifier
+func replacedByProfile(comp Component, replaced map[string]bool) bool {
+	for _, iface := range comp.Implements {
+		if replaced[interfaceKey(iface, comp.Qualifier)] {
+			return true
+		}
+	}
+	return false
+}
+
+// interfaceKey identifies an implemented interface by package name, type name and
+// qualifier, however the implements tag spelled its package
+func interfaceKey(iface, qualifier string) string {
+	base, name := splitTypeRef(iface)
+	return base + "." + name + "\x00" + qualifier
+}
@@ -0,0 +1,95 @@
+package wire
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseComponents_ProfileMarker(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.23\n",
+		"store/store.go": `package store
+
+type Store interface {
+	Get(key string) string
+}
+
+type Postgres struct {
+	Component struct{} ` + "`implements:\"Store\"`" + `
+}
+
+type Memory struct {
+	Component struct{} ` + "`implements:\"Store\"`" + `
+	Profile   struct{} ` + "`value:\"test, local\"`" + `
+}
+`,
+	})
+
+	components, _, err := ParseComponentsWithOptions(tmpDir, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+	}
+	if len(components) != 1 || components[0].Type != "Postgres" {
+		t.Fatalf("Expected only the unprofiled store without active profiles, got %+v", components)
+	}
+
+	components, _, err = ParseComponentsWithOptions(tmpDir, ParseOptions{Profiles: []string{"local"}})
+	if err != nil {
+		t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+	}
+	if len(components) != 1 || components[0].Type != "Memory" {
+		t.Fatalf("Expected the memory store to replace the postgres store, got %+v", components)
+	}
+	if !reflect.DeepEqual(components[0].Profiles, []string{"test", "local"}) {
+		t.Errorf("Expected the profiles test and local, got %v", components[0].Profiles)
+	}
+}
+
+func TestActivateProfiles(t *testing.T) {
+	components := []Component{
+		{Type: "EmailSender", Package: "example.com/app/message", Qualifier: "email", Implements: []string{"example.com/app/message/Sender"}},
+		{Type: "SmsSender", Package: "example.com/app/message", Qualifier: "sms", Implements: []string{"example.com/app/message/Sender"}},
+		{Type: "Service", Package: "example.com/app/service"},
+		{Type: "FakeEmailSender", Package: "example.com/app/mocks", Qualifier: "email", Implements: []string{"example.com/app/message.Sender"}, Profiles: []string{"test"}},
+		{Type: "Seeder", Package: "example.com/app/dev", Profiles: []string{"dev"}},
+	}
+
+	tests := []struct {
+		profiles []string
+		expected []string
+	}{
+		{nil, []string{"EmailSender", "Service", "SmsSender"}},
+		// The fake replaces the email sender only, the interface is matched however it is spelled
+		{[]string{"test"}, []string{"FakeEmailSender", "Service", "SmsSender"}},
+		{[]string{"test", "dev"}, []string{"FakeEmailSender", "Seeder", "Service", "SmsSender"}},
+	}
+	for _, tt := range tests {
+		var types []string
+		for _, comp := range ActivateProfiles(components, tt.profiles) {
+			types = append(types, comp.Type)
+		}
+		sort.Strings(types)
+		if !reflect.DeepEqual(types, tt.expected) {
+			t.Errorf("ActivateProfiles(%v) = %v, expected %v", tt.profiles, types, tt.expected)
+		}
+	}
+}
+
+func TestGenerator_ForOutput(t *testing.T) {
+	components := []Component{{Name: "Config", Type: "Config", Package: "example.com/app/config"}}
+	code, err := NewGenerator(components).ForOutput("/src/app", "/src/app/internal/testwire").ForProfiles([]string{"test"}).GenerateCode()
+	if err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	for _, want := range []string{
+		"iocgen --dir=../.. --output=internal/testwire/wire_gen.go --profile=test\n",
+		"\npackage testwire\n",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Expected generated code to contain %q:\n%s", want, code)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package wire
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// maxDependencyPaths bounds the number of paths returned by FindPaths so that
+// densely connected graphs cannot explode the result
+const maxDependencyPaths = 1000
+
+// maxPathSearchSteps bounds the number of edges FindPaths follows, so that a
+// query on a densely connected graph returns the paths found so far instead of
+// running for exponential time
+const maxPathSearchSteps = 1000000
+
+// DependencyPath is a chain of resolved dependency edges from one component to another
+type DependencyPath struct {
+	Steps []GraphEdge
+}
+
+// String renders the path as "A -Field-> B -Field[qualifier]-> C"
+func (p DependencyPath) String() string {
+	if len(p.Steps) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(p.Steps[0].From)
+	for _, step := range p.Steps {
+		fmt.Fprintf(&b, " -%s-> %s", edgeLabel(step), step.To)
+	}
+	return b.String()
+}
+
+// RelatedComponent is a component found by a dependency query together with its distance
+type RelatedComponent struct {
+	Component Component
+	Distance  int      // Number of edges from the queried component
+	Via       []string // Fields through which the component is reached at the first hop
+}
+
+// ImpactReason explains why a component is affected by a set of changed files
+type ImpactReason string
+
+// Reasons reported by ImpactedComponents
+const (
+	ImpactDefinedInFile  ImpactReason = "defined in changed file"
+	ImpactPackageChanged ImpactReason = "package contains changed file"
+	ImpactDependsOn      ImpactReason = "depends on affected component"
+)
+
+// ImpactedComponent is a component that must be re-tested after a change
+type ImpactedComponent struct {
+	Component Component
+	Reason    ImpactReason
+	Cause     string // Changed file or affected dependency that caused the impact
+}
+
+// resolveRef returns the keys of all components named by ref. A reference may name
+// a component (see matchesComponentRef) or an interface, in which case every
+// component implementing the interface is returned, matched like dependencies are
+func (a *DependencyAnalyzer) resolveRef(ref string) []string {
+	var keys []string
+	refBase, refName := splitTypeRef(ref)
+	for _, comp := range a.components {
+		if matchesComponentRef(comp, ref) || slices.Contains(comp.Implements, ref) ||
+			(strings.Contains(ref, ".") && implementsInterface(comp, refBase, refName)) {
+			keys = append(keys, componentKey(comp))
+		}
+	}
+	return keys
+}
+
+// resolvedEdges returns the edges of the dependency graph between components,
+// indexed by dependent and by dependency, so that queries agree with BuildGraph
+func (a *DependencyAnalyzer) resolvedEdges() (map[string][]GraphEdge, map[string][]GraphEdge) {
+	// Building the whole graph cannot fail, only unknown roots are errors
+	graph, _ := a.BuildGraph(GraphOptions{})
+	missing := make(map[string]bool)
+	for _, node := range graph.Nodes {
+		missing[node.ID] = node.Missing
+	}
+
+	forward := make(map[string][]GraphEdge)
+	reverse := make(map[string][]GraphEdge)
+	for _, edge := range graph.Edges {
+		if missing[edge.To] {
+			continue
+		}
+		forward[edge.From] = append(forward[edge.From], edge)
+		reverse[edge.To] = append(reverse[edge.To], edge)
+	}
+	return forward, reverse
+}
+
+// componentsByKey indexes the analyzed components by package.Type. The map is
+// shared and must not be modified
+func (a *DependencyAnalyzer) componentsByKey() map[string]Component {
+	return a.index.byKey
+}
+
+// FindPaths returns every simple dependency path from the component(s) named by
+// from to the component(s) named by to, shortest paths first. At most
+// maxDependencyPaths paths are returned, found within maxPathSearchSteps edges
+func (a *DependencyAnalyzer) FindPaths(from, to string) ([]DependencyPath, error) {
+	sources := a.resolveRef(from)
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no component matches %q", from)
+	}
+	targetKeys := a.resolveRef(to)
+	if len(targetKeys) == 0 {
+		return nil, fmt.Errorf("no component matches %q", to)
+	}
+	targets := make(map[string]bool)
+	for _, key := range targetKeys {
+		targets[key] = true
+	}
+
+	forward, reverse := a.resolvedEdges()
+
+	// Only components that can reach a target are walked, and only while a
+	// target is within the depth limit
+	distance := make(map[string]int, len(targets))
+	queue := append([]string(nil), targetKeys...)
+	for _, key := range targetKeys {
+		distance[key] = 0
+	}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, edge := range reverse[key] {
+			if _, seen := distance[edge.From]; !seen {
+				distance[edge.From] = distance[key] + 1
+				queue = append(queue, edge.From)
+			}
+		}
+	}
+
+	var paths []DependencyPath
+	onPath := make(map[string]bool)
+	var steps []GraphEdge
+	truncated := false // Whether a walk stopped at the depth limit
+	searched := 0      // Edges followed so far
+
+	// walk collects the paths of exactly depth steps
+	var walk func(key string, depth int)
+	walk = func(key string, depth int) {
+		onPath[key] = true
+		defer delete(onPath, key)
+
+		for _, edge := range forward[key] {
+			remaining, reachable := distance[edge.To]
+			if !reachable || onPath[edge.To] || len(paths) >= maxDependencyPaths || searched >= maxPathSearchSteps {
+				continue
+			}
+			if len(steps)+1+remaining > depth {
+				truncated = true
+				continue
+			}
+			searched++
+			steps = append(steps, edge)
+			if !targets[edge.To] {
+				walk(edge.To, depth)
+			} else if len(steps) == depth {
+				paths = append(paths, DependencyPath{Steps: append([]GraphEdge(nil), steps...)})
+			}
+			steps = steps[:len(steps)-1]
+		}
+	}
+
+	// Deepen the search one step at a time, so that paths are found shortest
+	// first and the bound on their number keeps the shortest ones
+	for depth := 1; len(paths) < maxDependencyPaths && searched < maxPathSearchSteps; depth++ {
+		truncated = false
+		for _, source := range sources {
+			walk(source, depth)
+		}
+		if !truncated {
+			break
+		}
+	}
+	return paths, nil
+}
+
+// FindRelated returns the components the referenced component depends on, or with
+// reverse set the components that depend on it. With transitive set the whole
+// closure is returned instead of only direct neighbours
+func (a *DependencyAnalyzer) FindRelated(ref string, reverse, transitive bool) ([]RelatedComponent, error) {
+	starts := a.resolveRef(ref)
+	if len(starts) == 0 {
+		return nil, fmt.Errorf("no component matches %q", ref)
+	}
+
+	forward, backward := a.resolvedEdges()
+	edges := forward
+	if reverse {
+		edges = backward
+	}
+	index := a.componentsByKey()
+
+	distance := make(map[string]int)
+	via := make(map[string][]string)
+	queue := append([]string(nil), starts...)
+	for _, key := range starts {
+		distance[key] = 0
+	}
+
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		if distance[key] > 0 && !transitive {
+			continue
+		}
+		for _, edge := range edges[key] {
+			next := edge.To
+			if reverse {
+				next = edge.From
+			}
+			if distance[key] == 0 {
+				via[next] = appendUnique(via[next], edgeLabel(edge))
+			}
+			if _, seen := distance[next]; seen {
+				continue
+			}
+			distance[next] = distance[key] + 1
+			queue = append(queue, next)
+		}
+	}
+
+	var related []RelatedComponent
+	for key, dist := range distance {
+		if dist == 0 {
+			continue
+		}
+		related = append(related, RelatedComponent{Component: index[key], Distance: dist, Via: via[key]})
+	}
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Distance != related[j].Distance {
+			return related[i].Distance < related[j].Distance
+		}
+		return componentKey(related[i].Component) < componentKey(related[j].Component)
+	})
+	return related, nil
+}
+
+// ImpactedComponents maps changed source files to the components that must be
+// re-tested: components defined in those files, components sharing a package
+// directory with them, and everything that transitively depends on either
+func (a *DependencyAnalyzer) ImpactedComponents(files []string) []ImpactedComponent {
+	changedFiles := make(map[string]bool)
+	changedDirs := make(map[string]string)
+	for _, file := range files {
+		file = filepath.Clean(file)
+		changedFiles[file] = true
+		changedDirs[filepath.Dir(file)] = file
+	}
+
+	impacted := make(map[string]ImpactedComponent)
+	var queue []string
+	for _, comp := range a.components {
+		source := filepath.Clean(comp.SourceFile)
+		key := componentKey(comp)
+		if changedFiles[source] {
+			impacted[key] = ImpactedComponent{Component: comp, Reason: ImpactDefinedInFile, Cause: source}
+			queue = append(queue, key)
+		} else if file, ok := changedDirs[filepath.Dir(source)]; ok {
+			impacted[key] = ImpactedComponent{Component: comp, Reason: ImpactPackageChanged, Cause: file}
+			queue = append(queue, key)
+		}
+	}
+
+	_, reverse := a.resolvedEdges()
+	index := a.componentsByKey()
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, edge := range reverse[key] {
+			if _, seen := impacted[edge.From]; seen {
+				continue
+			}
+			impacted[edge.From] = ImpactedComponent{Component: index[edge.From], Reason: ImpactDependsOn, Cause: key}
+			queue = append(queue, edge.From)
+		}
+	}
+
+	var result []ImpactedComponent
+	for _, imp := range impacted {
+		result = append(result, imp)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return componentKey(result[i].Component) < componentKey(result[j].Component)
+	})
+	return result
+}
+
+// appendUnique appends s to slice unless it is already present
+func appendUnique(slice []string, s string) []string {
+	for _, existing := range slice {
+		if existing == s {
+			return slice
+		}
+	}
+	return append(slice, s)
+}
@@ -0,0 +1,214 @@
+package wire
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func queryTestComponents() []Component {
+	return []Component{
+		{
+			Name:       "ConsoleLogger",
+			Type:       "ConsoleLogger",
+			Package:    "example.com/app/logger",
+			SourceFile: "/app/logger/console_logger.go",
+			Implements: []string{"example.com/app/logger.Logger"},
+		},
+		{
+			Name:       "UserRepository",
+			Type:       "UserRepository",
+			Package:    "example.com/app/repository",
+			SourceFile: "/app/repository/user_repository.go",
+			Dependencies: []Dependency{
+				{FieldName: "Logger", Type: "logger.Logger"},
+			},
+		},
+		{
+			Name:       "UserService",
+			Type:       "UserService",
+			Package:    "example.com/app/service",
+			SourceFile: "/app/service/user_service.go",
+			Dependencies: []Dependency{
+				{FieldName: "Logger", Type: "logger.Logger"},
+				{FieldName: "Repo", Type: "repository.UserRepository"},
+			},
+		},
+		{
+			Name:       "UserHandler",
+			Type:       "UserHandler",
+			Package:    "example.com/app/handler",
+			SourceFile: "/app/handler/user_handler.go",
+			Dependencies: []Dependency{
+				{FieldName: "Users", Type: "service.UserService"},
+			},
+		},
+	}
+}
+
+func TestAnalyzer_FindPaths(t *testing.T) {
+	analyzer := NewAnalyzer(queryTestComponents())
+
+	paths, err := analyzer.FindPaths("handler.UserHandler", "logger.ConsoleLogger")
+	if err != nil {
+		t.Fatalf("FindPaths failed: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("Expected 2 paths, got %d", len(paths))
+	}
+	if len(paths[0].Steps) != 2 || len(paths[1].Steps) != 3 {
+		t.Errorf("Expected paths ordered by length, got %d and %d steps", len(paths[0].Steps), len(paths[1].Steps))
+	}
+	if !strings.Contains(paths[1].String(), "-Repo-> example.com/app/repository.UserRepository") {
+		t.Errorf("Unexpected path rendering: %s", paths[1].String())
+	}
+
+	// Interfaces can be used as the target of a query
+	paths, err = analyzer.FindPaths("UserService", "logger.Logger")
+	if err != nil {
+		t.Fatalf("FindPaths failed: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Errorf("Expected 2 paths to the Logger implementation, got %d", len(paths))
+	}
+
+	if _, err := analyzer.FindPaths("Unknown", "UserService"); err == nil {
+		t.Error("Expected error for unknown component")
+	}
+}
+
+func TestAnalyzer_FindPathsBounded(t *testing.T) {
+	// Four layers of six components give more long paths than the bound,
+	// and the app also depends on the target directly through its last field
+	components := []Component{{Name: "Target", Type: "Target", Package: "example.com/app/target"}}
+	next := []Dependency{{FieldName: "Target", Type: "target.Target"}}
+	for layer := 4; layer >= 1; layer-- {
+		var deps []Dependency
+		for i := 0; i < 6; i++ {
+			typ := fmt.Sprintf("L%dC%d", layer, i)
+			components = append(components, Component{Name: typ, Type: typ, Package: "example.com/app/layer", Dependencies: next})
+			deps = append(deps, Dependency{FieldName: typ, Type: "layer." + typ})
+		}
+		next = deps
+	}
+	components = append(components, Component{Name: "App", Type: "App", Package: "example.com/app/app",
+		Dependencies: append(next, Dependency{FieldName: "Direct", Type: "target.Target"})})
+
+	paths, err := NewAnalyzer(components).FindPaths("app.App", "target.Target")
+	if err != nil {
+		t.Fatalf("FindPaths failed: %v", err)
+	}
+	if len(paths) != maxDependencyPaths {
+		t.Fatalf("Expected %d paths, got %d", maxDependencyPaths, len(paths))
+	}
+	if len(paths[0].Steps) != 1 || paths[0].Steps[0].FieldName != "Direct" {
+		t.Errorf("Expected the direct path first, got %s", paths[0])
+	}
+}
+
+func TestAnalyzer_FindPathsPrunesUnreachable(t *testing.T) {
+	// Twelve fully connected layers of eight components have 8^11 simple paths,
+	// none of which leads to the target
+	components := []Component{
+		{Name: "Target", Type: "Target", Package: "example.com/app/target"},
+		{Name: "Chain", Type: "Chain", Package: "example.com/app/chain", Dependencies: []Dependency{{FieldName: "Target", Type: "target.Target"}}},
+	}
+	var next []Dependency
+	for layer := 12; layer >= 1; layer-- {
+		var deps []Dependency
+		for i := 0; i < 8; i++ {
+			typ := fmt.Sprintf("L%dC%d", layer, i)
+			components = append(components, Component{Name: typ, Type: typ, Package: "example.com/app/layer", Dependencies: next})
+			deps = append(deps, Dependency{FieldName: typ, Type: "layer." + typ})
+		}
+		next = deps
+	}
+	components = append(components, Component{Name: "App", Type: "App", Package: "example.com/app/app",
+		Dependencies: append(next, Dependency{FieldName: "Chain", Type: "chain.Chain"})})
+	analyzer := NewAnalyzer(components)
+
+	paths, err := analyzer.FindPaths("app.App", "target.Target")
+	if err != nil {
+		t.Fatalf("FindPaths failed: %v", err)
+	}
+	if len(paths) != 1 || len(paths[0].Steps) != 2 {
+		t.Errorf("Expected the path through the chain, got %v", paths)
+	}
+	if paths, _ := analyzer.FindPaths("L1C0", "chain.Chain"); len(paths) != 0 {
+		t.Errorf("Expected no path from the layers, got %v", paths)
+	}
+}
+
+func TestAnalyzer_FindRelated(t *testing.T) {
+	analyzer := NewAnalyzer(queryTestComponents())
+
+	direct, err := analyzer.FindRelated("logger.Logger", true, false)
+	if err != nil {
+		t.Fatalf("FindRelated failed: %v", err)
+	}
+	if len(direct) != 2 {
+		t.Errorf("Expected 2 direct dependents, got %d", len(direct))
+	}
+
+	transitive, err := analyzer.FindRelated("logger.Logger", true, true)
+	if err != nil {
+		t.Fatalf("FindRelated failed: %v", err)
+	}
+	if len(transitive) != 3 {
+		t.Errorf("Expected 3 transitive dependents, got %d", len(transitive))
+	}
+	last := transitive[len(transitive)-1]
+	if last.Component.Type != "UserHandler" || last.Distance != 2 {
+		t.Errorf("Expected UserHandler at distance 2, got %s at %d", last.Component.Type, last.Distance)
+	}
+
+	deps, err := analyzer.FindRelated("UserService", false, false)
+	if err != nil {
+		t.Fatalf("FindRelated failed: %v", err)
+	}
+	if len(deps) != 2 {
+		t.Errorf("Expected 2 dependencies of UserService, got %d", len(deps))
+	}
+
+	// Interfaces declared without a package match like they do for dependencies
+	unqualified := NewAnalyzer([]Component{
+		{Name: "EmailSender", Type: "EmailSender", Package: "example.com/app/message", Implements: []string{"Sender"}},
+		{Name: "Notifier", Type: "Notifier", Package: "example.com/app/notify", Dependencies: []Dependency{{FieldName: "Sender", Type: "message.Sender"}}},
+	})
+	for _, ref := range []string{"message.Sender", "Sender"} {
+		dependents, err := unqualified.FindRelated(ref, true, false)
+		if err != nil || len(dependents) != 1 {
+			t.Errorf("Expected %s to resolve to the EmailSender with one dependent, got %v, %v", ref, dependents, err)
+		}
+	}
+}
+
+func TestAnalyzer_ImpactedComponents(t *testing.T) {
+	analyzer := NewAnalyzer(queryTestComponents())
+
+	impacted := analyzer.ImpactedComponents([]string{"/app/repository/user_repository.go"})
+	reasons := make(map[string]ImpactReason)
+	for _, imp := range impacted {
+		reasons[imp.Component.Type] = imp.Reason
+	}
+
+	expected := map[string]ImpactReason{
+		"UserRepository": ImpactDefinedInFile,
+		"UserService":    ImpactDependsOn,
+		"UserHandler":    ImpactDependsOn,
+	}
+	if len(reasons) != len(expected) {
+		t.Errorf("Expected %d impacted components, got %d: %v", len(expected), len(reasons), reasons)
+	}
+	for typ, reason := range expected {
+		if reasons[typ] != reason {
+			t.Errorf("Expected %s to be impacted with reason %q, got %q", typ, reason, reasons[typ])
+		}
+	}
+
+	// A file without components still affects its package
+	impacted = analyzer.ImpactedComponents([]string{"/app/logger/logger.go"})
+	if len(impacted) != 4 {
+		t.Errorf("Expected all 4 components to be impacted by a logger change, got %d", len(impacted))
+	}
+}
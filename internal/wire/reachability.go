@@ -0,0 +1,172 @@
+package wire
+
+import (
+	"fmt"
+	"sort"
+)
+
+// UnreachableSubgraph is a group of connected components that no entry point reaches
+type UnreachableSubgraph struct {
+	Components []Component
+	Packages   []string // Packages the components are defined in
+}
+
+// SetRoots adds application roots to the ones marked with EntryPoint. Every
+// reference must name at least one component or interface
+func (a *DependencyAnalyzer) SetRoots(refs []string) error {
+	for _, ref := range refs {
+		keys := a.resolveRef(ref)
+		if len(keys) == 0 {
+			return fmt.Errorf("no component matches root %q", ref)
+		}
+		a.roots = append(a.roots, keys...)
+	}
+	return nil
+}
+
+// rootKeys returns the keys of all entry points and configured roots
+func (a *DependencyAnalyzer) rootKeys() []string {
+	var keys []string
+	for _, comp := range a.components {
+		if comp.EntryPoint {
+			keys = appendUnique(keys, componentKey(comp))
+		}
+	}
+	for _, key := range a.roots {
+		keys = appendUnique(keys, key)
+	}
+	return keys
+}
+
+// HasRoots reports whether unused component detection is based on reachability from roots
+func (a *DependencyAnalyzer) HasRoots() bool {
+	return len(a.rootKeys()) > 0
+}
+
+// EntryPoints returns the components unused detection starts from
+func (a *DependencyAnalyzer) EntryPoints() []Component {
+	index := a.componentsByKey()
+	var entryPoints []Component
+	for _, key := range a.rootKeys() {
+		entryPoints = append(entryPoints, index[key])
+	}
+	return entryPoints
+}
+
+// ReachableComponents returns the keys of every component reachable from the roots,
+// including the roots themselves
+func (a *DependencyAnalyzer) ReachableComponents() map[string]bool {
+	return a.reachableFrom(a.rootKeys())
+}
+
+// reachableFrom returns the keys of the given components and every component they
+// transitively depend on
+func (a *DependencyAnalyzer) reachableFrom(keys []string) map[string]bool {
+	forward, _ := a.resolvedEdges()
+	reachable := make(map[string]bool)
+	queue := append([]string(nil), keys...)
+	for _, key := range queue {
+		reachable[key] = true
+	}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, edge := range forward[key] {
+			if !reachable[edge.To] {
+				reachable[edge.To] = true
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	return reachable
+}
+
+// findUnreachableComponents returns the components no root reaches, in declaration order
+func (a *DependencyAnalyzer) findUnreachableComponents() []Component {
+	reachable := a.ReachableComponents()
+	var unreachable []Component
+	for _, comp := range a.components {
+		if !reachable[componentKey(comp)] {
+			unreachable = append(unreachable, comp)
+		}
+	}
+	return unreachable
+}
+
+// FindUnreachableSubgraphs groups unreachable components into connected subgraphs,
+// so that whole features that are no longer wired into the application stand out
+func (a *DependencyAnalyzer) FindUnreachableSubgraphs(unreachable []Component) []UnreachableSubgraph {
+	inSet := make(map[string]bool)
+	for _, comp := range unreachable {
+		inSet[componentKey(comp)] = true
+	}
+
+	// Dependencies are followed in both directions within the unreachable set
+	forward, reverse := a.resolvedEdges()
+	neighbours := func(key string) []string {
+		var keys []string
+		for _, edge := range forward[key] {
+			keys = append(keys, edge.To)
+		}
+		for _, edge := range reverse[key] {
+			keys = append(keys, edge.From)
+		}
+		return keys
+	}
+
+	index := a.componentsByKey()
+	seen := make(map[string]bool)
+	var subgraphs []UnreachableSubgraph
+	for _, comp := range unreachable {
+		start := componentKey(comp)
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+
+		var subgraph UnreachableSubgraph
+		queue := []string{start}
+		for len(queue) > 0 {
+			key := queue[0]
+			queue = queue[1:]
+			member := index[key]
+			subgraph.Components = append(subgraph.Components, member)
+			subgraph.Packages = appendUnique(subgraph.Packages, member.Package)
+			for _, next := range neighbours(key) {
+				if inSet[next] && !seen[next] {
+					seen[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+
+		sort.Slice(subgraph.Components, func(i, j int) bool {
+			return componentKey(subgraph.Components[i]) < componentKey(subgraph.Components[j])
+		})
+		sort.Strings(subgraph.Packages)
+		subgraphs = append(subgraphs, subgraph)
+	}
+
+	sort.SliceStable(subgraphs, func(i, j int) bool {
+		return len(subgraphs[i].Components) > len(subgraphs[j].Components)
+	})
+	return subgraphs
+}
+
+// FindRemovablePackages returns the packages whose components are all unreachable.
+// They are candidates for removal, provided nothing outside the container uses them
+func (a *DependencyAnalyzer) FindRemovablePackages(unreachable []Component) []string {
+	unreachableCount := make(map[string]int)
+	for _, comp := range unreachable {
+		unreachableCount[comp.Package]++
+	}
+
+	var packages []string
+	for pkg, components := range a.groupComponentsByPackage() {
+		if unreachableCount[pkg] == len(components) {
+			packages = append(packages, pkg)
+		}
+	}
+	sort.Strings(packages)
+	return packages
+}
@@ -0,0 +1,116 @@
+package wire
+
+import (
+	"testing"
+)
+
+func reachabilityTestComponents() []Component {
+	return []Component{
+		{
+			Name:       "Server",
+			Type:       "Server",
+			Package:    "example.com/app/server",
+			EntryPoint: true,
+			Dependencies: []Dependency{
+				{FieldName: "Users", Type: "service.UserService"},
+			},
+		},
+		{
+			Name:    "UserService",
+			Type:    "UserService",
+			Package: "example.com/app/service",
+			Dependencies: []Dependency{
+				{FieldName: "Logger", Type: "logger.Logger"},
+			},
+		},
+		{
+			Name:       "ConsoleLogger",
+			Type:       "ConsoleLogger",
+			Package:    "example.com/app/logger",
+			Implements: []string{"example.com/app/logger.Logger"},
+		},
+		// A feature that is no longer wired into the server
+		{
+			Name:    "ReportJob",
+			Type:    "ReportJob",
+			Package: "example.com/app/reports",
+			Dependencies: []Dependency{
+				{FieldName: "Exporter", Type: "reports.Exporter"},
+				{FieldName: "Logger", Type: "logger.Logger"},
+			},
+		},
+		{
+			Name:    "Exporter",
+			Type:    "Exporter",
+			Package: "example.com/app/reports",
+		},
+		{
+			Name:    "LegacyCache",
+			Type:    "LegacyCache",
+			Package: "example.com/app/service",
+		},
+	}
+}
+
+func TestAnalyzer_FindUnusedComponentsFromEntryPoints(t *testing.T) {
+	result := NewAnalyzer(reachabilityTestComponents()).PerformComprehensiveAnalysis()
+
+	unused := make(map[string]bool)
+	for _, comp := range result.UnusedComponents {
+		unused[comp.Type] = true
+	}
+	for _, name := range []string{"ReportJob", "Exporter", "LegacyCache"} {
+		if !unused[name] {
+			t.Errorf("Expected %s to be unreachable", name)
+		}
+	}
+	// The entry point itself has no dependents but must not be reported
+	if unused["Server"] || len(result.UnusedComponents) != 3 {
+		t.Errorf("Expected exactly 3 unreachable components, got %v", unused)
+	}
+
+	if len(result.UnreachableSubgraphs) != 2 {
+		t.Fatalf("Expected 2 unreachable subgraphs, got %d", len(result.UnreachableSubgraphs))
+	}
+	largest := result.UnreachableSubgraphs[0]
+	if len(largest.Components) != 2 || len(largest.Packages) != 1 || largest.Packages[0] != "example.com/app/reports" {
+		t.Errorf("Expected the reports feature as one subgraph, got %+v", largest)
+	}
+
+	if len(result.RemovablePackages) != 1 || result.RemovablePackages[0] != "example.com/app/reports" {
+		t.Errorf("Expected only the reports package to be removable, got %v", result.RemovablePackages)
+	}
+}
+
+func TestAnalyzer_SetRoots(t *testing.T) {
+	components := reachabilityTestComponents()
+	components[0].EntryPoint = false
+
+	analyzer := NewAnalyzer(components)
+	if err := analyzer.SetRoots([]string{"server.Server", "ReportJob"}); err != nil {
+		t.Fatalf("SetRoots failed: %v", err)
+	}
+
+	unused := analyzer.FindUnusedComponents()
+	if len(unused) != 1 || unused[0].Type != "LegacyCache" {
+		t.Errorf("Expected only LegacyCache to be unreachable, got %v", unused)
+	}
+
+	if err := analyzer.SetRoots([]string{"Missing"}); err == nil {
+		t.Error("Expected error for unknown root")
+	}
+}
+
+func TestAnalyzer_FindUnusedComponentsWithoutRoots(t *testing.T) {
+	components := reachabilityTestComponents()
+	components[0].EntryPoint = false
+
+	result := NewAnalyzer(components).PerformComprehensiveAnalysis()
+	if len(result.EntryPoints) != 0 || result.UnreachableSubgraphs != nil {
+		t.Error("Expected dependency based detection without entry points")
+	}
+	// Server and ReportJob have no dependents, LegacyCache is never used
+	if len(result.UnusedComponents) != 3 {
+		t.Errorf("Expected 3 unused components, got %d", len(result.UnusedComponents))
+	}
+}
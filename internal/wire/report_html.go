@@ -0,0 +1,452 @@
+package wire
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+)
+
+// htmlReportData is the view model rendered into the HTML analysis report
+type htmlReportData struct {
+	GeneratedAt string
+	Result      *AnalysisResult
+	Packages    []htmlPackageRow
+	Unused      []htmlComponentRef
+	Orphaned    []htmlComponentRef
+	GraphJSON   template.JS // Embedded graph data consumed by the report script
+}
+
+// htmlPackageRow summarizes a single package in the overview table
+type htmlPackageRow struct {
+	Package string
+	Count   int
+}
+
+// htmlComponentRef is a component reference with its source location
+type htmlComponentRef struct {
+	Key    string
+	Source string
+}
+
+// htmlGraph is the JSON payload embedded into the report
+type htmlGraph struct {
+	Nodes []htmlNode `json:"nodes"`
+	Edges []htmlEdge `json:"edges"`
+}
+
+// htmlNode holds the panel details and layout position of one graph node
+type htmlNode struct {
+	ID            string   `json:"id"`
+	Label         string   `json:"label"`
+	Package       string   `json:"package"`
+	Qualifier     string   `json:"qualifier,omitempty"`
+	Source        string   `json:"source,omitempty"`
+	Implements    []string `json:"implements,omitempty"`
+	Constructor   string   `json:"constructor,omitempty"`
+	PostConstruct bool     `json:"postConstruct,omitempty"`
+	PreDestroy    bool     `json:"preDestroy,omitempty"`
+	Depth         int      `json:"depth"`
+	FanIn         int      `json:"fanIn"`
+	FanOut        int      `json:"fanOut"`
+	Missing       bool     `json:"missing,omitempty"`
+	InCycle       bool     `json:"cycle,omitempty"`
+	X             int      `json:"x"`
+	Y             int      `json:"y"`
+}
+
+// htmlEdge is a dependency edge in the embedded graph
+type htmlEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Label   string `json:"label"`
+	InCycle bool   `json:"cycle,omitempty"`
+	Missing bool   `json:"missing,omitempty"`
+}
+
+// Layout constants for the embedded graph
+const (
+	htmlColumnWidth = 260
+	htmlRowHeight   = 70
+)
+
+// WriteHTMLReport writes a self-contained, offline HTML analysis report with an
+// interactive dependency graph, per-component details and the analysis findings
+func (a *DependencyAnalyzer) WriteHTMLReport(w io.Writer) error {
+	result := a.PerformComprehensiveAnalysis()
+
+	graph, err := a.BuildGraph(GraphOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+
+	payload, err := json.Marshal(buildHTMLGraph(graph, result.DependencyDepth))
+	if err != nil {
+		return fmt.Errorf("failed to encode graph data: %w", err)
+	}
+
+	data := htmlReportData{
+		GeneratedAt: time.Now().Format(time.RFC1123),
+		Result:      result,
+		GraphJSON:   template.JS(payload),
+	}
+	for pkg, comps := range result.ComponentsByPackage {
+		data.Packages = append(data.Packages, htmlPackageRow{Package: pkg, Count: len(comps)})
+	}
+	sort.Slice(data.Packages, func(i, j int) bool {
+		return data.Packages[i].Package < data.Packages[j].Package
+	})
+	for _, comp := range result.UnusedComponents {
+		data.Unused = append(data.Unused, htmlComponentRef{Key: componentKey(comp), Source: fmt.Sprintf("%s:%d", comp.SourceFile, comp.LineNumber)})
+	}
+	for _, comp := range result.OrphanedComponents {
+		data.Orphaned = append(data.Orphaned, htmlComponentRef{Key: componentKey(comp), Source: fmt.Sprintf("%s:%d", comp.SourceFile, comp.LineNumber)})
+	}
+
+	funcMap := template.FuncMap{
+		"inc": func(i int) int { return i + 1 },
+	}
+	tmpl, err := template.New("report").Funcs(funcMap).Parse(htmlReportTemplate)
+	if err != nil {
+		return fmt.Errorf("template parsing failed: %w", err)
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("template execution failed: %w", err)
+	}
+	return nil
+}
+
+// buildHTMLGraph converts the dependency graph into the embedded payload and
+// assigns a layered layout: dependents on the left, their dependencies to the right
+func buildHTMLGraph(graph *DependencyGraph, depths map[string]int) htmlGraph {
+	var out htmlGraph
+	missing := make(map[string]bool)
+	for _, node := range graph.Nodes {
+		missing[node.ID] = node.Missing
+	}
+	fanIn := make(map[string]map[string]bool)
+	fanOut := make(map[string]map[string]bool)
+	for _, edge := range graph.Edges {
+		if fanOut[edge.From] == nil {
+			fanOut[edge.From] = make(map[string]bool)
+		}
+		if fanIn[edge.To] == nil {
+			fanIn[edge.To] = make(map[string]bool)
+		}
+		fanOut[edge.From][edge.To] = true
+		fanIn[edge.To][edge.From] = true
+
+		out.Edges = append(out.Edges, htmlEdge{
+			From:    edge.From,
+			To:      edge.To,
+			Label:   edgeLabel(edge),
+			InCycle: edge.InCycle,
+			Missing: missing[edge.To],
+		})
+	}
+
+	maxDepth := 0
+	for _, depth := range depths {
+		maxDepth = max(maxDepth, depth)
+	}
+
+	rows := make(map[int]int)
+	for _, node := range graph.Nodes {
+		n := htmlNode{
+			ID:      node.ID,
+			Label:   nodeLabel(node),
+			Package: node.Package,
+			Missing: node.Missing,
+			InCycle: node.InCycle,
+			FanIn:   len(fanIn[node.ID]),
+			FanOut:  len(fanOut[node.ID]),
+		}
+
+		column := maxDepth + 1 // Missing dependencies go to the far right
+		if comp := node.Component; comp != nil {
+			n.Qualifier = comp.Qualifier
+			n.Source = fmt.Sprintf("%s:%d", comp.SourceFile, comp.LineNumber)
+			n.Implements = comp.Implements
+			n.Constructor = comp.Constructor
+			n.PostConstruct = comp.PostConstruct
+			n.PreDestroy = comp.PreDestroy
+			n.Depth = depths[node.ID]
+			column = maxDepth - n.Depth
+			if n.Depth < 0 {
+				column = 0 // Cyclic components have no defined depth
+			}
+		}
+
+		n.X = column * htmlColumnWidth
+		n.Y = rows[column] * htmlRowHeight
+		rows[column]++
+		out.Nodes = append(out.Nodes, n)
+	}
+
+	return out
+}
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Go IoC Component Analysis Report</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #222; background: #fafafa; }
+  header { background: #00add8; color: #fff; padding: 16px 24px; }
+  header h1 { margin: 0; font-size: 22px; }
+  header p { margin: 4px 0 0; opacity: .85; font-size: 13px; }
+  main { padding: 16px 24px; }
+  section { background: #fff; border: 1px solid #e2e2e2; border-radius: 6px; padding: 12px 16px; margin-bottom: 16px; }
+  h2 { font-size: 17px; margin: 4px 0 12px; }
+  table { border-collapse: collapse; width: 100%; font-size: 13px; }
+  th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #eee; vertical-align: top; }
+  th { background: #f4f4f4; }
+  code { font-family: Menlo, Consolas, monospace; font-size: 12px; }
+  .stats { display: flex; gap: 16px; flex-wrap: wrap; }
+  .stat { border: 1px solid #e2e2e2; border-radius: 6px; padding: 8px 16px; min-width: 120px; }
+  .stat b { display: block; font-size: 22px; }
+  .ok { color: #2a8a2a; }
+  .ERROR { color: #c62828; font-weight: bold; }
+  .WARNING { color: #d68400; font-weight: bold; }
+  #graph-wrap { display: flex; gap: 16px; }
+  #graph { flex: 1; height: 560px; border: 1px solid #ddd; border-radius: 4px; background: #fff; cursor: grab; }
+  #panel { width: 340px; font-size: 13px; overflow-y: auto; max-height: 560px; }
+  #panel dt { font-weight: bold; margin-top: 8px; }
+  #panel dd { margin: 2px 0 0; word-break: break-all; }
+  .node rect { fill: #e8f6fb; stroke: #00add8; stroke-width: 1.5; rx: 4; }
+  .node.cycle rect { fill: #fdecea; stroke: #c62828; stroke-width: 2.5; }
+  .node.missing rect { fill: #fff7e6; stroke: #f90; stroke-dasharray: 5 3; }
+  .node.selected rect { stroke-width: 3.5; }
+  .node text { font-size: 12px; pointer-events: none; }
+  .node { cursor: pointer; }
+  .edge { fill: none; stroke: #999; stroke-width: 1.2; }
+  .edge.cycle { stroke: #c62828; stroke-width: 2; }
+  .edge.missing { stroke: #f90; stroke-dasharray: 5 3; }
+  .edge.dim, .node.dim { opacity: .15; }
+  .hint { font-size: 12px; color: #777; }
+</style>
+</head>
+<body>
+<header>
+  <h1>📊 Go IoC Component Analysis Report</h1>
+  <p>Generated {{.GeneratedAt}}</p>
+</header>
+<main>
+<section>
+  <h2>📋 Overview</h2>
+  <div class="stats">
+    <div class="stat"><b>{{.Result.TotalComponents}}</b>Components</div>
+    <div class="stat"><b>{{.Result.TotalDependencies}}</b>Dependencies</div>
+    <div class="stat"><b>{{len .Packages}}</b>Packages</div>
+    <div class="stat"><b>{{.Result.InterfaceAnalysis.TotalInterfaces}}</b>Interfaces</div>
+    <div class="stat"><b>{{len .Result.CircularDependencies}}</b>Cycles</div>
+  </div>
+</section>
+
+<section>
+  <h2>🕸️ Dependency Graph</h2>
+  <p class="hint">Scroll to zoom, drag to pan, click a component for details. Red marks cycles, dashed orange marks unsatisfied dependencies.</p>
+  <div id="graph-wrap">
+    <svg id="graph" xmlns="http://www.w3.org/2000/svg"></svg>
+    <div id="panel"><p class="hint">Select a component to see its details.</p></div>
+  </div>
+</section>
+
+<section>
+  <h2>📦 Components by Package</h2>
+  <table>
+    <tr><th>Package</th><th>Components</th></tr>
+    {{- range .Packages}}
+    <tr><td><code>{{.Package}}</code></td><td>{{.Count}}</td></tr>
+    {{- end}}
+  </table>
+</section>
+
+<section>
+  <h2>🔄 Circular Dependencies</h2>
+  {{- if .Result.CircularDependencies}}
+  <table>
+    <tr><th>#</th><th>Path</th></tr>
+    {{- range $i, $c := .Result.CircularDependencies}}
+    <tr><td>{{inc $i}}</td><td>{{range $j, $p := $c.Path}}{{if $j}} → {{end}}<code>{{$p}}</code>{{end}}</td></tr>
+    {{- end}}
+  </table>
+  {{- else}}
+  <p class="ok">✅ No circular dependencies found</p>
+  {{- end}}
+</section>
+
+<section>
+  <h2>🗑️ Unused Components</h2>
+  {{- if .Unused}}
+  <table>
+    <tr><th>Component</th><th>Source</th></tr>
+    {{- range .Unused}}
+    <tr><td><code>{{.Key}}</code></td><td><code>{{.Source}}</code></td></tr>
+    {{- end}}
+  </table>
+  {{- else}}
+  <p class="ok">✅ All components are used</p>
+  {{- end}}
+</section>
+
+<section>
+  <h2>🔗 Orphaned Components</h2>
+  {{- if .Orphaned}}
+  <table>
+    <tr><th>Component</th><th>Source</th></tr>
+    {{- range .Orphaned}}
+    <tr><td><code>{{.Key}}</code></td><td><code>{{.Source}}</code></td></tr>
+    {{- end}}
+  </table>
+  {{- else}}
+  <p class="ok">✅ All component dependencies can be satisfied</p>
+  {{- end}}
+</section>
+
+<section>
+  <h2>⚠️ Qualifier Conflicts</h2>
+  {{- if .Result.QualifierConflicts}}
+  <table>
+    <tr><th>Severity</th><th>Interface</th><th>Qualifier</th><th>Conflicting</th></tr>
+    {{- range .Result.QualifierConflicts}}
+    <tr><td class="{{.Severity}}">{{.Severity}}</td><td><code>{{.Interface}}</code></td><td><code>{{.Qualifier}}</code></td><td>{{range $j, $c := .Conflicting}}{{if $j}}<br>{{end}}<code>{{$c}}</code>{{end}}</td></tr>
+    {{- end}}
+  </table>
+  {{- else}}
+  <p class="ok">✅ No qualifier conflicts found</p>
+  {{- end}}
+</section>
+</main>
+
+<script id="graph-data" type="application/json">{{.GraphJSON}}</script>
+<script>
+(function () {
+  var data = JSON.parse(document.getElementById("graph-data").textContent);
+  var svg = document.getElementById("graph");
+  var panel = document.getElementById("panel");
+  var NS = "http://www.w3.org/2000/svg";
+  var W = 200, H = 44;
+  var byId = {};
+  data.nodes.forEach(function (n) { byId[n.id] = n; });
+
+  function el(name, attrs, parent) {
+    var e = document.createElementNS(NS, name);
+    for (var k in attrs) { e.setAttribute(k, attrs[k]); }
+    if (parent) { parent.appendChild(e); }
+    return e;
+  }
+
+  var defs = el("defs", {}, svg);
+  var marker = el("marker", { id: "arrow", viewBox: "0 0 10 10", refX: 10, refY: 5, markerWidth: 6, markerHeight: 6, orient: "auto" }, defs);
+  el("path", { d: "M0,0 L10,5 L0,10 z", fill: "#999" }, marker);
+  var root = el("g", {}, svg);
+
+  var edgeEls = (data.edges || []).map(function (e) {
+    var a = byId[e.from], b = byId[e.to];
+    var x1 = a.x + W, y1 = a.y + H / 2, x2 = b.x, y2 = b.y + H / 2;
+    if (b.x <= a.x) { x1 = a.x + W / 2; y1 = a.y + H; x2 = b.x + W / 2; y2 = b.y; }
+    var mx = (x1 + x2) / 2;
+    var cls = "edge" + (e.cycle ? " cycle" : "") + (e.missing ? " missing" : "");
+    var p = el("path", { d: "M" + x1 + "," + y1 + " C" + mx + "," + y1 + " " + mx + "," + y2 + " " + x2 + "," + y2, "class": cls, "marker-end": "url(#arrow)" }, root);
+    el("title", {}, p).textContent = e.label;
+    return { edge: e, el: p };
+  });
+
+  var nodeEls = {};
+  data.nodes.forEach(function (n) {
+    var g = el("g", { "class": "node" + (n.cycle ? " cycle" : "") + (n.missing ? " missing" : ""), transform: "translate(" + n.x + "," + n.y + ")" }, root);
+    el("rect", { width: W, height: H }, g);
+    var t = el("text", { x: 8, y: 18 }, g);
+    t.textContent = n.label.length > 30 ? n.label.slice(0, 29) + "…" : n.label;
+    var p = el("text", { x: 8, y: 34, fill: "#777" }, g);
+    var pkg = n.package.split("/").pop();
+    p.textContent = pkg || "unresolved";
+    g.addEventListener("click", function (ev) { ev.stopPropagation(); select(n.id); });
+    nodeEls[n.id] = g;
+  });
+
+  function esc(s) {
+    return String(s).replace(/[&<>"]/g, function (c) { return { "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;" }[c]; });
+  }
+
+  function row(dt, dd) { return "<dt>" + dt + "</dt><dd>" + dd + "</dd>"; }
+
+  function select(id) {
+    var n = byId[id];
+    var related = {};
+    related[id] = true;
+    edgeEls.forEach(function (x) {
+      if (x.edge.from === id) { related[x.edge.to] = true; }
+      if (x.edge.to === id) { related[x.edge.from] = true; }
+    });
+    Object.keys(nodeEls).forEach(function (k) {
+      nodeEls[k].classList.toggle("dim", !related[k]);
+      nodeEls[k].classList.toggle("selected", k === id);
+    });
+    edgeEls.forEach(function (x) { x.el.classList.toggle("dim", x.edge.from !== id && x.edge.to !== id); });
+
+    var deps = edgeEls.filter(function (x) { return x.edge.from === id; }).map(function (x) { return "<code>" + esc(x.edge.label) + "</code> → " + esc(byId[x.edge.to].label); });
+    var users = edgeEls.filter(function (x) { return x.edge.to === id; }).map(function (x) { return esc(byId[x.edge.from].label) + " (<code>" + esc(x.edge.label) + "</code>)"; });
+    var hooks = [];
+    if (n.postConstruct) { hooks.push("PostConstruct"); }
+    if (n.preDestroy) { hooks.push("PreDestroy"); }
+
+    var html = "<h2>" + esc(n.label) + "</h2><dl>";
+    html += row("ID", "<code>" + esc(n.id) + "</code>");
+    if (n.missing) {
+      html += row("Status", "<span class=\"WARNING\">Unsatisfied dependency</span>");
+    } else {
+      html += row("Source", "<code>" + esc(n.source) + "</code>");
+      html += row("Implements", (n.implements || []).map(esc).join("<br>") || "—");
+      html += row("Lifecycle hooks", hooks.join(", ") || "—");
+      html += row("Constructor", n.constructor ? "<code>" + esc(n.constructor) + "</code>" : "—");
+      html += row("Depth", n.depth < 0 ? "<span class=\"ERROR\">cyclic</span>" : n.depth);
+    }
+    html += row("Fan-in / fan-out", n.fanIn + " / " + n.fanOut);
+    html += row("Dependencies", deps.join("<br>") || "—");
+    html += row("Used by", users.join("<br>") || "—");
+    panel.innerHTML = html + "</dl>";
+  }
+
+  // Pan and zoom by manipulating the viewBox
+  var maxX = 0, maxY = 0;
+  data.nodes.forEach(function (n) { maxX = Math.max(maxX, n.x + W); maxY = Math.max(maxY, n.y + H); });
+  var view = { x: -20, y: -20, w: Math.max(maxX + 40, 400), h: Math.max(maxY + 40, 300) };
+  function apply() { svg.setAttribute("viewBox", view.x + " " + view.y + " " + view.w + " " + view.h); }
+  apply();
+
+  svg.addEventListener("wheel", function (ev) {
+    ev.preventDefault();
+    var r = svg.getBoundingClientRect();
+    var f = ev.deltaY > 0 ? 1.15 : 1 / 1.15;
+    var px = view.x + (ev.clientX - r.left) / r.width * view.w;
+    var py = view.y + (ev.clientY - r.top) / r.height * view.h;
+    view.x = px - (px - view.x) * f; view.y = py - (py - view.y) * f;
+    view.w *= f; view.h *= f;
+    apply();
+  }, { passive: false });
+
+  var drag = null;
+  svg.addEventListener("mousedown", function (ev) { drag = { x: ev.clientX, y: ev.clientY }; svg.style.cursor = "grabbing"; });
+  window.addEventListener("mouseup", function () { drag = null; svg.style.cursor = "grab"; });
+  window.addEventListener("mousemove", function (ev) {
+    if (!drag) { return; }
+    var r = svg.getBoundingClientRect();
+    view.x -= (ev.clientX - drag.x) / r.width * view.w;
+    view.y -= (ev.clientY - drag.y) / r.height * view.h;
+    drag = { x: ev.clientX, y: ev.clientY };
+    apply();
+  });
+  svg.addEventListener("click", function () {
+    Object.keys(nodeEls).forEach(function (k) { nodeEls[k].classList.remove("dim", "selected"); });
+    edgeEls.forEach(function (x) { x.el.classList.remove("dim"); });
+  });
+})();
+</script>
+</body>
+</html>
+`
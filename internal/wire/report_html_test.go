@@ -0,0 +1,63 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzer_WriteHTMLReport(t *testing.T) {
+	components := graphTestComponents()
+	components[1].SourceFile = "service/user_service.go"
+	components[1].LineNumber = 12
+	components[1].PostConstruct = true
+
+	var buf bytes.Buffer
+	if err := NewAnalyzer(components).WriteHTMLReport(&buf); err != nil {
+		t.Fatalf("WriteHTMLReport failed: %v", err)
+	}
+	report := buf.String()
+
+	for _, section := range []string{"Dependency Graph", "Circular Dependencies", "Unused Components", "Orphaned Components", "Qualifier Conflicts"} {
+		if !strings.Contains(report, section) {
+			t.Errorf("Expected report to contain section %q", section)
+		}
+	}
+
+	// The report must be usable offline
+	if strings.Contains(report, "<script src") || strings.Contains(report, "<link ") {
+		t.Error("Report must not reference external scripts or stylesheets")
+	}
+
+	// Extract and decode the embedded graph data
+	start := strings.Index(report, `<script id="graph-data" type="application/json">`)
+	if start == -1 {
+		t.Fatal("Embedded graph data not found")
+	}
+	start += len(`<script id="graph-data" type="application/json">`)
+	end := strings.Index(report[start:], "</script>")
+
+	var graph htmlGraph
+	if err := json.Unmarshal([]byte(report[start:start+end]), &graph); err != nil {
+		t.Fatalf("Embedded graph data is not valid JSON: %v", err)
+	}
+	if len(graph.Nodes) != 5 {
+		t.Errorf("Expected 5 nodes in embedded graph, got %d", len(graph.Nodes))
+	}
+
+	for _, node := range graph.Nodes {
+		if node.ID != "example.com/app/service.UserService" {
+			continue
+		}
+		if node.Source != "service/user_service.go:12" {
+			t.Errorf("Expected source location, got %q", node.Source)
+		}
+		if !node.PostConstruct {
+			t.Error("Expected PostConstruct hook in panel data")
+		}
+		if node.FanOut != 2 || node.FanIn != 0 {
+			t.Errorf("Expected fan-in 0 and fan-out 2, got %d and %d", node.FanIn, node.FanOut)
+		}
+	}
+}
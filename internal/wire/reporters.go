@@ -0,0 +1,372 @@
+package wire
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ReportFormat identifies a machine readable findings report format
+type ReportFormat string
+
+// Supported report formats
+const (
+	ReportFormatSARIF      ReportFormat = "sarif"
+	ReportFormatJUnit      ReportFormat = "junit"
+	ReportFormatCheckstyle ReportFormat = "checkstyle"
+)
+
+// ReportFormats lists every supported report format
+var ReportFormats = []ReportFormat{ReportFormatSARIF, ReportFormatJUnit, ReportFormatCheckstyle}
+
+// ParseReportFormat validates a user supplied report format name
+func ParseReportFormat(name string) (ReportFormat, error) {
+	for _, f := range ReportFormats {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+	var names []string
+	for _, f := range ReportFormats {
+		names = append(names, string(f))
+	}
+	return "", fmt.Errorf("unknown report format %q (supported: %s)", name, strings.Join(names, ", "))
+}
+
+// Reporter writes findings in a machine readable format. Suppressed findings are
+// passed separately so that formats supporting suppressions can record them
+type Reporter interface {
+	Report(w io.Writer, findings, suppressed []Finding) error
+}
+
+// NewReporter returns the reporter for a format. Source paths in the report are
+// made relative to baseDir when possible
+func NewReporter(format ReportFormat, baseDir string) (Reporter, error) {
+	switch format {
+	case ReportFormatSARIF:
+		return sarifReporter{baseDir: baseDir}, nil
+	case ReportFormatJUnit:
+		return junitReporter{baseDir: baseDir}, nil
+	case ReportFormatCheckstyle:
+		return checkstyleReporter{baseDir: baseDir}, nil
+	}
+	return nil, fmt.Errorf("unsupported report format %q", format)
+}
+
+// ruleDescriptions documents every rule ID in reports
+var ruleDescriptions = map[string]string{
+	RuleCircularDependency:   "Components depend on each other in a cycle and cannot be initialized",
+	RuleUnresolvedDependency: "An autowired field has no matching component",
+	RuleQualifierConflict:    "Multiple components implement the same interface with the same qualifier",
+	RuleUnusedComponent:      "A component is not used as a dependency by any other component",
+	RulePrivateComponent:     "An autowired field injects a component that its module does not export",
+}
+
+// reportRules lists the rule IDs in a stable order
+var reportRules = []string{RuleCircularDependency, RuleUnresolvedDependency, RulePrivateComponent, RuleQualifierConflict, RuleUnusedComponent}
+
+// reportPath returns a slash separated path relative to baseDir, or the path itself
+func reportPath(baseDir, file string) string {
+	if baseDir != "" && filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(baseDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+type sarifReporter struct{ baseDir string }
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations,omitempty"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// Report writes a SARIF 2.1.0 log with one run
+func (r sarifReporter) Report(w io.Writer, findings, suppressed []Finding) error {
+	driver := sarifDriver{
+		Name:           "iocgen",
+		Version:        Version,
+		InformationURI: "https://github.com/tuhuynh27/go-ioc",
+	}
+	ruleIndex := make(map[string]int)
+	for i, rule := range reportRules {
+		level := "error"
+		if rule == RuleUnusedComponent {
+			level = "warning"
+		}
+		ruleIndex[rule] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule,
+			ShortDescription:     sarifMessage{Text: ruleDescriptions[rule]},
+			DefaultConfiguration: sarifConfiguration{Level: level},
+		})
+	}
+
+	results := []sarifResult{}
+	add := func(finding Finding, isSuppressed bool) {
+		result := sarifResult{
+			RuleID:              finding.RuleID,
+			RuleIndex:           ruleIndex[finding.RuleID],
+			Level:               finding.Severity,
+			Message:             sarifMessage{Text: finding.Message},
+			PartialFingerprints: map[string]string{"iocgenFingerprint/v1": finding.Fingerprint},
+		}
+		if finding.SourceFile != "" {
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: reportPath(r.baseDir, finding.SourceFile)}}
+			if !filepath.IsAbs(location.ArtifactLocation.URI) {
+				location.ArtifactLocation.URIBaseID = "%SRCROOT%"
+			}
+			if finding.LineNumber > 0 {
+				location.Region = &sarifRegion{StartLine: finding.LineNumber, StartColumn: finding.Column}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+		if isSuppressed {
+			result.Suppressions = []sarifSuppression{{Kind: "inSource", Justification: finding.SuppressionReason}}
+		}
+		results = append(results, result)
+	}
+	for _, finding := range findings {
+		add(finding, false)
+	}
+	for _, finding := range suppressed {
+		add(finding, true)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+type junitReporter struct{ baseDir string }
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// Report writes one test suite per rule. Every finding is a failed test case,
+// suppressed findings are skipped and rules without findings get a passing case
+func (r junitReporter) Report(w io.Writer, findings, suppressed []Finding) error {
+	suites := junitTestSuites{Name: "iocgen"}
+	for _, rule := range reportRules {
+		suite := junitTestSuite{Name: rule}
+		for _, finding := range findings {
+			if finding.RuleID != rule {
+				continue
+			}
+			testCase := r.testCase(finding)
+			location := ""
+			if finding.SourceFile != "" {
+				location = fmt.Sprintf("\n%s:%d", reportPath(r.baseDir, finding.SourceFile), finding.LineNumber)
+			}
+			testCase.Failure = &junitFailure{Message: finding.Message, Type: finding.Severity, Text: finding.Message + location}
+			suite.Cases = append(suite.Cases, testCase)
+			suite.Failures++
+		}
+		for _, finding := range suppressed {
+			if finding.RuleID != rule {
+				continue
+			}
+			testCase := r.testCase(finding)
+			testCase.Skipped = &junitSkipped{Message: "suppressed: " + finding.SuppressionReason}
+			suite.Cases = append(suite.Cases, testCase)
+			suite.Skipped++
+		}
+		if len(suite.Cases) == 0 {
+			suite.Cases = append(suite.Cases, junitTestCase{Name: "no findings", ClassName: "iocgen." + rule})
+		}
+		suite.Tests = len(suite.Cases)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	return writeXML(w, suites)
+}
+
+// testCase creates the test case for a finding
+func (r junitReporter) testCase(finding Finding) junitTestCase {
+	name := finding.Component
+	if finding.FieldName != "" {
+		name += "." + finding.FieldName
+	}
+	testCase := junitTestCase{Name: name, ClassName: "iocgen." + finding.RuleID, Line: finding.LineNumber}
+	if finding.SourceFile != "" {
+		testCase.File = reportPath(r.baseDir, finding.SourceFile)
+	}
+	return testCase
+}
+
+type checkstyleReporter struct{ baseDir string }
+
+type checkstyleResult struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// Report writes findings grouped by source file. Checkstyle has no notion of
+// suppressions, so suppressed findings are left out
+func (r checkstyleReporter) Report(w io.Writer, findings, _ []Finding) error {
+	files := make(map[string][]checkstyleError)
+	for _, finding := range findings {
+		name := reportPath(r.baseDir, finding.SourceFile)
+		files[name] = append(files[name], checkstyleError{
+			Line:     finding.LineNumber,
+			Column:   finding.Column,
+			Severity: finding.Severity,
+			Message:  finding.Message,
+			Source:   "iocgen." + finding.RuleID,
+		})
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := checkstyleResult{Version: "4.3"}
+	for _, name := range names {
+		errs := files[name]
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+		result.Files = append(result.Files, checkstyleFile{Name: name, Errors: errs})
+	}
+
+	return writeXML(w, result)
+}
+
+// writeXML writes an indented XML document with its header
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,151 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func reportTestFindings() ([]Finding, []Finding) {
+	findings := []Finding{
+		{
+			RuleID:      RuleUnresolvedDependency,
+			Severity:    "error",
+			Message:     "Could not resolve dependency 'cache.Cache'",
+			Component:   "example.com/app/service.UserService",
+			FieldName:   "Cache",
+			SourceFile:  "/src/app/service/user.go",
+			LineNumber:  12,
+			Column:      2,
+			Fingerprint: "unresolved-dependency:example.com/app/service.UserService.Cache",
+		},
+		{
+			RuleID:      RuleUnusedComponent,
+			Severity:    "warning",
+			Message:     "Component example.com/app/service.UserService is not used",
+			Component:   "example.com/app/service.UserService",
+			SourceFile:  "/src/app/service/user.go",
+			LineNumber:  8,
+			Fingerprint: "unused-component:example.com/app/service.UserService",
+		},
+	}
+	suppressed := []Finding{
+		{
+			RuleID:            RuleUnusedComponent,
+			Severity:          "warning",
+			Message:           "Component example.com/app.Server is not used",
+			Component:         "example.com/app.Server",
+			SourceFile:        "/src/app/server.go",
+			LineNumber:        5,
+			Fingerprint:       "unused-component:example.com/app.Server",
+			SuppressionReason: "entry point",
+		},
+	}
+	return findings, suppressed
+}
+
+func TestSARIFReporter(t *testing.T) {
+	findings, suppressed := reportTestFindings()
+	reporter, err := NewReporter(ReportFormatSARIF, "/src/app")
+	if err != nil {
+		t.Fatalf("NewReporter failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := reporter.Report(&buf, findings, suppressed); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("SARIF output is not valid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Expected a single SARIF 2.1.0 run, got version %q with %d runs", log.Version, len(log.Runs))
+	}
+
+	results := log.Runs[0].Results
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	first := results[0]
+	if first.RuleID != RuleUnresolvedDependency || first.Level != "error" {
+		t.Errorf("Unexpected first result: %+v", first)
+	}
+	location := first.Locations[0].PhysicalLocation
+	if location.ArtifactLocation.URI != "service/user.go" || location.ArtifactLocation.URIBaseID != "%SRCROOT%" {
+		t.Errorf("Expected path relative to the source root, got %+v", location.ArtifactLocation)
+	}
+	if location.Region == nil || location.Region.StartLine != 12 || location.Region.StartColumn != 2 {
+		t.Errorf("Expected region at the field position, got %+v", location.Region)
+	}
+	if log.Runs[0].Tool.Driver.Rules[first.RuleIndex].ID != first.RuleID {
+		t.Errorf("Rule index %d does not point at rule %s", first.RuleIndex, first.RuleID)
+	}
+
+	if len(results[2].Suppressions) != 1 || results[2].Suppressions[0].Justification != "entry point" {
+		t.Errorf("Expected suppressed finding to carry its justification, got %+v", results[2].Suppressions)
+	}
+}
+
+func TestJUnitReporter(t *testing.T) {
+	findings, suppressed := reportTestFindings()
+	reporter, _ := NewReporter(ReportFormatJUnit, "/src/app")
+
+	var buf bytes.Buffer
+	if err := reporter.Report(&buf, findings, suppressed); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("JUnit output is not valid XML: %v", err)
+	}
+	if suites.Failures != 2 || suites.Skipped != 1 {
+		t.Errorf("Expected 2 failures and 1 skipped case, got %d and %d", suites.Failures, suites.Skipped)
+	}
+	if len(suites.Suites) != len(reportRules) {
+		t.Errorf("Expected one suite per rule, got %d", len(suites.Suites))
+	}
+	// Rules without findings still produce a passing test case
+	if suites.Tests != 6 {
+		t.Errorf("Expected 6 test cases, got %d", suites.Tests)
+	}
+}
+
+func TestCheckstyleReporter(t *testing.T) {
+	findings, suppressed := reportTestFindings()
+	reporter, _ := NewReporter(ReportFormatCheckstyle, "/src/app")
+
+	var buf bytes.Buffer
+	if err := reporter.Report(&buf, findings, suppressed); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+
+	var result checkstyleResult
+	if err := xml.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Checkstyle output is not valid XML: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Name != "service/user.go" {
+		t.Fatalf("Expected findings grouped under service/user.go, got %+v", result.Files)
+	}
+	errs := result.Files[0].Errors
+	if len(errs) != 2 || errs[0].Line != 8 || errs[1].Source != "iocgen."+RuleUnresolvedDependency {
+		t.Errorf("Expected errors sorted by line with rule sources, got %+v", errs)
+	}
+	if strings.Contains(buf.String(), "entry point") {
+		t.Error("Suppressed findings should not be reported")
+	}
+}
+
+func TestParseReportFormat(t *testing.T) {
+	if f, err := ParseReportFormat("SARIF"); err != nil || f != ReportFormatSARIF {
+		t.Errorf("Expected sarif format, got %q (%v)", f, err)
+	}
+	if _, err := ParseReportFormat("html"); err == nil {
+		t.Error("Expected error for unknown report format")
+	}
+}
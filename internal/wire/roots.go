@@ -0,0 +1,73 @@
+package wire
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rootInit is a field of the Roots struct returned by a narrowed Initialize
+type rootInit struct {
+	Field   string // Field of the root in Roots
+	Type    string // Component type name
+	Package string // Package path where the component is defined
+	Ref     string // Path of the instance relative to the internal container
+}
+
+// reachableComponents resolves root references and returns the components they
+// transitively depend on, in declaration order, together with the root keys
+func reachableComponents(components []Component, refs []string) ([]Component, []string, error) {
+	analyzer := NewAnalyzer(components)
+	var rootKeys []string
+	for _, ref := range refs {
+		keys := analyzer.resolveRef(ref)
+		if len(keys) == 0 {
+			return nil, nil, fmt.Errorf("no component matches root %q", ref)
+		}
+		for _, key := range keys {
+			rootKeys = appendUnique(rootKeys, key)
+		}
+	}
+
+	reachable := analyzer.reachableFrom(rootKeys)
+	var selected []Component
+	for _, comp := range components {
+		if reachable[componentKey(comp)] {
+			selected = append(selected, comp)
+		}
+	}
+	return selected, rootKeys, nil
+}
+
+// ForRoots narrows the generator to the given roots. Initialize then builds only
+// the roots and what they transitively depend on, and returns a Roots struct
+// holding just the roots instead of the Container with every component
+func (g *Generator) ForRoots(refs []string) (*Generator, error) {
+	if len(refs) == 0 {
+		return g, nil
+	}
+	selected, rootKeys, err := reachableComponents(g.components, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	g.components = selected
+	g.index = newComponentIndex(selected)
+	g.roots = rootKeys
+	g.generateArgs += " --roots=" + strings.Join(refs, ",")
+	return g, nil
+}
+
+// rootInits returns the Roots fields of the roots, in the order they were requested
+func (g *Generator) rootInits(inits []componentInit) []rootInit {
+	byKey := make(map[string]componentInit)
+	for _, init := range inits {
+		byKey[init.Package+"."+init.Type] = init
+	}
+
+	var roots []rootInit
+	for _, key := range g.roots {
+		init := byKey[key]
+		roots = append(roots, rootInit{Field: init.VarName, Type: init.Type, Package: init.Package, Ref: init.Ref})
+	}
+	return roots
+}
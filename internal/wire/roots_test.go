@@ -0,0 +1,75 @@
+package wire
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerator_ForRoots(t *testing.T) {
+	components, err := ParseComponents(filepath.Join("testdata", "golden", "layered"))
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+
+	gen, err := NewGenerator(components).ForRoots([]string{"handler.UserHandler"})
+	if err != nil {
+		t.Fatalf("ForRoots failed: %v", err)
+	}
+	code, err := gen.GenerateCode()
+	if err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"iocgen --dir=../ --roots=handler.UserHandler\n",
+		"type Roots struct {\n\tUserHandler *handler.UserHandler\n}",
+		"type container struct {",
+		"func Initialize() (*Roots, func()) {",
+		"container.UserRepository = &repository.UserRepository{",
+		"return &Roots{\n\t\tUserHandler: container.UserHandler,\n\t}, cleanup",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Expected generated code to contain %q:\n%s", want, code)
+		}
+	}
+	// Components the root does not depend on are neither built nor imported
+	for _, unwanted := range []string{"OrderService", "OrderHandler", "OrderRepository", "type Container"} {
+		if strings.Contains(string(code), unwanted) {
+			t.Errorf("Expected generated code not to contain %q:\n%s", unwanted, code)
+		}
+	}
+
+	// The header hashes the narrowed components, which drift is computed against
+	modelHash, _, ok := ParseModelHeader(code)
+	if !ok || modelHash != ComputeModelHashes(gen.Components()).ModelHash() || len(gen.Components()) != 4 {
+		t.Errorf("Expected the header to hash the %d selected components", len(gen.Components()))
+	}
+}
+
+func TestGenerator_ForRootsSharedDependencies(t *testing.T) {
+	components, err := ParseComponents(filepath.Join("testdata", "golden", "layered"))
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+
+	gen, err := NewGenerator(components).ForRoots([]string{"service.OrderService", "handler.UserHandler"})
+	if err != nil {
+		t.Fatalf("ForRoots failed: %v", err)
+	}
+	code, err := gen.GenerateCode()
+	if err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	// The user service both roots need is built once, the order handler not at all
+	if strings.Count(string(code), "container.UserService = &service.UserService{") != 1 || strings.Contains(string(code), "OrderHandler") {
+		t.Errorf("Unexpected generated code:\n%s", code)
+	}
+	if !strings.Contains(string(code), "\tOrderService *service.OrderService\n\tUserHandler  *handler.UserHandler\n}") {
+		t.Errorf("Expected both roots in the Roots struct:\n%s", code)
+	}
+
+	if _, err := NewGenerator(components).ForRoots([]string{"handler.PaymentHandler"}); err == nil {
+		t.Error("Expected an error for a root that matches no component")
+	}
+}
@@ -0,0 +1,284 @@
+package wire
+
+import (
+	"bytes"
+	"fmt"
+	"go/build"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// skippedDirs are directory names that never contain components of the module
+var skippedDirs = map[string]bool{
+	"vendor":       true,
+	"testdata":     true,
+	"node_modules": true,
+}
+
+// scanScope decides which directories and files of a module are scanned and
+// which module each of them belongs to
+type scanScope struct {
+	rootDir string
+	modules *workspace
+	roots   []string        // Absolute directories to walk
+	flat    map[string]bool // Roots whose sub directories are not walked
+	deps    []string        // Directories of dependency module packages
+	include []string
+	exclude []string
+	build   build.Context
+}
+
+// newScanScope builds the scan scope of rootDir from the parse options. Without
+// explicit roots, rootDir and the other modules of its workspace are scanned
+func newScanScope(rootDir string, opts ParseOptions) (*scanScope, error) {
+	modules, err := loadWorkspace(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	scope := &scanScope{
+		rootDir: rootDir,
+		modules: modules,
+		flat:    make(map[string]bool),
+		include: opts.Include,
+		exclude: opts.Exclude,
+		build:   build.Default,
+	}
+	if opts.GOOS != "" {
+		scope.build.GOOS = opts.GOOS
+	}
+	if opts.GOARCH != "" {
+		scope.build.GOARCH = opts.GOARCH
+	}
+	scope.build.BuildTags = append([]string(nil), opts.Tags...)
+
+	for _, pattern := range append(append([]string(nil), opts.Include...), opts.Exclude...) {
+		if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	for _, root := range opts.Roots {
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(rootDir, root)
+		}
+		root = filepath.Clean(root)
+		if !modules.contains(rootDir, root) {
+			return nil, fmt.Errorf("scan root %s is outside of %s and its workspace", root, rootDir)
+		}
+		scope.roots = append(scope.roots, root)
+	}
+	if len(scope.roots) == 0 {
+		scope.roots = append([]string{rootDir}, modules.externalDirs(rootDir)...)
+	}
+
+	// Packages of dependency modules are scanned with the same rules as local ones
+	for _, pattern := range opts.Modules {
+		deps, err := modules.dependencyRoots(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			scope.roots = append(scope.roots, dep.Dir)
+			scope.deps = append(scope.deps, dep.Dir)
+			if !dep.Recursive {
+				scope.flat[dep.Dir] = true
+			}
+		}
+	}
+	return scope, nil
+}
+
+// relPath returns the slash separated path of an absolute path relative to the root
+func (s *scanScope) relPath(absPath string) string {
+	rel, err := filepath.Rel(s.rootDir, absPath)
+	if err != nil {
+		return filepath.ToSlash(absPath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// skipDir reports whether a directory and everything below it is left out: hidden
+// directories, vendor, testdata, node_modules and directories matching an exclude pattern
+func (s *scanScope) skipDir(dir string) bool {
+	if dir == s.rootDir || s.isWorkspaceModule(dir) {
+		return false
+	}
+	name := filepath.Base(dir)
+	if strings.HasPrefix(name, ".") || skippedDirs[name] {
+		return true
+	}
+	return matchAny(s.exclude, s.relPath(dir))
+}
+
+// isWorkspaceModule reports whether dir is the root of a module used by the workspace
+func (s *scanScope) isWorkspaceModule(dir string) bool {
+	for _, mod := range s.modules.modules {
+		if mod.Dir == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// includeFileName reports whether a file is scanned judging by its path alone:
+// it must be a .go file that is not a test and matches the include and exclude
+// patterns. Include patterns only narrow down the files below the root directory
+// that are not part of a dependency module, such as the vendored ones
+func (s *scanScope) includeFileName(file string) bool {
+	name := filepath.Base(file)
+	if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+		return false
+	}
+	rel := s.relPath(file)
+	if len(s.include) > 0 && isWithin(s.rootDir, file) && !s.isDependency(file) && !matchAny(s.include, rel) {
+		return false
+	}
+	return !matchAny(s.exclude, rel)
+}
+
+// isDependency reports whether a file belongs to a package of a dependency module
+func (s *scanScope) isDependency(file string) bool {
+	for _, dir := range s.deps {
+		if isWithin(dir, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchBuild reports whether a file is part of the build for the configured
+// GOOS, GOARCH and tags, evaluating //go:build and // +build lines as well as
+// _GOOS and _GOARCH file name suffixes
+func (s *scanScope) matchBuild(file string, content []byte) (bool, error) {
+	ctxt := s.build
+	ctxt.OpenFile = func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+	match, err := ctxt.MatchFile(filepath.Dir(file), filepath.Base(file))
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", file, err)
+	}
+	return match, nil
+}
+
+// walkDirs returns every directory of the scan roots that is not skipped, once
+func (s *scanScope) walkDirs() ([]string, error) {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, root := range s.roots {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+			if seen[path] || (path != root && (s.flat[root] || s.skipDir(path))) {
+				return filepath.SkipDir
+			}
+			seen[path] = true
+			dirs = append(dirs, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
+
+// matchAny reports whether any pattern matches the slash separated path
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash separated path against a glob pattern. A pattern
+// without a slash matches any single path element, like "*_mock.go" or "legacy".
+// Other patterns match from the root, where "**" spans any number of directories.
+// A pattern matching a directory also matches everything below it
+func matchGlob(pattern, rel string) bool {
+	pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+	parts := strings.Split(rel, "/")
+	if !strings.Contains(pattern, "/") && pattern != "**" {
+		for _, part := range parts {
+			if ok, _ := path.Match(pattern, part); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return matchSegments(strings.Split(pattern, "/"), parts)
+}
+
+// matchSegments matches pattern segments against a prefix of the path segments
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
+
+// ForScan records the scan options in the go:generate directive, so that
+// regenerating the file scans the same files. stereotypeConfig is the stereotype
+// config relative to the directory of the generated file, empty for the default
+func (g *Generator) ForScan(scope ParseOptions, stereotypeConfig string) *Generator {
+	g.generateArgs += scanArgs(scope)
+	if stereotypeConfig != "" {
+		g.generateArgs += " " + generateFlag("stereotypes", stereotypeConfig)
+	}
+	return g
+}
+
+// scanArgs renders the scan roots, modules, patterns and build tags of the
+// options as iocgen flags, each preceded by a space
+func scanArgs(scope ParseOptions) string {
+	var args strings.Builder
+	for _, flag := range []struct {
+		name   string
+		values []string
+	}{
+		{"scan", scope.Roots},
+		{"scan-module", scope.Modules},
+		{"include", scope.Include},
+		{"exclude", scope.Exclude},
+		{"tags", scope.Tags},
+	} {
+		if len(flag.values) > 0 {
+			args.WriteString(" " + generateFlag(flag.name, strings.Join(flag.values, ",")))
+		}
+	}
+	return args.String()
+}
+
+// generateFlag renders a flag of a go:generate directive, quoted when go
+// generate would otherwise split or unquote it
+func generateFlag(name, value string) string {
+	arg := "--" + name + "=" + value
+	if strings.ContainsAny(arg, " \t\"\\") {
+		return strconv.Quote(arg)
+	}
+	return arg
+}
@@ -0,0 +1,236 @@
+package wire
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// writeScopeTree writes a module with one component per file, named after the file
+func writeScopeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/scope\n\ngo 1.23\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+	for name, header := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		typeName := strings.NewReplacer("/", "_", ".", "_", "-", "_").Replace(strings.TrimSuffix(name, ".go"))
+		pkg := filepath.Base(filepath.Dir(path))
+		if filepath.Dir(path) == tmpDir {
+			pkg = "main"
+		}
+		src := header + "package " + pkg + "\n\ntype " + typeName + " struct {\n\tComponent struct{}\n}\n"
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return tmpDir
+}
+
+// scannedTypes returns the sorted type names of the parsed components
+func scannedTypes(t *testing.T, rootDir string, opts ParseOptions) []string {
+	t.Helper()
+	components, _, err := ParseComponentsWithOptions(rootDir, opts)
+	if err != nil {
+		t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+	}
+	var types []string
+	for _, comp := range components {
+		types = append(types, comp.Type)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func TestParseComponents_SkipsTestsVendorAndTestdata(t *testing.T) {
+	rootDir := writeScopeTree(t, map[string]string{
+		"app/service.go":             "",
+		"app/service_test.go":        "",
+		"vendor/lib/lib.go":          "",
+		"app/testdata/fixture.go":    "",
+		"web/node_modules/x/x.go":    "",
+		".hidden/hidden.go":          "",
+		"app/internal/repository.go": "",
+	})
+
+	types := scannedTypes(t, rootDir, ParseOptions{})
+	expected := "app_internal_repository,app_service"
+	if got := strings.Join(types, ","); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestParseComponents_IncludeExcludeAndRoots(t *testing.T) {
+	rootDir := writeScopeTree(t, map[string]string{
+		"app/service.go":          "",
+		"app/service_mock.go":     "",
+		"app/legacy/old.go":       "",
+		"lib/util.go":             "",
+		"lib/deep/nested/more.go": "",
+	})
+
+	tests := []struct {
+		name     string
+		opts     ParseOptions
+		expected string
+	}{
+		{"exclude by name", ParseOptions{Exclude: []string{"*_mock.go"}}, "app_legacy_old,app_service,lib_deep_nested_more,lib_util"},
+		{"exclude directory", ParseOptions{Exclude: []string{"app/legacy"}}, "app_service,app_service_mock,lib_deep_nested_more,lib_util"},
+		{"include double star", ParseOptions{Include: []string{"lib/**/*.go"}}, "lib_deep_nested_more,lib_util"},
+		{"include and exclude", ParseOptions{Include: []string{"app/**"}, Exclude: []string{"legacy", "*_mock.go"}}, "app_service"},
+		{"multiple roots", ParseOptions{Roots: []string{"app/legacy", "lib/deep"}}, "app_legacy_old,lib_deep_nested_more"},
+		{"nested roots", ParseOptions{Roots: []string{"lib", "lib/deep"}}, "lib_deep_nested_more,lib_util"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			types := scannedTypes(t, rootDir, tt.opts)
+			if got := strings.Join(types, ","); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	// Package paths stay relative to the module root when scanning a sub directory
+	components, _, err := ParseComponentsWithOptions(rootDir, ParseOptions{Roots: []string{"app/legacy"}})
+	if err != nil || len(components) != 1 || components[0].Package != "example.com/scope/app/legacy" {
+		t.Errorf("Expected package example.com/scope/app/legacy, got %v (%v)", components, err)
+	}
+
+	if _, _, err := ParseComponentsWithOptions(rootDir, ParseOptions{Roots: []string{"../elsewhere"}}); err == nil {
+		t.Error("Expected an error for a scan root outside the module")
+	}
+	if _, _, err := ParseComponentsWithOptions(rootDir, ParseOptions{Exclude: []string{"[bad"}}); err == nil {
+		t.Error("Expected an error for a malformed pattern")
+	}
+}
+
+func TestParseComponents_BuildConstraints(t *testing.T) {
+	rootDir := writeScopeTree(t, map[string]string{
+		"platform/common.go":        "",
+		"platform/store_linux.go":   "",
+		"platform/store_windows.go": "",
+		"platform/arm.go":           "//go:build arm64\n\n",
+		"platform/legacy.go":        "// +build integration\n\n",
+		"platform/pro.go":           "//go:build pro && !linux\n\n",
+		"platform/ignored.go":       "//go:build ignore\n\n",
+	})
+
+	tests := []struct {
+		name     string
+		opts     ParseOptions
+		expected string
+	}{
+		{"linux amd64", ParseOptions{GOOS: "linux", GOARCH: "amd64"}, "platform_common,platform_store_linux"},
+		{"windows arm64", ParseOptions{GOOS: "windows", GOARCH: "arm64"}, "platform_arm,platform_common,platform_store_windows"},
+		{"tags", ParseOptions{GOOS: "darwin", GOARCH: "amd64", Tags: []string{"pro", "integration"}}, "platform_common,platform_legacy,platform_pro"},
+		{"tags excluded by os", ParseOptions{GOOS: "linux", GOARCH: "amd64", Tags: []string{"pro"}}, "platform_common,platform_store_linux"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			types := scannedTypes(t, rootDir, tt.opts)
+			if got := strings.Join(types, ","); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"*_mock.go", "app/service_mock.go", true},
+		{"legacy", "app/legacy/old.go", true},
+		{"legacy", "app/legacyold/old.go", false},
+		{"app/legacy", "app/legacy/old.go", true},
+		{"app/legacy", "lib/app/legacy/old.go", false},
+		{"./app/*", "app/service.go", true},
+		{"app/*.go", "app/legacy/old.go", false},
+		{"**/internal/**", "a/b/internal/c/d.go", true},
+		{"**/internal/**", "internal/d.go", true},
+		{"lib/**/*.go", "lib/util.go", true},
+		{"lib/**/*.go", "lib/a/b/util.go", true},
+		{"lib/**/*.go", "app/util.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.path); got != tt.match {
+			t.Errorf("matchGlob(%q, %q) = %t, expected %t", tt.pattern, tt.path, got, tt.match)
+		}
+	}
+}
+
+func TestGenerator_ForScanDirective(t *testing.T) {
+	scope := ParseOptions{
+		Roots:   []string{"internal", "cmd"},
+		Modules: []string{"github.com/acme/platform/..."},
+		Include: []string{"internal/**", "my dir/*.go"},
+		Exclude: []string{"*_mock.go"},
+		Tags:    []string{"integration"},
+	}
+	gen := NewGenerator([]Component{{Name: "Config", Type: "Config", Package: "example.com/app/config"}}).
+		ForScan(scope, "../config/stereotypes.json")
+	code, err := gen.GenerateCode()
+	if err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	// Split the directive like go generate does and parse it like iocgen does
+	var directive string
+	for _, line := range strings.Split(string(code), "\n") {
+		if strings.HasPrefix(line, "//go:generate ") {
+			directive = line
+		}
+	}
+	var args []string
+	for rest := strings.TrimSpace(directive); rest != ""; rest = strings.TrimSpace(rest) {
+		if rest[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				t.Fatalf("Invalid quoting in %s: %v", directive, err)
+			}
+			arg, _ := strconv.Unquote(quoted)
+			args = append(args, arg)
+			rest = rest[len(quoted):]
+			continue
+		}
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		args = append(args, rest[:end])
+		rest = rest[end:]
+	}
+
+	flags := flag.NewFlagSet("iocgen", flag.ContinueOnError)
+	values := make(map[string]*string)
+	for _, name := range []string{"dir", "scan", "scan-module", "include", "exclude", "tags", "stereotypes"} {
+		values[name] = flags.String(name, "", "")
+	}
+	if len(args) < 4 || args[2] != "run" {
+		t.Fatalf("Unexpected directive %s", directive)
+	}
+	// Skip //go:generate, go run and the package of iocgen
+	if err := flags.Parse(args[4:]); err != nil {
+		t.Fatalf("Failed to parse %s: %v", directive, err)
+	}
+	split := func(name string) []string { return strings.Split(*values[name], ",") }
+	if !slices.Equal(split("scan"), scope.Roots) || !slices.Equal(split("scan-module"), scope.Modules) ||
+		!slices.Equal(split("include"), scope.Include) || !slices.Equal(split("exclude"), scope.Exclude) ||
+		!slices.Equal(split("tags"), scope.Tags) || *values["stereotypes"] != "../config/stereotypes.json" {
+		t.Errorf("Expected the directive to repeat the scan options, got %s", directive)
+	}
+}
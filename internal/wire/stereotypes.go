@@ -0,0 +1,183 @@
+package wire
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultStereotypeConfig is the stereotype config read from the scanned
+// directory when it exists
+const DefaultStereotypeConfig = ".iocgen-stereotypes.json"
+
+// StereotypeConfig is the content of a stereotype config file
+type StereotypeConfig struct {
+	// Markers counting as Component, mapped to their role. Untyped markers are
+	// named like "Service", embedded types like "example.com/app/stereotype.Service"
+	Stereotypes map[string]string `json:"stereotypes"`
+}
+
+// LoadStereotypeConfig reads a stereotype config file and returns its markers
+func LoadStereotypeConfig(file string) (map[string]string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var config StereotypeConfig
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse stereotype config %s: %w", file, err)
+	}
+	for marker, role := range config.Stereotypes {
+		if marker == "" || strings.TrimSpace(role) == "" {
+			return nil, fmt.Errorf("stereotype %q in %s needs a marker and a role", marker, file)
+		}
+		if builtinMarkers[marker] {
+			return nil, fmt.Errorf("stereotype %q in %s is a built-in marker", marker, file)
+		}
+	}
+	return config.Stereotypes, nil
+}
+
+// builtinMarkers are the markers with a meaning of their own, which cannot be stereotypes
+var builtinMarkers = map[string]bool{
+	"Component": true, "Qualifier": true, "Primary": true, "Profile": true,
+	"EntryPoint": true, "Module": true, "Exports": true, "Stereotype": true,
+}
+
+// stereotypeDecl is a type declared as a stereotype by its Stereotype meta-marker
+type stereotypeDecl struct {
+	Type string `json:"type"` // Stereotype type as package.Type
+	Role string `json:"role"` // Value of the meta-marker, the lower-cased type name by default
+}
+
+// stereotypeCandidate is a struct with markers that may be stereotypes. It
+// becomes a component with the role of the first marker that is declared as one
+type stereotypeCandidate struct {
+	Component Component `json:"component"`          // The struct parsed as a component
+	Markers   []string  `json:"markers"`            // Names of untyped markers and package.Type of embedded types
+	Declared  bool      `json:"declared,omitempty"` // Whether the struct has a Component marker as well
+}
+
+// parseStereotypeDecl recognizes a type declaring a stereotype with a
+// Stereotype meta-marker, like `ioc.Stereotype` with a value tag of the role
+func parseStereotypeDecl(typeSpec *ast.TypeSpec, structType *ast.StructType, fullPkgPath, markers string) (stereotypeDecl, bool) {
+	for _, field := range structType.Fields.List {
+		if markerName(field, markers) != "Stereotype" {
+			continue
+		}
+		role := strings.ToLower(typeSpec.Name.Name)
+		if field.Tag != nil {
+			if value := strings.TrimSpace(parseStructTag(field.Tag.Value)["value"]); value != "" {
+				role = value
+			}
+		}
+		return stereotypeDecl{Type: fullPkgPath + "." + typeSpec.Name.Name, Role: role}, true
+	}
+	return stereotypeDecl{}, false
+}
+
+// stereotypeMarker returns the possible stereotype a field declares: the name of
+// an untyped struct{} marker that is not built in, or package.Type of an
+// embedded type. Embedded typed markers of the ioc package are never stereotypes
+func stereotypeMarker(field *ast.Field, fullPkgPath, markers string, imports map[string]string) string {
+	if len(field.Names) > 0 {
+		if st, ok := field.Type.(*ast.StructType); ok && len(st.Fields.List) == 0 && !builtinMarkers[field.Names[0].Name] {
+			return field.Names[0].Name
+		}
+		return ""
+	}
+	switch t := field.Type.(type) {
+	case *ast.Ident:
+		return fullPkgPath + "." + t.Name
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok && x.Name != markers {
+			if path, ok := imports[x.Name]; ok {
+				return path + "." + t.Sel.Name
+			}
+		}
+	}
+	return ""
+}
+
+// fileImports maps the names a file imports packages under to their paths
+func fileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := importName(path)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = path
+	}
+	return imports
+}
+
+// applyStereotypes turns the candidates with a stereotype marker into
+// components with its role. Stereotypes are the types with a meta-marker and
+// the markers of the config, which take precedence. Candidates without a
+// stereotype that have no Component marker are dropped
+func applyStereotypes(components []Component, candidates []stereotypeCandidate, decls []stereotypeDecl, config map[string]string, logger Logger) []Component {
+	if len(candidates) == 0 {
+		return components
+	}
+
+	roles := make(map[string]string, len(decls)+len(config))
+	for _, decl := range decls {
+		roles[decl.Type] = decl.Role
+	}
+	for marker, role := range config {
+		roles[marker] = strings.TrimSpace(role)
+	}
+
+	byKey := make(map[string]int, len(components))
+	for i, comp := range components {
+		byKey[componentKey(comp)] = i
+	}
+	for _, candidate := range candidates {
+		comp := candidate.Component
+		for _, marker := range candidate.Markers {
+			role, ok := roles[marker]
+			if !ok {
+				continue
+			}
+			if comp.Stereotype != "" && comp.Stereotype != role {
+				logger.Printf("Warning: component %s has stereotypes %s and %s, using %s (%s:%d)",
+					componentKey(comp), comp.Stereotype, role, comp.Stereotype, comp.SourceFile, comp.LineNumber)
+				continue
+			}
+			comp.Stereotype = role
+		}
+
+		switch {
+		case candidate.Declared:
+			if i, ok := byKey[componentKey(comp)]; ok {
+				components[i].Stereotype = comp.Stereotype
+			}
+		case comp.Stereotype != "":
+			components = append(components, comp)
+		}
+	}
+	return components
+}
+
+// Stereotypes returns the roles of the components in sorted order
+func Stereotypes(components []Component) []string {
+	seen := make(map[string]bool)
+	var roles []string
+	for _, comp := range components {
+		if comp.Stereotype != "" && !seen[comp.Stereotype] {
+			seen[comp.Stereotype] = true
+			roles = append(roles, comp.Stereotype)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
@@ -0,0 +1,223 @@
+package wire
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var stereotypeFiles = map[string]string{
+	"go.mod": "module example.com/app\n\ngo 1.23\n",
+	"stereotype/stereotype.go": `package stereotype
+
+import "github.com/tuhuynh27/go-ioc/ioc"
+
+type Service struct {
+	ioc.Stereotype ` + "`value:\"service\"`" + `
+}
+
+// The role defaults to the lower-cased type name
+type Repository struct {
+	ioc.Stereotype
+}
+`,
+	"store/store.go": `package store
+
+import (
+	"sync"
+
+	"example.com/app/stereotype"
+)
+
+type UserStore interface {
+	Find(id string) string
+}
+
+type PostgresUserStore struct {
+	stereotype.Repository ` + "`implements:\"UserStore\"`" + `
+	mu sync.Mutex
+}
+
+// Embedding other types does not make a component
+type Cache struct {
+	sync.Mutex
+}
+`,
+	"service/service.go": `package service
+
+import (
+	st "example.com/app/stereotype"
+	"example.com/app/store"
+)
+
+type UserService struct {
+	st.Service ` + "`name:\"users\"`" + `
+	Store store.UserStore ` + "`autowired:\"true\"`" + `
+}
+
+func (s *UserService) PostConstruct() {}
+`,
+	"web/web.go": `package web
+
+import (
+	"example.com/app/service"
+	"example.com/app/store"
+)
+
+// Controller is an untyped stereotype declared in the config
+type UserController struct {
+	Controller struct{}
+	Users      *service.UserService ` + "`autowired:\"true\"`" + `
+	Store      store.UserStore      ` + "`autowired:\"true\"`" + `
+}
+
+type Health struct {
+	Component  struct{}
+	Controller struct{}
+}
+`,
+}
+
+func TestParseComponents_Stereotypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, stereotypeFiles)
+	opts := ParseOptions{
+		CacheDir:    filepath.Join(tmpDir, ".iocgen", "cache"),
+		Stereotypes: map[string]string{"Controller": "controller"},
+	}
+
+	// The second scan reads every file from the cache
+	for _, cached := range []bool{false, true} {
+		components, stats, err := ParseComponentsWithOptions(tmpDir, opts)
+		if err != nil {
+			t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+		}
+		if cached != (stats.CacheMisses == 0) {
+			t.Fatalf("Expected cached=%t, got %+v", cached, stats)
+		}
+
+		roles := make(map[string]string)
+		for _, comp := range components {
+			roles[comp.Type] = comp.Stereotype
+		}
+		expected := map[string]string{
+			"PostgresUserStore": "repository",
+			"UserService":       "service",
+			"UserController":    "controller",
+			"Health":            "controller",
+		}
+		if len(roles) != len(expected) {
+			t.Fatalf("Expected %d components, got %v", len(expected), roles)
+		}
+		for typ, role := range expected {
+			if roles[typ] != role {
+				t.Errorf("Expected %s to have stereotype %q, got %q (cached=%t)", typ, role, roles[typ], cached)
+			}
+		}
+
+		for _, comp := range components {
+			switch comp.Type {
+			case "UserService":
+				if comp.Name != "users" || !comp.PostConstruct || len(comp.Dependencies) != 1 {
+					t.Errorf("Expected the stereotype tag and lifecycle of the user service, got %+v", comp)
+				}
+			case "PostgresUserStore":
+				if len(comp.Implements) != 1 || comp.Implements[0] != "example.com/app/store/UserStore" {
+					t.Errorf("Expected the stereotype to declare the implemented interface, got %v", comp.Implements)
+				}
+			}
+		}
+	}
+
+	// Without the config the untyped marker is an ordinary field
+	components, _, err := ParseComponentsWithOptions(tmpDir, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+	}
+	for _, comp := range components {
+		if comp.Type == "UserController" {
+			t.Errorf("Expected the controller to need the stereotype config")
+		}
+		if comp.Type == "Health" && comp.Stereotype != "" {
+			t.Errorf("Expected the health component without a stereotype, got %q", comp.Stereotype)
+		}
+	}
+}
+
+func TestLoadStereotypeConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"valid.json":   `{"stereotypes": {"Service": "service", "example.com/platform/stereotype.Repository": "repository"}}`,
+		"builtin.json": `{"stereotypes": {"Component": "component"}}`,
+		"empty.json":   `{"stereotypes": {"Service": " "}}`,
+	})
+
+	stereotypes, err := LoadStereotypeConfig(filepath.Join(tmpDir, "valid.json"))
+	if err != nil {
+		t.Fatalf("LoadStereotypeConfig failed: %v", err)
+	}
+	if len(stereotypes) != 2 || stereotypes["example.com/platform/stereotype.Repository"] != "repository" {
+		t.Errorf("Unexpected stereotypes: %v", stereotypes)
+	}
+	for _, name := range []string{"builtin.json", "empty.json"} {
+		if _, err := LoadStereotypeConfig(filepath.Join(tmpDir, name)); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+	if _, err := LoadStereotypeConfig(filepath.Join(tmpDir, "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected a missing config to be reported as such, got %v", err)
+	}
+}
+
+func TestAnalyzer_Stereotypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, stereotypeFiles)
+	components, _, err := ParseComponentsWithOptions(tmpDir, ParseOptions{Stereotypes: map[string]string{"Controller": "controller"}})
+	if err != nil {
+		t.Fatalf("ParseComponentsWithOptions failed: %v", err)
+	}
+	analyzer := NewAnalyzer(components)
+
+	analysis := analyzer.PerformComprehensiveAnalysis()
+	if len(analysis.Stereotypes["controller"]) != 2 || len(analysis.Stereotypes["service"]) != 1 {
+		t.Errorf("Expected the components grouped by stereotype, got %v", analysis.Stereotypes)
+	}
+
+	violations := analyzer.CheckArchitecture(&ArchRuleSet{Rules: []ArchRule{
+		{Name: "controllers-use-services", FromStereotype: "controller", DenyStereotypes: []string{"repository"}},
+		{Name: "services-only", FromStereotype: "service", AllowStereotypes: []string{"repository"}},
+	}})
+	if len(violations) != 1 {
+		t.Fatalf("Expected one violation, got %+v", violations)
+	}
+	v := violations[0]
+	if v.Rule != "controllers-use-services" || v.FieldName != "Store" || v.ToStereotype != "repository" {
+		t.Errorf("Unexpected violation: %+v", v)
+	}
+	expected := "controller example.com/app/web.UserController must not depend on repository example.com/app/store.PostgresUserStore: dependency on repository is denied"
+	if v.Message != expected {
+		t.Errorf("Expected message %q, got %q", expected, v.Message)
+	}
+
+	graph, err := analyzer.BuildGraph(GraphOptions{ByStereotype: true})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+	var dot, graphML bytes.Buffer
+	if err := graph.Write(&dot, GraphFormatDOT); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	for _, want := range []string{`label="controller";`, `label="repository";`, `fillcolor="#dbeafe"`, `fillcolor="#fef3c7"`} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("Expected DOT output to contain %s:\n%s", want, dot.String())
+		}
+	}
+	if err := graph.Write(&graphML, GraphFormatGraphML); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if !strings.Contains(graphML.String(), `<data key="stereotype">service</data>`) {
+		t.Errorf("Expected GraphML output to carry the stereotype:\n%s", graphML.String())
+	}
+}
@@ -0,0 +1,6 @@
+package config
+
+type Config struct {
+	Component struct{}
+	DSN       string
+}
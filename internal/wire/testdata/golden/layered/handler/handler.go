@@ -0,0 +1,14 @@
+package handler
+
+import "example.com/layered/service"
+
+type UserHandler struct {
+	Component struct{}
+	Users     *service.UserService `autowired:"true"`
+}
+
+type OrderHandler struct {
+	Component struct{}
+	Orders    *service.OrderService `autowired:"true"`
+	Users     *service.UserService  `autowired:"true"`
+}
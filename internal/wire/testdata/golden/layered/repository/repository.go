@@ -0,0 +1,13 @@
+package repository
+
+import "example.com/layered/config"
+
+type UserRepository struct {
+	Component struct{}
+	Config    *config.Config `autowired:"true"`
+}
+
+type OrderRepository struct {
+	Component struct{}
+	Config    *config.Config `autowired:"true"`
+}
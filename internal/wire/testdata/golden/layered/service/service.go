@@ -0,0 +1,14 @@
+package service
+
+import "example.com/layered/repository"
+
+type UserService struct {
+	Component struct{}
+	Users     *repository.UserRepository `autowired:"true"`
+}
+
+type OrderService struct {
+	Component struct{}
+	Orders    *repository.OrderRepository `autowired:"true"`
+	Users     *UserService                `autowired:"true"`
+}
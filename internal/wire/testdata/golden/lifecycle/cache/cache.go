@@ -0,0 +1,14 @@
+package cache
+
+import "example.com/lifecycle/db"
+
+type Cache struct {
+	Component struct{}
+	Pool      *db.Pool `autowired:"true"`
+}
+
+func NewCache(pool *db.Pool) *Cache {
+	return &Cache{Pool: pool}
+}
+
+func (c *Cache) PreDestroy() {}
@@ -0,0 +1,9 @@
+package db
+
+type Pool struct {
+	Component struct{}
+}
+
+func (p *Pool) PostConstruct() {}
+
+func (p *Pool) PreDestroy() {}
@@ -0,0 +1,15 @@
+package server
+
+import (
+	"example.com/lifecycle/cache"
+	"example.com/lifecycle/db"
+)
+
+type Server struct {
+	Component  struct{}
+	EntryPoint struct{}
+	Cache      *cache.Cache `autowired:"true"`
+	Pool       *db.Pool     `autowired:"true"`
+}
+
+func (s *Server) PostConstruct() {}
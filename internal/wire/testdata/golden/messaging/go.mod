@@ -0,0 +1,3 @@
+module example.com/messaging
+
+go 1.23
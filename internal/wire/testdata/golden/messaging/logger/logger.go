@@ -0,0 +1,19 @@
+package logger
+
+type Logger interface {
+	Log(msg string)
+}
+
+type ConsoleLogger struct {
+	Component struct{} `implements:"Logger"`
+	Qualifier struct{} `value:"console"`
+}
+
+func (l *ConsoleLogger) Log(msg string) {}
+
+type FileLogger struct {
+	Component struct{} `implements:"Logger"`
+	Qualifier struct{} `value:"file"`
+}
+
+func (l *FileLogger) Log(msg string) {}
@@ -0,0 +1,23 @@
+package message
+
+import "example.com/messaging/logger"
+
+type Sender interface {
+	Send(msg string) error
+}
+
+type EmailSender struct {
+	Component struct{}      `implements:"Sender"`
+	Qualifier struct{}      `value:"email"`
+	Logger    logger.Logger `autowired:"true" qualifier:"file"`
+}
+
+func (s *EmailSender) Send(msg string) error { return nil }
+
+type SmsSender struct {
+	Component struct{}      `implements:"Sender"`
+	Qualifier struct{}      `value:"sms"`
+	Logger    logger.Logger `autowired:"true" qualifier:"console"`
+}
+
+func (s *SmsSender) Send(msg string) error { return nil }
@@ -0,0 +1,13 @@
+package notification
+
+import (
+	"example.com/messaging/logger"
+	"example.com/messaging/message"
+)
+
+type NotificationService struct {
+	Component struct{}
+	Email     message.Sender `autowired:"true" qualifier:"email"`
+	Sms       message.Sender `autowired:"true" qualifier:"sms"`
+	Logger    logger.Logger  `autowired:"true" qualifier:"console"`
+}
@@ -0,0 +1,6 @@
+package billing
+
+type InvoiceService struct {
+	Component struct{}
+	Ledger    *Ledger `autowired:"true"`
+}
@@ -0,0 +1,14 @@
+package billing
+
+import (
+	"example.com/modules/billing/tax"
+	"example.com/modules/shared"
+)
+
+type Ledger struct {
+	Component struct{}
+	Tax       *tax.Table    `autowired:"true"`
+	Clock     *shared.Clock `autowired:"true"`
+}
+
+func (l *Ledger) PreDestroy() {}
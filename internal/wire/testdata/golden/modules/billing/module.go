@@ -0,0 +1,8 @@
+package billing
+
+// BillingModule groups the billing components. Only the invoice service is
+// visible to other modules
+type BillingModule struct {
+	Module  struct{} `value:"billing"`
+	Exports struct{} `value:"InvoiceService"`
+}
@@ -0,0 +1,5 @@
+package tax
+
+type Table struct {
+	Component struct{}
+}
@@ -0,0 +1,6 @@
+package orders
+
+type OrdersModule struct {
+	Module  struct{} `value:"orders"`
+	Exports struct{} `value:"OrderService"`
+}
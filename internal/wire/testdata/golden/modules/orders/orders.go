@@ -0,0 +1,11 @@
+package orders
+
+import "example.com/modules/billing"
+
+type OrderService struct {
+	Component struct{}
+	Invoices  *billing.InvoiceService `autowired:"true"`
+	Repo      *Repository             `autowired:"true"`
+}
+
+func (s *OrderService) PostConstruct() {}
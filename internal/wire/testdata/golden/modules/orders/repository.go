@@ -0,0 +1,5 @@
+package orders
+
+type Repository struct {
+	Component struct{}
+}
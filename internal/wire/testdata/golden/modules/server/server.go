@@ -0,0 +1,8 @@
+package server
+
+import "example.com/modules/orders"
+
+type Server struct {
+	Component struct{}
+	Orders    *orders.OrderService `autowired:"true"`
+}
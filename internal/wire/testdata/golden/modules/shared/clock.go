@@ -0,0 +1,5 @@
+package shared
+
+type Clock struct {
+	Component struct{}
+}
@@ -0,0 +1,7 @@
+package wire
+
+// Version is the iocgen version shown in the banner, reported by machine readable
+// outputs and part of the scan cache key. Release builds set it with
+//
+//	go build -ldflags "-X github.com/tuhuynh27/go-ioc/internal/wire.Version=v1.2.3"
+var Version = "dev"
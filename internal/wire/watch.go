@@ -0,0 +1,422 @@
+package wire
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WatchOptions configures a Watcher
+type WatchOptions struct {
+	Interval time.Duration // How often the source tree is polled for changes
+	Debounce time.Duration // Quiet period after the last change before regenerating
+	Output   io.Writer     // Destination of status messages and diagnostics
+	Scope    ParseOptions  // Roots, patterns and build constraints selecting the scanned files, the cache is not used
+
+	Containers []ContainerSpec // Named containers of the manifest, EntryPoint markers may add more
+	Select     []string        // Names of the containers to regenerate, all when empty
+	Roots      []string        // Components a narrowed Initialize is generated for, when there are no named containers
+	OutputDir  string          // Directory of wire_gen.go when there are no named containers, defaults to <rootDir>/wire
+
+	StereotypeConfig string // Absolute path of the stereotype config to record in the go:generate directive, empty for the default
+}
+
+// Watcher polls a source tree and regenerates wire_gen.go whenever the component
+// model changes. Only packages with changed .go files are parsed again, and a
+// failing model never overwrites the last good generated file. With named
+// containers every container is regenerated on its own
+type Watcher struct {
+	rootDir    string
+	outputDirs map[string]bool // Directories of generated files, not scanned for components
+	opts       WatchOptions
+	scope      *scanScope
+
+	stamps      map[string]string   // Directory -> fingerprint of its .go files
+	packages    map[string]fileScan // Directory -> components, modules and stereotypes declared in it
+	parseErrors map[string]error    // Directories that currently fail to parse
+
+	pending    map[string]bool // Changed directories waiting for the debounce period
+	lastChange time.Time
+
+	lastHashes map[string]ModelHashes // Output directory -> model its wire_gen.go was generated from
+}
+
+// watchTarget is a generated file the watcher keeps up to date
+type watchTarget struct {
+	label      string      // Describes the file in status messages
+	outputDir  string      // Directory of the generated wire_gen.go
+	components []Component // Components wired into the file
+	generator  *Generator
+}
+
+// NewWatcher creates a watcher for the module directory rootDir
+func NewWatcher(rootDir string, opts WatchOptions) (*Watcher, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = 500 * time.Millisecond
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	scope, err := newScanScope(rootDir, opts.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.OutputDir == "" {
+		opts.OutputDir = filepath.Join(rootDir, "wire")
+	}
+	outputDirs := map[string]bool{opts.OutputDir: true}
+	for _, spec := range opts.Containers {
+		outputDirs[spec.OutputDir(rootDir)] = true
+	}
+
+	return &Watcher{
+		rootDir:     rootDir,
+		outputDirs:  outputDirs,
+		opts:        opts,
+		scope:       scope,
+		stamps:      make(map[string]string),
+		packages:    make(map[string]fileScan),
+		parseErrors: make(map[string]error),
+		pending:     make(map[string]bool),
+		lastHashes:  make(map[string]ModelHashes),
+	}, nil
+}
+
+// Run parses the whole tree, brings wire_gen.go up to date and then polls for
+// changes until stop is closed
+func (w *Watcher) Run(stop <-chan struct{}) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.printf("👀 Watching %s for changes (Ctrl+C to stop)\n", w.rootDir)
+
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-ticker.C:
+			if err := w.poll(now); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// start performs the initial full parse and generation
+func (w *Watcher) start() error {
+	stamps, err := w.scan()
+	if err != nil {
+		return err
+	}
+	var dirs []string
+	for dir := range stamps {
+		dirs = append(dirs, dir)
+	}
+	w.stamps = stamps
+	w.reparse(dirs)
+	w.rebuild()
+	return nil
+}
+
+// poll checks for changed packages and rebuilds once no change was seen for the
+// debounce period
+func (w *Watcher) poll(now time.Time) error {
+	stamps, err := w.scan()
+	if err != nil {
+		return err
+	}
+
+	for dir, stamp := range stamps {
+		if w.stamps[dir] != stamp {
+			w.pending[dir] = true
+		}
+	}
+	for dir := range w.stamps {
+		if _, exists := stamps[dir]; !exists {
+			w.pending[dir] = true
+		}
+	}
+	if changed := !stampsEqual(w.stamps, stamps); changed {
+		w.lastChange = now
+		w.stamps = stamps
+	}
+
+	if len(w.pending) == 0 || now.Sub(w.lastChange) < w.opts.Debounce {
+		return nil
+	}
+
+	var dirs []string
+	for dir := range w.pending {
+		dirs = append(dirs, dir)
+	}
+	w.pending = make(map[string]bool)
+	w.reparse(dirs)
+	w.rebuild()
+	return nil
+}
+
+// scan fingerprints the scanned .go files of every directory in scope
+func (w *Watcher) scan() (map[string]string, error) {
+	dirs, err := w.scope.walkDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	stamps := make(map[string]string)
+	for _, dir := range dirs {
+		if w.outputDirs[dir] {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue // Removed while scanning, picked up by the next poll
+		}
+		var stamp strings.Builder
+		for _, entry := range entries {
+			if entry.IsDir() || !w.scope.includeFileName(filepath.Join(dir, entry.Name())) {
+				continue
+			}
+			fileInfo, err := entry.Info()
+			if err != nil {
+				continue // Removed while scanning, picked up by the next poll
+			}
+			fmt.Fprintf(&stamp, "%s:%d:%d;", entry.Name(), fileInfo.Size(), fileInfo.ModTime().UnixNano())
+		}
+		if stamp.Len() > 0 {
+			stamps[dir] = stamp.String()
+		}
+	}
+	return stamps, nil
+}
+
+// reparse parses the given directories again, dropping the ones that no longer
+// exist. Each rebuild uses a fresh FileSet, which would otherwise keep every
+// parsed file for the lifetime of the watcher
+func (w *Watcher) reparse(dirs []string) {
+	sort.Strings(dirs)
+	fset := token.NewFileSet()
+	for _, dir := range dirs {
+		if _, exists := w.stamps[dir]; !exists {
+			delete(w.packages, dir)
+			delete(w.parseErrors, dir)
+			continue
+		}
+
+		scan, err := parsePackageDir(fset, w.scope, dir, nil, &ParseStats{})
+		if err != nil {
+			// Keep the previous components of the package until it parses again
+			w.parseErrors[dir] = err
+			continue
+		}
+		delete(w.parseErrors, dir)
+		w.packages[dir] = scan
+	}
+}
+
+// components returns the current component model
+func (w *Watcher) components() []Component {
+	var components []Component
+	var decls []moduleDecl
+	var stereotypes []stereotypeDecl
+	var candidates []stereotypeCandidate
+	for _, dir := range sortedKeys(w.packages) {
+		components = append(components, w.packages[dir].Components...)
+		decls = append(decls, w.packages[dir].Modules...)
+		stereotypes = append(stereotypes, w.packages[dir].Stereotypes...)
+		candidates = append(candidates, w.packages[dir].Candidates...)
+	}
+	logger := w.opts.Scope.logger()
+	components = applyStereotypes(components, candidates, stereotypes, w.opts.Scope.Stereotypes, logger)
+	return ActivateProfiles(assignModules(components, decls, logger), w.opts.Scope.Profiles)
+}
+
+// rebuild validates the current model and regenerates the generated files whose
+// components changed. Problems are printed as diagnostics and leave the last good
+// file in place
+func (w *Watcher) rebuild() {
+	if len(w.parseErrors) > 0 {
+		for _, dir := range sortedKeys(w.parseErrors) {
+			w.printf("  %v\n", w.parseErrors[dir])
+		}
+		w.printf("❌ Syntax errors, keeping the last good wire_gen.go\n")
+		return
+	}
+
+	targets, err := w.targets(w.components())
+	if err != nil {
+		w.printf("  %v\n❌ Invalid containers, keeping the last good wire_gen.go\n", err)
+		return
+	}
+	for _, target := range targets {
+		w.rebuildTarget(target)
+	}
+}
+
+// targets returns the generated files of the current model: one per selected named
+// container, or the wire package of the whole project when there are none
+func (w *Watcher) targets(components []Component) ([]watchTarget, error) {
+	specs, err := CollectContainers(w.opts.Containers, components)
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) == 0 {
+		gen := NewGenerator(components)
+		if w.opts.OutputDir != filepath.Join(w.rootDir, "wire") {
+			gen.ForOutput(w.rootDir, w.opts.OutputDir)
+		}
+		gen, err := gen.ForProfiles(w.opts.Scope.Profiles).ForRoots(w.opts.Roots)
+		if err != nil {
+			return nil, err
+		}
+		return []watchTarget{{
+			label:      "wire_gen.go",
+			outputDir:  w.opts.OutputDir,
+			components: gen.components,
+			generator:  w.recordScan(gen, w.opts.OutputDir),
+		}}, nil
+	}
+
+	if len(w.opts.Scope.Profiles) > 0 {
+		return nil, fmt.Errorf("profiles cannot be combined with named containers, the containers would be overwritten with profiled components")
+	}
+	specs, err = SelectContainers(specs, w.opts.Select)
+	if err != nil {
+		return nil, err
+	}
+	var targets []watchTarget
+	for _, spec := range specs {
+		selected, err := ContainerComponents(components, spec)
+		if err != nil {
+			return nil, err
+		}
+		w.outputDirs[spec.OutputDir(w.rootDir)] = true
+		targets = append(targets, watchTarget{
+			label:      "wire_gen.go of container " + spec.Name,
+			outputDir:  spec.OutputDir(w.rootDir),
+			components: selected,
+			generator:  w.recordScan(NewGenerator(selected).ForContainer(w.rootDir, spec), spec.OutputDir(w.rootDir)),
+		})
+	}
+	return targets, nil
+}
+
+// recordScan makes the go:generate directive of the generator writing into
+// outputDir repeat the scan options the watcher was started with
+func (w *Watcher) recordScan(gen *Generator, outputDir string) *Generator {
+	config := w.opts.StereotypeConfig
+	if config != "" {
+		if rel, err := filepath.Rel(outputDir, config); err == nil {
+			config = rel
+		}
+		config = filepath.ToSlash(config)
+	}
+	return gen.ForScan(w.opts.Scope, config)
+}
+
+// rebuildTarget regenerates one generated file if the code it renders changed.
+// The rendered code is compared rather than the component model, so a file
+// generated by another iocgen version or with other options is replaced as well
+func (w *Watcher) rebuildTarget(target watchTarget) {
+	// Pick up the model of an existing generated file to report what changed
+	existing, readErr := os.ReadFile(filepath.Join(target.outputDir, "wire_gen.go"))
+	lastHashes, known := w.lastHashes[target.outputDir]
+	if !known && readErr == nil {
+		if _, hashes, ok := ParseModelHeader(existing); ok {
+			lastHashes = hashes
+		}
+	}
+
+	hashes := ComputeModelHashes(target.components)
+	code, genErr := target.generator.GenerateCode()
+	if genErr == nil && readErr == nil && bytes.Equal(code, existing) {
+		w.lastHashes[target.outputDir] = hashes
+		w.printf("✅ No component changes, %s is up to date\n", target.label)
+		return
+	}
+
+	// Report problems the generator would stop at, inline with their source positions
+	failed := false
+	for _, finding := range NewAnalyzer(target.components).PerformComprehensiveAnalysis().Findings {
+		if finding.Severity != "error" {
+			continue
+		}
+		failed = true
+		w.printf("  %s:%d: [%s] %s\n", finding.SourceFile, finding.LineNumber, finding.RuleID, finding.Message)
+	}
+	if failed {
+		w.printf("❌ Component model has errors, keeping the last good %s\n", target.label)
+		return
+	}
+
+	err := genErr
+	if err == nil {
+		err = writeGeneratedFile(target.outputDir, code)
+	}
+	if err != nil {
+		w.printf("  %v\n❌ Generation failed, keeping the last good %s\n", err, target.label)
+		return
+	}
+
+	if lastHashes != nil && lastHashes.ModelHash() != hashes.ModelHash() {
+		drift := CompareModels(lastHashes, hashes)
+		for _, key := range drift.Added {
+			w.printf("  + %s\n", key)
+		}
+		for _, key := range drift.Removed {
+			w.printf("  - %s\n", key)
+		}
+		for _, key := range drift.Changed {
+			w.printf("  ~ %s\n", key)
+		}
+	}
+	w.lastHashes[target.outputDir] = hashes
+	w.printf("🔄 Regenerated %s (%d components)\n", target.label, len(target.components))
+}
+
+// printf writes a timestamped status line
+func (w *Watcher) printf(format string, args ...interface{}) {
+	fmt.Fprintf(w.opts.Output, "[%s] "+format, append([]interface{}{time.Now().Format("15:04:05")}, args...)...)
+}
+
+// writeGeneratedFile writes wire_gen.go into dir, creating the directory if needed
+func writeGeneratedFile(dir string, code []byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create wire directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "wire_gen.go"), code, 0644); err != nil {
+		return fmt.Errorf("failed to write generated code: %w", err)
+	}
+	return nil
+}
+
+// stampsEqual reports whether two directory fingerprint sets are identical
+func stampsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for dir, stamp := range a {
+		if b[dir] != stamp {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,134 @@
+package wire
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	writeFile("go.mod", "module example.com/watch\n\ngo 1.23\n")
+	writeFile("config/config.go", "package config\n\ntype Config struct {\n\tComponent struct{}\n}\n")
+
+	var out bytes.Buffer
+	watcher, err := NewWatcher(tmpDir, WatchOptions{Debounce: time.Second, Output: &out})
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+	if err := watcher.start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	wirePath := filepath.Join(tmpDir, "wire", "wire_gen.go")
+	initial, err := os.ReadFile(wirePath)
+	if err != nil {
+		t.Fatalf("Expected initial generation: %v", err)
+	}
+
+	// A new component is picked up once the debounce period has passed
+	writeFile("service/service.go", "package service\n\nimport \"example.com/watch/config\"\n\ntype Service struct {\n\tComponent struct{}\n\tConfig *config.Config `autowired:\"true\"`\n}\n")
+	now := time.Now()
+	if err := watcher.poll(now); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if current, _ := os.ReadFile(wirePath); !bytes.Equal(current, initial) {
+		t.Error("Expected no regeneration before the debounce period")
+	}
+	if err := watcher.poll(now.Add(2 * time.Second)); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	good, _ := os.ReadFile(wirePath)
+	if !strings.Contains(string(good), "container.Service = &service.Service{") {
+		t.Fatalf("Expected the new component to be generated, got:\n%s", good)
+	}
+
+	// A broken model keeps the last good file and reports the problem inline
+	writeFile("service/service.go", "package service\n\ntype Service struct {\n\tComponent struct{}\n\tCache *Cache `autowired:\"true\"`\n}\n")
+	now = now.Add(3 * time.Second)
+	watcher.poll(now)
+	watcher.poll(now.Add(2 * time.Second))
+	if current, _ := os.ReadFile(wirePath); !bytes.Equal(current, good) {
+		t.Error("Expected the last good wire_gen.go to be kept")
+	}
+	if !strings.Contains(out.String(), "service.go:5: ["+RuleUnresolvedDependency+"]") {
+		t.Errorf("Expected an inline diagnostic for the unresolved field, got:\n%s", out.String())
+	}
+
+	// Syntax errors while editing are reported without touching the file either
+	writeFile("service/service.go", "package service\n\ntype Service struct {\n")
+	now = now.Add(3 * time.Second)
+	watcher.poll(now)
+	watcher.poll(now.Add(2 * time.Second))
+	if current, _ := os.ReadFile(wirePath); !bytes.Equal(current, good) {
+		t.Error("Expected the last good wire_gen.go to be kept on syntax errors")
+	}
+	if !strings.Contains(out.String(), "Syntax errors") {
+		t.Errorf("Expected a syntax error report, got:\n%s", out.String())
+	}
+}
+
+func TestWatcher_RegeneratesStaleOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod":           "module example.com/watch\n\ngo 1.23\n",
+		"config/config.go": "package config\n\ntype Config struct {\n\tComponent struct{}\n}\n",
+	})
+	wirePath := filepath.Join(tmpDir, "wire", "wire_gen.go")
+
+	// A file with the current model but rendered by another iocgen version
+	components, err := ParseComponents(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+	code, err := NewGenerator(components).GenerateCode()
+	if err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	stale := append(append([]byte(nil), code...), "\n// Rendered by an older template\n"...)
+	if err := writeGeneratedFile(filepath.Dir(wirePath), stale); err != nil {
+		t.Fatalf("Failed to write stale file: %v", err)
+	}
+
+	start := func(opts WatchOptions) []byte {
+		t.Helper()
+		opts.Output = &bytes.Buffer{}
+		watcher, err := NewWatcher(tmpDir, opts)
+		if err != nil {
+			t.Fatalf("NewWatcher failed: %v", err)
+		}
+		if err := watcher.start(); err != nil {
+			t.Fatalf("start failed: %v", err)
+		}
+		current, _ := os.ReadFile(wirePath)
+		return current
+	}
+	if current := start(WatchOptions{}); !bytes.Equal(current, code) {
+		t.Errorf("Expected the stale file to be regenerated, got:\n%s", current)
+	}
+
+	// Other generation options are picked up with the same model
+	if current := start(WatchOptions{Roots: []string{"config.Config"}}); !strings.Contains(string(current), "type Roots struct") {
+		t.Errorf("Expected a narrowed Initialize, got:\n%s", current)
+	}
+
+	// The go:generate directive repeats the scan options of the watcher
+	config := filepath.Join(tmpDir, "stereotypes.json")
+	current := start(WatchOptions{Scope: ParseOptions{Exclude: []string{"*_mock.go"}}, StereotypeConfig: config})
+	if !strings.Contains(string(current), "--exclude=*_mock.go --stereotypes=../stereotypes.json") {
+		t.Errorf("Expected the scan options in the go:generate directive, got:\n%s", current)
+	}
+}
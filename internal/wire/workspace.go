@@ -0,0 +1,280 @@
+package wire
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// goModule is a Go module found on disk
+type goModule struct {
+	Path     string            // Module path declared in go.mod
+	Dir      string            // Directory containing go.mod
+	Requires map[string]string // Module paths required by go.mod -> version
+	Replaces map[string]string // Module paths -> replacement directory or path@version
+}
+
+// workspace maps directories to the modules that own them. The main module owns
+// the scanned root directory and receives the generated code. With a go.work file,
+// the other modules it uses are part of the workspace and scanned as well
+type workspace struct {
+	main     *goModule
+	modules  []*goModule // Modules listed in go.work, including the main module
+	workFile string      // Path of the go.work file, empty without a workspace
+
+	mu    sync.Mutex
+	owner map[string]*goModule // Directory -> owning module, filled on demand
+}
+
+// loadWorkspace finds the module owning rootDir and the go.work file governing it.
+// Like the go command, GOWORK=off disables workspaces and GOWORK=path selects a
+// go.work file explicitly
+func loadWorkspace(rootDir string) (*workspace, error) {
+	ws := &workspace{owner: make(map[string]*goModule)}
+
+	mainDir, err := findUp(rootDir, "go.mod")
+	if err != nil {
+		return nil, err
+	}
+	if ws.main, err = ws.moduleAt(mainDir); err != nil {
+		return nil, err
+	}
+
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+	case "":
+		if workDir, err := findUp(mainDir, "go.work"); err == nil {
+			ws.workFile = filepath.Join(workDir, "go.work")
+		}
+	default:
+		ws.workFile = gowork
+	}
+	if ws.workFile == "" {
+		ws.modules = []*goModule{ws.main}
+		return ws, nil
+	}
+
+	content, err := os.ReadFile(ws.workFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, use := range modDirectives(string(content), "use") {
+		dir := filepath.FromSlash(use)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(ws.workFile), dir)
+		}
+		mod, err := ws.moduleAt(filepath.Clean(dir))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ws.workFile, err)
+		}
+		ws.modules = append(ws.modules, mod)
+	}
+
+	for _, mod := range ws.modules {
+		if mod == ws.main {
+			return ws, nil
+		}
+	}
+	return nil, fmt.Errorf("module %s in %s is not one of the workspace modules listed in %s", ws.main.Path, ws.main.Dir, ws.workFile)
+}
+
+// moduleAt reads the go.mod file in dir, once per directory
+func (ws *workspace) moduleAt(dir string) (*goModule, error) {
+	if mod, ok := ws.owner[dir]; ok && mod.Dir == dir {
+		return mod, nil
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	mod := &goModule{Dir: dir, Requires: make(map[string]string), Replaces: make(map[string]string)}
+	if paths := modDirectives(string(content), "module"); len(paths) > 0 {
+		mod.Path = paths[0]
+	}
+	if mod.Path == "" {
+		return nil, fmt.Errorf("%s: missing module directive", filepath.Join(dir, "go.mod"))
+	}
+	for _, req := range modDirectiveFields(string(content), "require") {
+		if len(req) > 1 {
+			mod.Requires[req[0]] = req[1]
+		}
+	}
+	for _, repl := range modDirectiveFields(string(content), "replace") {
+		// old [version] => new [version]
+		if arrow := indexOf(repl, "=>"); arrow > 0 && arrow < len(repl)-1 {
+			target := repl[arrow+1]
+			if arrow+2 < len(repl) {
+				target += "@" + repl[arrow+2]
+			}
+			mod.Replaces[repl[0]] = target
+		}
+	}
+	ws.owner[dir] = mod
+	return mod, nil
+}
+
+// moduleFor returns the module owning dir: the one whose go.mod is closest above
+// it. Nested modules inside another module's tree are therefore mapped correctly
+func (ws *workspace) moduleFor(dir string) (*goModule, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.moduleForLocked(dir)
+}
+
+func (ws *workspace) moduleForLocked(dir string) (*goModule, error) {
+	if mod, ok := ws.owner[dir]; ok {
+		return mod, nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		return ws.moduleAt(dir)
+	}
+	parent := filepath.Dir(dir)
+	if parent == dir {
+		return nil, fmt.Errorf("no go.mod found for %s", dir)
+	}
+	mod, err := ws.moduleForLocked(parent)
+	if err != nil {
+		return nil, err
+	}
+	ws.owner[dir] = mod
+	return mod, nil
+}
+
+// packagePath returns the module owning dir and the import path of the package in it
+func (ws *workspace) packagePath(dir string) (*goModule, string, error) {
+	mod, err := ws.moduleFor(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	rel, err := filepath.Rel(mod.Dir, dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting relative path: %w", err)
+	}
+	return mod, path.Join(mod.Path, filepath.ToSlash(rel)), nil
+}
+
+// externalDirs returns the directories of workspace modules outside rootDir,
+// which are scanned in addition to rootDir
+func (ws *workspace) externalDirs(rootDir string) []string {
+	var dirs []string
+	for _, mod := range ws.modules {
+		if !isWithin(rootDir, mod.Dir) {
+			dirs = append(dirs, mod.Dir)
+		}
+	}
+	return dirs
+}
+
+// contains reports whether dir lies inside rootDir or one of the workspace modules
+func (ws *workspace) contains(rootDir, dir string) bool {
+	if isWithin(rootDir, dir) {
+		return true
+	}
+	for _, mod := range ws.modules {
+		if isWithin(mod.Dir, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// missingRequirements returns the modules of components that the main module
+// does not require. The generated code imports them, so without a require
+// directive it only builds inside the workspace
+func (ws *workspace) missingRequirements(components []Component) []string {
+	missing := make(map[string]bool)
+	for _, comp := range components {
+		mod, err := ws.moduleFor(filepath.Dir(comp.SourceFile))
+		if err != nil || mod == ws.main || ws.main.Requires[mod.Path] != "" {
+			continue
+		}
+		missing[mod.Path] = true
+	}
+	return sortedKeys(missing)
+}
+
+// findUp returns the first directory at or above dir containing the named file
+func findUp(dir, name string) (string, error) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s found in %s or any parent directory: %w", name, dir, os.ErrNotExist)
+		}
+		dir = parent
+	}
+}
+
+// isWithin reports whether path is dir or lies below it
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// modDirectives returns the first argument of every directive with the given verb
+// in a go.mod or go.work file, in both the single line and the block form
+func modDirectives(content, verb string) []string {
+	var args []string
+	for _, fields := range modDirectiveFields(content, verb) {
+		args = append(args, fields[0])
+	}
+	return args
+}
+
+// modDirectiveFields returns the arguments of every directive with the given verb
+func modDirectiveFields(content, verb string) [][]string {
+	var directives [][]string
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var args []string
+		switch {
+		case inBlock && fields[0] == ")":
+			inBlock = false
+		case inBlock:
+			args = fields
+		case fields[0] == verb && len(fields) > 1 && fields[1] == "(":
+			inBlock = true
+		case fields[0] == verb && len(fields) > 1:
+			args = fields[1:]
+		}
+		if len(args) > 0 {
+			for i := range args {
+				args[i] = unquoteModArg(args[i])
+			}
+			directives = append(directives, args)
+		}
+	}
+	return directives
+}
+
+// indexOf returns the index of the first element equal to value, or -1
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// unquoteModArg removes the quotes of a quoted module path or directory
+func unquoteModArg(arg string) string {
+	if unquoted, err := strconv.Unquote(arg); err == nil {
+		return unquoted
+	}
+	return arg
+}
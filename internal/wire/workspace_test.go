@@ -0,0 +1,188 @@
+package wire
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes files given by slash separated paths relative to dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+// workspaceFiles is a go.work workspace where the app module wires a component of the lib module
+var workspaceFiles = map[string]string{
+	"go.work":     "go 1.23\n\nuse (\n\t./app\n\t./lib // shared code\n)\n",
+	"app/go.mod":  "module example.com/app\n\ngo 1.23\n",
+	"lib/go.mod":  "module example.com/lib\n\ngo 1.23\n",
+	"app/main.go": "package main\n\nfunc main() {}\n",
+	"app/service/service.go": `package service
+
+import "example.com/lib/store"
+
+type Service struct {
+	Component struct{}
+	Store     store.Store ` + "`autowired:\"true\"`" + `
+}
+`,
+	"lib/store/store.go": `package store
+
+type Store interface{ Get() string }
+
+type MemoryStore struct {
+	Component  struct{}
+	Implements struct{} ` + "`implements:\"Store\"`" + `
+}
+
+func (s *MemoryStore) Get() string { return "" }
+`,
+}
+
+func TestParseComponents_Workspace(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, workspaceFiles)
+	appDir := filepath.Join(tmpDir, "app")
+
+	components, err := ParseComponents(appDir)
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+
+	packages := make(map[string]string)
+	for _, comp := range components {
+		packages[comp.Type] = comp.Package
+	}
+	expected := map[string]string{
+		"Service":     "example.com/app/service",
+		"MemoryStore": "example.com/lib/store",
+	}
+	if !reflect.DeepEqual(packages, expected) {
+		t.Errorf("Expected packages %v, got %v", expected, packages)
+	}
+
+	code, err := NewGenerator(components).GenerateCode()
+	if err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	if !strings.Contains(string(code), `"example.com/lib/store"`) {
+		t.Errorf("Expected generated code to import example.com/lib/store:\n%s", code)
+	}
+
+	// The app module does not require the lib module yet
+	ws, err := loadWorkspace(appDir)
+	if err != nil {
+		t.Fatalf("loadWorkspace failed: %v", err)
+	}
+	if missing := ws.missingRequirements(components); !reflect.DeepEqual(missing, []string{"example.com/lib"}) {
+		t.Errorf("Expected example.com/lib to be reported as not required, got %v", missing)
+	}
+
+	writeFiles(t, tmpDir, map[string]string{
+		"app/go.mod": "module example.com/app\n\ngo 1.23\n\nrequire example.com/lib v0.0.0\n",
+	})
+	ws, err = loadWorkspace(appDir)
+	if err != nil {
+		t.Fatalf("loadWorkspace failed: %v", err)
+	}
+	if missing := ws.missingRequirements(components); len(missing) != 0 {
+		t.Errorf("Expected no missing requirements, got %v", missing)
+	}
+}
+
+func TestParseComponents_WorkspaceDisabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, workspaceFiles)
+	t.Setenv("GOWORK", "off")
+
+	components, err := ParseComponents(filepath.Join(tmpDir, "app"))
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+	if len(components) != 1 || components[0].Type != "Service" {
+		t.Errorf("Expected only the app module to be scanned, got %v", components)
+	}
+}
+
+func TestParseComponents_NestedModule(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"go.mod":                   "module example.com/app\n\ngo 1.23\n\nrequire (\n\texample.com/plugins v0.0.0\n)\n",
+		"core/core.go":             "package core\n\ntype Core struct {\n\tComponent struct{}\n}\n",
+		"plugins/go.mod":           "module example.com/plugins\n\ngo 1.23\n",
+		"plugins/audit/audit.go":   "package audit\n\ntype Audit struct {\n\tComponent struct{}\n}\n",
+		"plugins/extra/go.mod":     "module \"example.com/extra\"\n\ngo 1.23\n",
+		"plugins/extra/x/extra.go": "package x\n\ntype Extra struct {\n\tComponent struct{}\n}\n",
+	})
+
+	components, err := ParseComponents(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseComponents failed: %v", err)
+	}
+	packages := make(map[string]string)
+	for _, comp := range components {
+		packages[comp.Type] = comp.Package
+	}
+	expected := map[string]string{
+		"Core":  "example.com/app/core",
+		"Audit": "example.com/plugins/audit",
+		"Extra": "example.com/extra/x",
+	}
+	if !reflect.DeepEqual(packages, expected) {
+		t.Errorf("Expected packages %v, got %v", expected, packages)
+	}
+
+	ws, err := loadWorkspace(tmpDir)
+	if err != nil {
+		t.Fatalf("loadWorkspace failed: %v", err)
+	}
+	if missing := ws.missingRequirements(components); !reflect.DeepEqual(missing, []string{"example.com/extra"}) {
+		t.Errorf("Expected only example.com/extra to be reported, got %v", missing)
+	}
+}
+
+func TestLoadWorkspace_ModuleNotInWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"go.work":      "go 1.23\n\nuse ./lib\n",
+		"lib/go.mod":   "module example.com/lib\n",
+		"other/go.mod": "module example.com/other\n",
+	})
+
+	if _, err := loadWorkspace(filepath.Join(tmpDir, "other")); err == nil || !strings.Contains(err.Error(), "not one of the workspace modules") {
+		t.Errorf("Expected an error for a module outside the workspace, got %v", err)
+	}
+}
+
+func TestModDirectives(t *testing.T) {
+	content := `module example.com/app // the app
+
+go 1.23
+
+require example.com/single v1.0.0
+require (
+	example.com/a v1.2.3
+	"example.com/quoted" v0.1.0 // indirect
+)
+
+replace example.com/a => ../a
+`
+	if got := modDirectives(content, "module"); !reflect.DeepEqual(got, []string{"example.com/app"}) {
+		t.Errorf("Expected module example.com/app, got %v", got)
+	}
+	expected := []string{"example.com/single", "example.com/a", "example.com/quoted"}
+	if got := modDirectives(content, "require"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected requires %v, got %v", expected, got)
+	}
+}
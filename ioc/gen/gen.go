@@ -0,0 +1,414 @@
+// Package gen is the Go API of iocgen. It scans a module for components,
+// analyzes them and generates the wiring code, returning every result as a
+// value instead of printing it, so that linters, documentation generators and
+// build scripts can build on it:
+//
+//	project, err := gen.Load(ctx, gen.Options{Dir: "."})
+//	if err != nil {
+//		return err
+//	}
+//	analysis, err := project.Analyze(ctx)
+//	...
+//	files, err := project.Generate(ctx)
+//	for _, file := range files {
+//		if err := file.Write(); err != nil {
+//			return err
+//		}
+//	}
+package gen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/tuhuynh27/go-ioc/internal/wire"
+)
+
+// The component model and the analysis results
+type (
+	Component        = wire.Component
+	Dependency       = wire.Dependency
+	Stats            = wire.ParseStats
+	Analysis         = wire.AnalysisResult
+	Finding          = wire.Finding
+	Graph            = wire.DependencyGraph
+	GraphOptions     = wire.GraphOptions
+	GraphFormat      = wire.GraphFormat
+	Container        = wire.ContainerSpec
+	ModelDrift       = wire.ModelDrift
+	DependencyPath   = wire.DependencyPath
+	RelatedComponent = wire.RelatedComponent
+	ArchViolation    = wire.ArchViolation
+)
+
+// Logger receives progress messages and warnings, *log.Logger implements it
+type Logger = wire.Logger
+
+// Options selects the scanned files and configures generation
+type Options struct {
+	Dir      string   // Module directory, defaults to the current directory
+	Scan     []string // Directories to scan relative to Dir, all of Dir and its workspace when empty
+	Modules  []string // Package patterns of required modules to scan, like github.com/acme/platform/...
+	Include  []string // Glob patterns of files to scan below Dir, all files when empty
+	Exclude  []string // Glob patterns of files and directories to leave out
+	Tags     []string // Build tags that are satisfied when evaluating build constraints
+	GOOS     string   // Target operating system for build constraints, defaults to $GOOS or the host
+	GOARCH   string   // Target architecture for build constraints, defaults to $GOARCH or the host
+	Profiles []string // Active profiles of components with a Profile marker
+	CacheDir string   // Directory of the scan cache, empty disables caching
+	Workers  int      // Number of directories parsed concurrently, defaults to GOMAXPROCS
+
+	Stereotypes      map[string]string // Markers counting as Component mapped to their role, added to the config
+	StereotypeConfig string            // Stereotype config, defaults to .iocgen-stereotypes.json in Dir if present
+
+	Roots      []string // Components Initialize is generated for and unused components are judged from
+	Output     string   // Directory of wire_gen.go without named containers, defaults to <Dir>/wire
+	Manifest   string   // Manifest of named containers, defaults to .iocgen-containers.json in Dir if present
+	Containers []string // Named containers to generate and check, all when empty
+
+	Logger Logger // Destination of progress messages and warnings, discarded when nil
+}
+
+// Project is a scanned module with its components
+type Project struct {
+	Dir        string      // Absolute module directory
+	Components []Component // Components active in the selected profiles
+	Stats      Stats       // Work done by the scan
+
+	opts Options
+}
+
+// File is a generated file that has not been written yet
+type File struct {
+	Path      string // Absolute path of the file
+	Container string // Named container the file wires, empty for the whole project
+	Code      []byte
+}
+
+// Write writes the file, creating its directory if needed
+func (f File) Write() error {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(f.Path, f.Code, 0644)
+}
+
+// CheckResult compares a generated file on disk with the code generated now
+type CheckResult struct {
+	File     File       // The file as it would be generated now
+	UpToDate bool       // Whether the file on disk has the same content
+	Missing  bool       // Whether there is no file on disk
+	Diff     string     // Unified diff from the file on disk to the generated code
+	Drift    ModelDrift // Components added, removed or changed since the file was generated
+}
+
+// Load scans the module for components
+func Load(ctx context.Context, opts Options) (*Project, error) {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	if opts.Logger == nil {
+		opts.Logger = nopLogger{}
+	}
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.loadStereotypes(dir); err != nil {
+		return nil, err
+	}
+
+	components, stats, err := wire.ParseComponentsContext(ctx, dir, opts.parseOptions())
+	if err != nil {
+		return nil, err
+	}
+	return &Project{Dir: dir, Components: components, Stats: stats, opts: opts}, nil
+}
+
+// Analyze runs every analysis over the components, judging unused components
+// from the EntryPoint markers and the roots of the options
+func (p *Project) Analyze(ctx context.Context) (*Analysis, error) {
+	analyzer, err := p.analyzer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return analyzer.PerformComprehensiveAnalysis(), nil
+}
+
+// Graph returns the resolved dependency graph, write it with Graph.Write
+func (p *Project) Graph(ctx context.Context, opts GraphOptions) (*Graph, error) {
+	analyzer, err := p.analyzer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return analyzer.BuildGraph(opts)
+}
+
+// Paths returns every dependency path from one component to another, named
+// like in iocgen why by type, package.Type, full type or interface
+func (p *Project) Paths(ctx context.Context, from, to string) ([]DependencyPath, error) {
+	analyzer, err := p.analyzer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return analyzer.FindPaths(from, to)
+}
+
+// Related returns the dependencies of a component, or its dependents when
+// reverse is set, optionally including the transitive ones
+func (p *Project) Related(ctx context.Context, ref string, reverse, transitive bool) ([]RelatedComponent, error) {
+	analyzer, err := p.analyzer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return analyzer.FindRelated(ref, reverse, transitive)
+}
+
+// CheckArchitecture checks the components against the rules of a rules file
+func (p *Project) CheckArchitecture(ctx context.Context, rulesFile string) ([]ArchViolation, error) {
+	rules, err := wire.LoadArchRules(rulesFile)
+	if err != nil {
+		return nil, err
+	}
+	analyzer, err := p.analyzer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return analyzer.CheckArchitecture(rules), nil
+}
+
+// Generate renders wire_gen.go without writing it: one file per named
+// container if the project declares any, otherwise one for all components
+func (p *Project) Generate(ctx context.Context) ([]File, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	targets, err := p.targets()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	for _, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		code, err := target.generator.GenerateCode()
+		if err != nil {
+			if target.container != "" {
+				return nil, fmt.Errorf("container %s: %w", target.container, err)
+			}
+			return nil, err
+		}
+		files = append(files, File{Path: filepath.Join(target.outputDir, "wire_gen.go"), Container: target.container, Code: code})
+	}
+	return files, nil
+}
+
+// Check compares the generated files on disk with the code generated now
+func (p *Project) Check(ctx context.Context) ([]CheckResult, error) {
+	files, err := p.Generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []CheckResult
+	for _, file := range files {
+		result := CheckResult{File: file}
+		committed, err := os.ReadFile(file.Path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			result.Missing = true
+		case err != nil:
+			return nil, err
+		default:
+			result.UpToDate = bytes.Equal(committed, file.Code)
+		}
+
+		if !result.UpToDate {
+			rel, err := filepath.Rel(p.Dir, file.Path)
+			if err != nil {
+				rel = file.Path
+			}
+			rel = filepath.ToSlash(rel)
+			result.Diff = wire.UnifiedDiff("a/"+rel, "b/"+rel, committed, file.Code)
+
+			_, current, _ := wire.ParseModelHeader(file.Code)
+			_, previous, _ := wire.ParseModelHeader(committed)
+			result.Drift = wire.CompareModels(previous, current)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Mocks renders call-recording fakes of the interfaces named in implements
+// tags into outputDir, relative to the module directory. The fakes are active
+// in the test profile
+func (p *Project) Mocks(ctx context.Context, outputDir string) (File, error) {
+	if err := ctx.Err(); err != nil {
+		return File{}, err
+	}
+	opts := wire.MockOptions{Scope: p.opts.parseOptions(), OutputDir: outputDir}
+	code, err := wire.GenerateMockCode(p.Dir, p.Components, opts)
+	if err != nil {
+		return File{}, err
+	}
+	if !filepath.IsAbs(outputDir) {
+		outputDir = filepath.Join(p.Dir, outputDir)
+	}
+	return File{Path: filepath.Join(outputDir, "mocks_gen.go"), Code: code}, nil
+}
+
+// loadStereotypes adds the markers of the stereotype config to the options
+func (opts *Options) loadStereotypes(dir string) error {
+	config, explicit := opts.StereotypeConfig, opts.StereotypeConfig != ""
+	if !explicit {
+		config = filepath.Join(dir, wire.DefaultStereotypeConfig)
+	} else if !filepath.IsAbs(config) {
+		config = filepath.Join(dir, config)
+	}
+	stereotypes, err := wire.LoadStereotypeConfig(config)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !explicit {
+			return nil
+		}
+		return err
+	}
+
+	merged := make(map[string]string, len(stereotypes)+len(opts.Stereotypes))
+	for marker, role := range stereotypes {
+		merged[marker] = role
+	}
+	for marker, role := range opts.Stereotypes {
+		merged[marker] = role
+	}
+	opts.Stereotypes = merged
+	return nil
+}
+
+// parseOptions converts the options to the options of the scanner
+func (opts Options) parseOptions() wire.ParseOptions {
+	return wire.ParseOptions{
+		CacheDir: opts.CacheDir,
+		Workers:  opts.Workers,
+		Roots:    opts.Scan,
+		Modules:  opts.Modules,
+		Include:  opts.Include,
+		Exclude:  opts.Exclude,
+		Tags:     opts.Tags,
+		GOOS:     opts.GOOS,
+		GOARCH:   opts.GOARCH,
+		Profiles: opts.Profiles,
+		Logger:   opts.Logger,
+
+		Stereotypes: opts.Stereotypes,
+	}
+}
+
+// analyzer returns an analyzer of the components with the roots of the options
+func (p *Project) analyzer(ctx context.Context) (*wire.DependencyAnalyzer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	analyzer := wire.NewAnalyzer(p.Components)
+	if err := analyzer.SetRoots(p.opts.Roots); err != nil {
+		return nil, err
+	}
+	return analyzer, nil
+}
+
+// target is a generated file with the generator rendering it
+type target struct {
+	container string
+	outputDir string
+	generator *wire.Generator
+}
+
+// targets returns one target per selected named container, or the wire
+// package of the whole project when there are none
+func (p *Project) targets() ([]target, error) {
+	manifest, explicit := p.opts.Manifest, p.opts.Manifest != ""
+	if !explicit {
+		manifest = filepath.Join(p.Dir, wire.DefaultContainerManifest)
+	} else if !filepath.IsAbs(manifest) {
+		manifest = filepath.Join(p.Dir, manifest)
+	}
+	declared, err := wire.LoadContainerManifest(manifest)
+	if err != nil && !(errors.Is(err, fs.ErrNotExist) && !explicit) {
+		return nil, err
+	}
+	specs, err := wire.CollectContainers(declared, p.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(specs) == 0 {
+		if len(p.opts.Containers) > 0 {
+			return nil, fmt.Errorf("no named containers are declared")
+		}
+		outputDir := filepath.Join(p.Dir, "wire")
+		gen := wire.NewGenerator(p.Components)
+		if p.opts.Output != "" {
+			outputDir = p.opts.Output
+			if !filepath.IsAbs(outputDir) {
+				outputDir = filepath.Join(p.Dir, outputDir)
+			}
+			gen.ForOutput(p.Dir, outputDir)
+		} else if len(p.opts.Profiles) > 0 {
+			return nil, fmt.Errorf("profiles need an output directory, the application's wire_gen.go would be replaced")
+		}
+		gen, err := gen.ForProfiles(p.opts.Profiles).ForRoots(p.opts.Roots)
+		if err != nil {
+			return nil, err
+		}
+		return []target{{outputDir: outputDir, generator: p.recordScan(gen, outputDir)}}, nil
+	}
+
+	if len(p.opts.Roots) > 0 || len(p.opts.Profiles) > 0 {
+		return nil, fmt.Errorf("roots and profiles cannot be combined with named containers")
+	}
+	specs, err = wire.SelectContainers(specs, p.opts.Containers)
+	if err != nil {
+		return nil, err
+	}
+	var targets []target
+	for _, spec := range specs {
+		selected, err := wire.ContainerComponents(p.Components, spec)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target{
+			container: spec.Name,
+			outputDir: spec.OutputDir(p.Dir),
+			generator: p.recordScan(wire.NewGenerator(selected).ForContainer(p.Dir, spec), spec.OutputDir(p.Dir)),
+		})
+	}
+	return targets, nil
+}
+
+// recordScan makes the go:generate directive of the generator writing into
+// outputDir repeat the scan options, so that go generate scans the same files
+func (p *Project) recordScan(gen *wire.Generator, outputDir string) *wire.Generator {
+	config := p.opts.StereotypeConfig
+	if config != "" {
+		if !filepath.IsAbs(config) {
+			config = filepath.Join(p.Dir, config)
+		}
+		if rel, err := filepath.Rel(outputDir, config); err == nil {
+			config = rel
+		}
+		config = filepath.ToSlash(config)
+	}
+	return gen.ForScan(p.opts.parseOptions(), config)
+}
+
+// nopLogger discards all messages
+type nopLogger struct{}
+
+func (nopLogger) Printf(string, ...interface{}) {}
@@ -0,0 +1,195 @@
+package gen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var appFiles = map[string]string{
+	"go.mod": "module example.com/app\n\ngo 1.23\n",
+	"store/store.go": `package store
+
+type Store struct {
+	Component struct{}
+}
+`,
+	"service/service.go": `package service
+
+import "example.com/app/store"
+
+type Service struct {
+	Component struct{}
+	Store     *store.Store ` + "`autowired:\"true\"`" + `
+}
+`,
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+// recorder is a Logger collecting its messages
+type recorder struct {
+	messages []string
+}
+
+func (r *recorder) Printf(format string, args ...interface{}) {
+	r.messages = append(r.messages, fmt.Sprintf(format, args...))
+}
+
+func TestLoad_GenerateMatchesGolden(t *testing.T) {
+	fixture := filepath.Join("..", "..", "internal", "wire", "testdata", "golden", "lifecycle")
+	project, err := Load(context.Background(), Options{Dir: fixture})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(project.Components) != 3 || project.Stats.Files == 0 {
+		t.Errorf("Expected 3 components from a fresh scan, got %d and %+v", len(project.Components), project.Stats)
+	}
+
+	files, err := project.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != filepath.Join(project.Dir, "wire", "wire_gen.go") {
+		t.Fatalf("Expected wire/wire_gen.go, got %+v", files)
+	}
+	golden, err := os.ReadFile(filepath.Join(fixture, "wire_gen.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if string(files[0].Code) != string(golden) {
+		t.Errorf("Generated code differs from the golden file")
+	}
+}
+
+func TestProject_GenerateRoots(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, appFiles)
+	ctx := context.Background()
+
+	project, err := Load(ctx, Options{Dir: dir, Roots: []string{"store.Store"}})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	files, err := project.Generate(ctx)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	code := string(files[0].Code)
+	if !strings.Contains(code, "type Roots struct") || strings.Contains(code, "service.Service") {
+		t.Errorf("Expected the roots to narrow Initialize:\n%s", code)
+	}
+
+	// The same roots are the entry points of the analysis
+	analysis, err := project.Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(analysis.UnusedComponents) != 1 || analysis.UnusedComponents[0].Type != "Service" {
+		t.Errorf("Expected the service to be unreachable from the roots, got %+v", analysis.UnusedComponents)
+	}
+}
+
+func TestLoad_Canceled(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, appFiles)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Load(ctx, Options{Dir: dir}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestProject_Check(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, appFiles)
+	ctx := context.Background()
+
+	project, err := Load(ctx, Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	results, err := project.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if len(results) != 1 || !results[0].Missing || results[0].UpToDate {
+		t.Fatalf("Expected a missing file, got %+v", results)
+	}
+	if err := results[0].File.Write(); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	results, err = project.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !results[0].UpToDate || results[0].Diff != "" {
+		t.Errorf("Expected the written file to be up to date, got %+v", results[0])
+	}
+
+	writeFiles(t, dir, map[string]string{"cache/cache.go": "package cache\n\ntype Cache struct {\n\tComponent struct{}\n}\n"})
+	project, err = Load(ctx, Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	results, err = project.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	result := results[0]
+	if result.UpToDate || !strings.Contains(result.Diff, "+++ b/wire/wire_gen.go") {
+		t.Errorf("Expected a diff of wire/wire_gen.go, got %q", result.Diff)
+	}
+	if len(result.Drift.Added) != 1 || result.Drift.Added[0] != "example.com/app/cache.Cache" {
+		t.Errorf("Expected the cache component to be added, got %+v", result.Drift)
+	}
+}
+
+func TestProject_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, appFiles)
+	writeFiles(t, dir, map[string]string{"store/store.go": "package store\n\ntype Store struct{}\n"})
+	ctx := context.Background()
+
+	logger := &recorder{}
+	project, err := Load(ctx, Options{Dir: dir, Logger: logger})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, err := project.Generate(ctx); err == nil || !strings.Contains(err.Error(), "Store") {
+		t.Errorf("Expected the unresolved dependency as an error, got %v", err)
+	}
+
+	project.opts.Profiles = []string{"test"}
+	if _, err := project.Generate(ctx); err == nil || !strings.Contains(err.Error(), "output directory") {
+		t.Errorf("Expected profiles without an output directory to fail, got %v", err)
+	}
+
+	analysis, err := project.Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if analysis.TotalComponents != 1 {
+		t.Errorf("Expected the analysis to cover the service, got %d components", analysis.TotalComponents)
+	}
+	if len(logger.messages) == 0 || !strings.Contains(logger.messages[0], "Found 1 components") {
+		t.Errorf("Expected the scan to be reported to the logger, got %v", logger.messages)
+	}
+}
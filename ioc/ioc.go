@@ -0,0 +1,56 @@
+// Package ioc provides the typed markers iocgen recognizes on component
+// structs. Embed a marker, or declare it as a field of any name, and configure
+// it with the same struct tags as the untyped struct{} markers:
+//
+//	type EmailSender struct {
+//		ioc.Component
+//		ioc.Qualifier `value:"email"`
+//		ioc.Primary
+//		Logger logger.Logger `autowired:"true"`
+//	}
+//
+// Markers are empty structs, so they add no data or methods to a component
+package ioc
+
+// Component marks a struct as a component. Tags: name overrides the component
+// name, implements names an implemented interface, ignore and reason suppress
+// analyzer findings
+type Component struct{}
+
+// Qualifier distinguishes implementations of the same interface, its value tag
+// is matched against the qualifier tag of autowired fields
+type Qualifier struct{}
+
+// Primary prefers the component over the other implementations of its
+// interfaces that have the same qualifier
+type Primary struct{}
+
+// Profile makes the component active only in the comma-separated profiles of
+// its value tag, like `value:"local,test"`
+type Profile struct{}
+
+// EntryPoint marks an application root, such as a server or a worker. Its value
+// tag names the containers the component is a root of
+type EntryPoint struct{}
+
+// Module declares a container module named by its value tag. The struct
+// carrying it is a module declaration instead of a component
+type Module struct{}
+
+// Exports lists the comma-separated components a module exposes to other
+// modules in its value tag
+type Exports struct{}
+
+// Stereotype declares the struct carrying it as a stereotype marker that
+// counts as Component, like Spring's @Service. Its value tag is the role of the
+// components embedding the stereotype, the lower-cased type name by default:
+//
+//	type Service struct {
+//		ioc.Stereotype `value:"service"`
+//	}
+//
+//	type UserService struct {
+//		stereotype.Service
+//		Repo UserRepository `autowired:"true"`
+//	}
+type Stereotype struct{}
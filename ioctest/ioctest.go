@@ -0,0 +1,214 @@
+// Package ioctest builds single components with their real or faked
+// dependencies in unit tests, without the whole container.
+//
+// The components are wired by constructors that iocgen generates, so no
+// reflection is involved. Generate them with
+//
+//	iocgen ioctest
+//
+// and import the generated package for its side effects in the test:
+//
+//	import _ "example.com/app/wire/wiretest"
+//
+//	func TestNotificationService(t *testing.T) {
+//		log := &mocks.FakeLogger{}
+//		svc := ioctest.Build(t, &notification.NotificationService{}, ioctest.Fake[logger.Logger](log))
+//		...
+//	}
+//
+// The generated package imports every component, so it can only be imported
+// from external test packages. For tests inside a component package, generate
+// the constructors into the packages themselves with
+//
+//	iocgen ioctest --in-package
+package ioctest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Constructor wires components of type T. Its values are generated by iocgen
+// ioctest and registered with Register
+type Constructor[T any] struct {
+	New           func(r *Resolver) *T    // Constructor function of the component, nil for struct literals
+	Inject        func(c *T, r *Resolver) // Sets the autowired fields, nil for components with a constructor function
+	PostConstruct func(c *T)              // PostConstruct method, nil if the component has none
+	PreDestroy    func(c *T)              // PreDestroy method, nil if the component has none
+}
+
+var (
+	mu           sync.Mutex
+	constructors []any // Constructor[T] of every registered component type
+)
+
+// Register makes a component type buildable. It is called by generated code.
+// A later constructor of the same type replaces the earlier one, so the package
+// generated for the whole module wins over the constructors generated into the
+// package under test, which may leave out some real dependencies
+func Register[T any](c Constructor[T]) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i, registered := range constructors {
+		if _, ok := registered.(Constructor[T]); ok {
+			constructors[i] = c
+			return
+		}
+	}
+	constructors = append(constructors, c)
+}
+
+// lookup returns the registered constructor of T
+func lookup[T any]() (Constructor[T], bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, c := range constructors {
+		if c, ok := c.(Constructor[T]); ok {
+			return c, true
+		}
+	}
+	return Constructor[T]{}, false
+}
+
+// Option configures the dependencies of a built component
+type Option func(r *Resolver)
+
+// Fake injects impl into every dependency of type T, whatever its qualifier.
+// T is usually an interface, or a component pointer type like *store.Store
+func Fake[T any](impl T) Option {
+	return func(r *Resolver) {
+		r.fakes = append(r.fakes, &fake[T]{impl: impl})
+	}
+}
+
+// FakeQualified injects impl into the dependencies of type T with the qualifier.
+// It takes precedence over a Fake of the same type
+func FakeQualified[T any](qualifier string, impl T) Option {
+	return func(r *Resolver) {
+		r.fakes = append(r.fakes, &fake[T]{impl: impl, qualifier: qualifier, qualified: true})
+	}
+}
+
+// replacement is a fake of any type
+type replacement interface {
+	unused() string // Describes the fake if it was not injected, empty otherwise
+}
+
+// fake is a replacement of the dependencies of type T
+type fake[T any] struct {
+	impl      T
+	qualifier string
+	qualified bool
+	used      bool
+}
+
+// Resolver supplies the dependencies of the components of one Build: fakes
+// where given, and real components otherwise. Real components are built once
+// per Build and shared by everything depending on them
+type Resolver struct {
+	t         testing.TB
+	fakes     []replacement // Fakes of the options
+	instances []any         // *T of the real components built so far
+}
+
+// Build injects the dependencies of component, calls its PostConstruct method
+// and registers its PreDestroy method with t.Cleanup. Dependencies without a
+// fake are real components built the same way. A component with a constructor
+// function is created by it, Build then returns the new instance instead.
+// The test fails if T has no generated constructor or a fake is never injected
+func Build[T any](t testing.TB, component *T, opts ...Option) *T {
+	t.Helper()
+	r := &Resolver{t: t}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	component = build(r, component)
+	for _, f := range r.fakes {
+		if unused := f.unused(); unused != "" {
+			t.Errorf("ioctest: %s was not injected into any dependency", unused)
+		}
+	}
+	return component
+}
+
+// unused describes the fake if no dependency received it
+func (f *fake[T]) unused() string {
+	if f.used {
+		return ""
+	}
+	if f.qualified {
+		return fmt.Sprintf("fake %T with qualifier %q", f.impl, f.qualifier)
+	}
+	return fmt.Sprintf("fake %T", f.impl)
+}
+
+// Resolve returns the fake for a dependency of type T with the qualifier, or
+// the result of real when there is none. It is called by generated code
+func Resolve[T any](r *Resolver, qualifier string, real func() T) T {
+	var fallback *fake[T]
+	for _, f := range r.fakes {
+		f, ok := f.(*fake[T])
+		if !ok {
+			continue
+		}
+		if f.qualified && f.qualifier == qualifier {
+			f.used = true
+			return f.impl
+		}
+		if !f.qualified && fallback == nil {
+			fallback = f
+		}
+	}
+	if fallback != nil {
+		fallback.used = true
+		return fallback.impl
+	}
+	return real()
+}
+
+// Real returns the real component of type T of this Build, building it on
+// first use. It is called by generated code
+func Real[T any](r *Resolver) *T {
+	for _, instance := range r.instances {
+		if instance, ok := instance.(*T); ok {
+			return instance
+		}
+	}
+	instance := build(r, new(T))
+	r.instances = append(r.instances, instance)
+	return instance
+}
+
+// Unavailable fails the test for a dependency of type T whose real component
+// cannot be built in the package under test because its package imports it. It
+// is called by constructors generated with iocgen ioctest --in-package
+func Unavailable[T any](r *Resolver, component string) T {
+	r.t.Helper()
+	r.t.Fatalf("ioctest: the package of %s imports the package under test, fake it or build from an external test package", component)
+	var zero T
+	return zero
+}
+
+// build wires a component with the registered constructor of its type
+func build[T any](r *Resolver, component *T) *T {
+	r.t.Helper()
+	c, ok := lookup[T]()
+	if !ok {
+		r.t.Fatalf("ioctest: no constructor is registered for %T, run iocgen ioctest and import the generated package", component)
+	}
+
+	if c.New != nil {
+		component = c.New(r)
+	} else if c.Inject != nil {
+		c.Inject(component, r)
+	}
+	if c.PostConstruct != nil {
+		c.PostConstruct(component)
+	}
+	if c.PreDestroy != nil {
+		r.t.Cleanup(func() { c.PreDestroy(component) })
+	}
+	return component
+}
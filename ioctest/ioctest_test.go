@@ -0,0 +1,172 @@
+package ioctest
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Components and constructors as iocgen ioctest would generate them
+
+type Logger interface {
+	Log(msg string)
+}
+
+type ConsoleLogger struct {
+	lines []string
+}
+
+func (l *ConsoleLogger) Log(msg string) { l.lines = append(l.lines, msg) }
+
+// events records the lifecycle methods called on stores
+var events []string
+
+type Store struct {
+	Logger Logger
+}
+
+func (s *Store) PostConstruct() { events = append(events, "store started") }
+func (s *Store) PreDestroy()    { events = append(events, "store stopped") }
+
+type Service struct {
+	Store  *Store
+	Audit  Logger
+	Logger Logger
+}
+
+type Client struct {
+	Store *Store
+}
+
+func NewClient(store *Store) *Client { return &Client{Store: store} }
+
+func init() {
+	Register(Constructor[ConsoleLogger]{})
+	Register(Constructor[Store]{
+		Inject: func(c *Store, r *Resolver) {
+			c.Logger = Resolve(r, "", func() Logger { return Real[ConsoleLogger](r) })
+		},
+		PostConstruct: (*Store).PostConstruct,
+		PreDestroy:    (*Store).PreDestroy,
+	})
+	Register(Constructor[Service]{
+		Inject: func(c *Service, r *Resolver) {
+			c.Store = Resolve(r, "", func() *Store { return Real[Store](r) })
+			c.Audit = Resolve(r, "audit", func() Logger { return Real[ConsoleLogger](r) })
+			c.Logger = Resolve(r, "", func() Logger { return Real[ConsoleLogger](r) })
+		},
+	})
+	Register(Constructor[Client]{
+		New: func(r *Resolver) *Client {
+			return NewClient(Resolve(r, "", func() *Store { return Real[Store](r) }))
+		},
+	})
+}
+
+// recorder captures the errors a Build reports
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestBuild_RealDependencies(t *testing.T) {
+	svc := Build(t, &Service{})
+	if svc.Store == nil || svc.Store.Logger == nil {
+		t.Fatalf("Expected a real store with a logger, got %+v", svc.Store)
+	}
+	// Real components are shared within one Build
+	if svc.Audit != svc.Logger || svc.Store.Logger != svc.Logger {
+		t.Error("Expected one console logger for every dependency")
+	}
+	if other := Build(t, &Service{}); other.Store == svc.Store {
+		t.Error("Expected every Build to create its own real components")
+	}
+}
+
+func TestBuild_Fakes(t *testing.T) {
+	audit := &ConsoleLogger{}
+	logger := &ConsoleLogger{}
+
+	svc := Build(t, &Service{}, Fake[Logger](logger), FakeQualified[Logger]("audit", audit))
+	if svc.Audit != Logger(audit) {
+		t.Errorf("Expected the qualified fake for the audit logger, got %v", svc.Audit)
+	}
+	if svc.Logger != Logger(logger) || svc.Store.Logger != Logger(logger) {
+		t.Error("Expected the unqualified fake for every other logger")
+	}
+
+	store := &Store{}
+	if svc := Build(t, &Service{}, Fake[*Store](store)); svc.Store != store {
+		t.Errorf("Expected the faked store, got %+v", svc.Store)
+	}
+
+	rec := &recorder{TB: t}
+	Build(rec, &Service{}, FakeQualified[Logger]("metrics", logger), Fake[*ConsoleLogger](logger))
+	expected := []string{
+		`ioctest: fake *ioctest.ConsoleLogger with qualifier "metrics" was not injected into any dependency`,
+		"ioctest: fake *ioctest.ConsoleLogger was not injected into any dependency",
+	}
+	if !reflect.DeepEqual(rec.errors, expected) {
+		t.Errorf("Expected errors %q, got %q", expected, rec.errors)
+	}
+}
+
+func TestBuild_Lifecycle(t *testing.T) {
+	events = nil
+	t.Run("build", func(t *testing.T) {
+		// The client is created by its constructor function instead of the given instance
+		given := &Client{}
+		client := Build(t, given, Fake[Logger](&ConsoleLogger{}))
+		if client == given || client.Store == nil {
+			t.Errorf("Expected a client created by NewClient, got %+v", client)
+		}
+		if !reflect.DeepEqual(events, []string{"store started"}) {
+			t.Errorf("Expected PostConstruct to run when building, got %v", events)
+		}
+	})
+	if !reflect.DeepEqual(events, []string{"store started", "store stopped"}) {
+		t.Errorf("Expected PreDestroy to run on cleanup, got %v", events)
+	}
+}
+
+// Report is built by constructors generated into its own package, whose
+// logger lives in a package importing it
+type Report struct {
+	Logger Logger
+}
+
+func TestBuild_Unavailable(t *testing.T) {
+	Register(Constructor[Report]{
+		Inject: func(c *Report, r *Resolver) {
+			c.Logger = Resolve(r, "", func() Logger { return Unavailable[Logger](r, "example.com/app/audit.AuditLogger") })
+		},
+	})
+	if report := Build(t, &Report{}, Fake[Logger](&ConsoleLogger{})); report.Logger == nil {
+		t.Error("Expected the fake to replace the unavailable logger")
+	}
+
+	rec := &recorder{TB: t}
+	Build(rec, &Report{})
+	expected := []string{"ioctest: the package of example.com/app/audit.AuditLogger imports the package under test, fake it or build from an external test package"}
+	if !reflect.DeepEqual(rec.errors, expected) {
+		t.Errorf("Expected errors %q, got %q", expected, rec.errors)
+	}
+
+	// The constructors of the whole module replace those of the package
+	Register(Constructor[Report]{
+		Inject: func(c *Report, r *Resolver) {
+			c.Logger = Resolve(r, "", func() Logger { return Real[ConsoleLogger](r) })
+		},
+	})
+	if report := Build(t, &Report{}); report.Logger == nil {
+		t.Error("Expected the real logger of the later constructor")
+	}
+}